@@ -26,8 +26,10 @@ var (
 	outputFile        = flag.String("o", "", "Output file")
 	optimizeLevel     = flag.Int("O", 0, "Optimization level (0-3)")
 	debugInfo         = flag.Bool("g", false, "Generate debug information")
-	targetTriple      = flag.String("target", "", "Target triple for code generation")
+	targetTriple      = flag.String("target", "", "Target triple for code generation (default: host)")
 	warningsAsErrors  = flag.Bool("Werror", false, "Treat warnings as errors")
+	boundsChecks      = flag.Bool("bounds-check", false, "Check array indices at runtime (default on below -O2)")
+	divisionChecks    = flag.Bool("div-check", false, "Check integer divisors against zero at runtime")
 	verbose           = flag.Bool("v", false, "Verbose output")
 	showVersion       = flag.Bool("version", false, "Show version information")
 	showHelp          = flag.Bool("h", false, "Show this help message")
@@ -75,6 +77,14 @@ func main() {
 		}
 	}
 
+	// Bounds checks follow the optimization level unless set explicitly
+	checkBounds := domain.DefaultBoundsChecks(*optimizeLevel)
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "bounds-check" {
+			checkBounds = *boundsChecks
+		}
+	})
+
 	// Create compiler configuration
 	config := application.CompilerConfig{
 		UseMockComponents: *useMockComponents,
@@ -86,6 +96,8 @@ func main() {
 			TargetTriple:      *targetTriple,
 			OutputPath:        output,
 			WarningsAsErrors:  *warningsAsErrors,
+			BoundsChecks:      checkBounds,
+			DivisionChecks:    *divisionChecks,
 		},
 		ErrorOutput: os.Stderr,
 		Verbose:     *verbose,
@@ -227,6 +239,10 @@ func printUsage() {
 	fmt.Printf("  %s -i \"main.sl,lib.sl\" -o program.ll -O 2\n", os.Args[0])
 	fmt.Printf("\n  # Compile with debug info and warnings as errors\n")
 	fmt.Printf("  %s -i main.sl -o main.ll -g -Werror\n", os.Args[0])
+	fmt.Printf("\n  # Keep array bounds checks in an optimized build\n")
+	fmt.Printf("  %s -i main.sl -o main.ll -O 2 -bounds-check\n", os.Args[0])
+	fmt.Printf("\n  # Report integer division by zero with its source location\n")
+	fmt.Printf("  %s -i main.sl -o main.ll -div-check\n", os.Args[0])
 	fmt.Printf("\n  # Use mock components for testing\n")
 	fmt.Printf("  %s -i main.sl -o main.ll -mock -v\n", os.Args[0])
 }
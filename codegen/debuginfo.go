@@ -0,0 +1,200 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sokoide/llvm5/internal/domain"
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// debugProducer identifies the compiler in the compile unit
+const debugProducer = "StaticLang"
+
+// beginDebugInfo creates the compile unit of a program when debug
+// information is enabled
+func (g *Generator) beginDebugInfo(prog *domain.Program) {
+	if !g.debugInfo {
+		return
+	}
+
+	filename := prog.Location.Start.Filename
+	if filename == "" && len(prog.Declarations) > 0 {
+		filename = prog.Declarations[0].GetLocation().Start.Filename
+	}
+	directory, _ := os.Getwd()
+	if filepath.IsAbs(filename) {
+		directory = filepath.Dir(filename)
+		filename = filepath.Base(filename)
+	}
+
+	g.di = g.module.CreateDIBuilder()
+	g.di.CreateCompileUnit(filename, directory, debugProducer, g.optimization > 0)
+	g.diFile = g.di.CreateFile(filename, directory)
+	g.diTypes = make(map[string]interfaces.LLVMMetadata)
+}
+
+// beginFunctionDebugInfo attaches a subprogram to a function definition and
+// makes it the scope of the locations that follow
+func (g *Generator) beginFunctionDebugInfo(node *domain.FunctionDecl, fn interfaces.LLVMFunction) {
+	if g.di == nil {
+		return
+	}
+
+	types := []interfaces.LLVMMetadata{g.debugType(node.ReturnType)}
+	for _, param := range node.Parameters {
+		types = append(types, g.debugType(param.Type))
+	}
+	line := node.Location.Start.Line
+	subprogram := g.di.CreateFunction(g.diFile, node.Name, g.diFile, line, g.di.CreateSubroutineType(types), g.optimization > 0)
+	g.di.SetSubprogram(fn, subprogram)
+	g.diScope = subprogram
+	g.emitLocation(node)
+}
+
+// endFunctionDebugInfo stops attaching locations after a function definition
+func (g *Generator) endFunctionDebugInfo() {
+	if g.di == nil {
+		return
+	}
+	g.diScope = nil
+	g.builder.SetCurrentDebugLocation(nil)
+}
+
+// enterScope opens a lexical block for a nested block statement, returning
+// the enclosing scope to restore
+func (g *Generator) enterScope(node *domain.BlockStmt) interfaces.LLVMMetadata {
+	outer := g.diScope
+	if g.di != nil && g.diScope != nil && node != g.functionBody {
+		start := node.Location.Start
+		g.diScope = g.di.CreateLexicalBlock(g.diScope, g.diFile, start.Line, start.Column)
+	}
+	return outer
+}
+
+// emitLocation attaches the start of a node to the instructions generated next
+func (g *Generator) emitLocation(node domain.Node) {
+	if g.di == nil || g.diScope == nil {
+		return
+	}
+	start := node.GetLocation().Start
+	if start.Line == 0 {
+		return
+	}
+	g.builder.SetCurrentDebugLocation(g.di.CreateDebugLocation(start.Line, start.Column, g.diScope))
+}
+
+// declareVariable describes the variable stored in slot. argNo is the
+// 1-based position of a parameter, 0 for a local variable.
+func (g *Generator) declareVariable(name string, t domain.Type, slot interfaces.LLVMValue, location domain.SourceRange, argNo int) {
+	if g.di == nil || g.diScope == nil {
+		return
+	}
+
+	line := location.Start.Line
+	var variable interfaces.LLVMMetadata
+	if argNo > 0 {
+		variable = g.di.CreateParameterVariable(g.diScope, name, argNo, g.diFile, line, g.debugType(t))
+	} else {
+		variable = g.di.CreateAutoVariable(g.diScope, name, g.diFile, line, g.debugType(t))
+	}
+	debugLocation := g.di.CreateDebugLocation(line, location.Start.Column, g.diScope)
+	g.di.InsertDeclareAtEnd(slot, variable, debugLocation, g.builder.GetInsertBlock())
+}
+
+// debugType returns the debug type describing a source type, nil for void
+func (g *Generator) debugType(t domain.Type) interfaces.LLVMMetadata {
+	if t == nil || isVoidType(t) {
+		return nil
+	}
+
+	key := t.String()
+	if cached, ok := g.diTypes[key]; ok {
+		return cached
+	}
+
+	var result interfaces.LLVMMetadata
+	switch typ := t.(type) {
+	case *domain.BasicType:
+		result = g.debugBasicType(typ)
+	case *domain.ArrayType:
+		element := g.debugType(typ.ElementType)
+		if typ.Size == -1 {
+			// Dynamic arrays point to a {len, data} header
+			pointerBits, pointerAlign := g.typeLayout(g.module.PointerType(nil))
+			lenBits, lenAlign := g.typeLayout(g.module.IntType(64))
+			size, align := g.typeLayout(g.dynamicArrayHeader())
+			header := g.di.CreateStructType(g.diFile, key, g.diFile, 0, size, align)
+			g.di.SetStructMembers(header, []interfaces.LLVMMetadata{
+				g.di.CreateMemberType(header, "len", g.diFile, 0, lenBits, lenAlign, 0, g.debugType(&domain.BasicType{Kind: domain.IntType})),
+				g.di.CreateMemberType(header, "data", g.diFile, 0, pointerBits, pointerAlign, alignTo(lenBits, pointerAlign), g.di.CreatePointerType(element, pointerBits)),
+			})
+			result = g.di.CreatePointerType(header, pointerBits)
+		} else {
+			size, align := g.debugLayout(typ)
+			result = g.di.CreateArrayType(element, typ.Size, size, align)
+		}
+	case *domain.StructType:
+		size, align := g.debugLayout(typ)
+		structType := g.di.CreateStructType(g.diFile, typ.Name, g.diFile, 0, size, align)
+		g.diTypes[key] = structType
+
+		members := make([]interfaces.LLVMMetadata, len(typ.Order))
+		offset := 0
+		for i, fieldName := range typ.Order {
+			fieldType := typ.Fields[fieldName]
+			fieldSize, fieldAlign := g.debugLayout(fieldType)
+			offset = alignTo(offset, fieldAlign)
+			members[i] = g.di.CreateMemberType(structType, fieldName, g.diFile, 0, fieldSize, fieldAlign, offset, g.debugType(fieldType))
+			offset += fieldSize
+		}
+		g.di.SetStructMembers(structType, members)
+		result = structType
+	default:
+		return nil
+	}
+
+	g.diTypes[key] = result
+	return result
+}
+
+// debugBasicType describes a builtin type
+func (g *Generator) debugBasicType(t *domain.BasicType) interfaces.LLVMMetadata {
+	sizeBits := t.GetSize() * 8
+	switch {
+	case t.IsUnsigned():
+		return g.di.CreateBasicType(t.String(), sizeBits, interfaces.DWARFUnsigned)
+	case t.IsInteger():
+		return g.di.CreateBasicType(t.String(), sizeBits, interfaces.DWARFSigned)
+	case t.IsFloat():
+		return g.di.CreateBasicType(t.String(), sizeBits, interfaces.DWARFFloat)
+	case t.Kind == domain.BoolType:
+		return g.di.CreateBasicType(t.String(), 8, interfaces.DWARFBoolean)
+	case t.Kind == domain.StringType:
+		char := g.di.CreateBasicType("char", 8, interfaces.DWARFSignedChar)
+		pointerBits, _ := g.typeLayout(g.module.PointerType(nil))
+		return g.di.CreatePointerType(char, pointerBits)
+	default:
+		return nil
+	}
+}
+
+// debugLayout returns the size and alignment in bits of a source type as
+// the target lays it out in memory
+func (g *Generator) debugLayout(t domain.Type) (int, int) {
+	return g.typeLayout(g.getMemoryType(t))
+}
+
+// typeLayout returns the size and alignment in bits of an LLVM type on the
+// module's target
+func (g *Generator) typeLayout(t interfaces.LLVMType) (int, int) {
+	return g.module.SizeOf(t) * 8, g.module.AlignOf(t) * 8
+}
+
+// alignTo rounds offset up to a multiple of align
+func alignTo(offset, align int) int {
+	if align == 0 {
+		return offset
+	}
+	return (offset + align - 1) / align * align
+}
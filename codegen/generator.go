@@ -2,32 +2,105 @@ package codegen
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/sokoide/llvm5/internal/domain"
+	"github.com/sokoide/llvm5/internal/infrastructure/llvmir"
 	"github.com/sokoide/llvm5/internal/interfaces"
 )
 
-// Generator implements the CodeGenerator interface for LLVM IR generation
+// Generator implements the CodeGenerator interface for LLVM IR generation.
+// Instructions are built through the LLVM interfaces of the configured
+// backend, the pure-Go llvmir backend by default.
 type Generator struct {
 	backend       interfaces.LLVMBackend
 	symbolTable   interfaces.SymbolTable
 	typeRegistry  domain.TypeRegistry
 	errorReporter domain.ErrorReporter
-	output        strings.Builder
-	indentLevel   int
+	module        interfaces.LLVMModule   // Module being generated
+	builder       interfaces.LLVMBuilder  // Builder positioned in the current basic block
+	function      interfaces.LLVMFunction // Function being generated
 	labelCounter  int
 	functionName  string
-	currentValue  string          // Holds the current expression result value
-	currentType   string          // Holds the current expression result type
-	parameters    map[string]bool // Track which identifiers are function parameters
+	currentValue  interfaces.LLVMValue            // Result of the last generated expression, nil for void
+	returnType    domain.Type                     // Declared return type of the current function
+	boundsChecks  bool                            // Emit runtime array index checks
+	divChecks     bool                            // Emit runtime integer divisor checks
+	optimization  int                             // Optimization level the module is optimized at
+	targetTriple  string                          // Target the module is generated for, empty for the host
+	sourceFiles   map[string]interfaces.LLVMValue // Source file name constants used by runtime traps
+	stringPool    map[string]interfaces.LLVMValue // Module-level string literal constants by content
+	globals       map[string]interfaces.LLVMValue // Global variables by name
+	constants     map[string]interfaces.LLVMValue // Values of global constants by name
+	loops         []loopTarget                    // Enclosing loops, innermost last
+
+	// Local variables
+	scope  *interfaces.Scope                           // Innermost scope of local declarations
+	locals map[*interfaces.Symbol]interfaces.LLVMValue // Stack slots of parameters and local variables, values of local constants
+	names  *localNames                                 // Names of the values and blocks of the current function
+
+	// Debug information
+	debugInfo    bool                               // Emit DWARF debug information
+	di           interfaces.LLVMDIBuilder           // Debug information builder, nil without debug information
+	diFile       interfaces.LLVMMetadata            // Source file of the compile unit
+	diScope      interfaces.LLVMMetadata            // Innermost subprogram or lexical block
+	diTypes      map[string]interfaces.LLVMMetadata // Debug types by source type name
+	functionBody *domain.BlockStmt                  // Body of the current function, which shares its scope
+}
+
+// loopTarget holds the branch targets of an enclosing loop for break and continue
+type loopTarget struct {
+	label         string // Source label of the loop, empty if unlabeled
+	breakBlock    interfaces.LLVMBasicBlock
+	continueBlock interfaces.LLVMBasicBlock
+}
+
+// mainExitType is the C int type main returns its exit status as
+var mainExitType = &domain.BasicType{Kind: domain.Int32Type}
+
+// runtimeFunctions lists the C library and StaticLang runtime functions every
+// module declares, with their signatures written as LLVM type names and size
+// standing for C's size_t
+var runtimeFunctions = []struct {
+	name     string
+	result   string
+	params   []string
+	variadic bool
+}{
+	{"printf", "i32", []string{"ptr"}, true},
+	{"malloc", "ptr", []string{"size"}, false},
+	{"free", "void", []string{"ptr"}, false},
+	{"memset", "ptr", []string{"ptr", "i32", "size"}, false},
+	{"sl_print_int", "void", []string{"i64"}, false},
+	{"sl_print_uint", "void", []string{"i64"}, false},
+	{"sl_print_double", "void", []string{"double"}, false},
+	{"sl_print_string", "void", []string{"ptr"}, false},
+	{"sl_print_bool", "void", []string{"i32"}, false},
+	{"sl_alloc_string", "ptr", []string{"ptr"}, false},
+	{"sl_concat_string", "ptr", []string{"ptr", "ptr"}, false},
+	{"sl_compare_string", "i32", []string{"ptr", "ptr"}, false},
+	{"sl_string_length", "i64", []string{"ptr"}, false},
+	{"sl_alloc_array", "ptr", []string{"size", "size"}, false},
+	{"sl_bounds_check_failed", "void", []string{"ptr", "i32", "i32", "i64", "i64"}, false},
+	{"sl_division_by_zero", "void", []string{"ptr", "i32", "i32"}, false},
+	{"sl_int_to_string", "ptr", []string{"i64"}, false},
+	{"sl_uint_to_string", "ptr", []string{"i64"}, false},
+	{"sl_float_to_string", "ptr", []string{"double"}, false},
+	{"sl_bool_to_string", "ptr", []string{"i32"}, false},
 }
 
 // NewGenerator creates a new code generator
 func NewGenerator() *Generator {
 	return &Generator{
 		labelCounter: 0,
-		parameters:   make(map[string]bool),
+		scope:        newScope(nil),
+		locals:       make(map[*interfaces.Symbol]interfaces.LLVMValue),
+		names:        newLocalNames(),
+		sourceFiles:  make(map[string]interfaces.LLVMValue),
+		stringPool:   make(map[string]interfaces.LLVMValue),
+		globals:      make(map[string]interfaces.LLVMValue),
+		constants:    make(map[string]interfaces.LLVMValue),
 	}
 }
 
@@ -51,224 +124,429 @@ func (g *Generator) SetErrorReporter(reporter domain.ErrorReporter) {
 	g.errorReporter = reporter
 }
 
+// SetBoundsChecks enables or disables runtime array bounds checking
+func (g *Generator) SetBoundsChecks(enabled bool) {
+	g.boundsChecks = enabled
+}
+
+// SetDivisionChecks enables or disables runtime checks for integer division
+// and remainder by zero
+func (g *Generator) SetDivisionChecks(enabled bool) {
+	g.divChecks = enabled
+}
+
+// SetTargetTriple sets the target triple of the generated module. An empty
+// triple selects the host.
+func (g *Generator) SetTargetTriple(triple string) {
+	g.targetTriple = triple
+}
+
+// SetOptimizationLevel sets the level of the passes run over the generated
+// module; 0 only promotes local variables to SSA values
+func (g *Generator) SetOptimizationLevel(level int) {
+	g.optimization = level
+}
+
+// SetDebugInfo enables or disables DWARF debug information
+func (g *Generator) SetDebugInfo(enabled bool) {
+	g.debugInfo = enabled
+}
+
 // Generate generates LLVM IR for the given AST
 func (g *Generator) Generate(node domain.Node) (string, error) {
-	g.output.Reset()
-	g.indentLevel = 0
-	g.labelCounter = 0
+	backend := g.backend
+	if backend == nil {
+		backend = llvmir.NewBackend()
+	}
+
+	triple := g.targetTriple
+	if triple == "" {
+		triple = llvmir.HostTriple()
+	}
 
 	// Initialize LLVM backend
-	if g.backend != nil {
-		if err := g.backend.Initialize("x86_64-apple-macosx10.15.0"); err != nil {
-			return "", fmt.Errorf("failed to initialize LLVM backend: %v", err)
-		}
-		defer g.backend.Dispose()
+	if err := backend.Initialize(triple); err != nil {
+		return "", fmt.Errorf("failed to initialize LLVM backend: %v", err)
 	}
+	defer backend.Dispose()
 
-	// Generate the code
-	err := node.Accept(g)
+	module, err := backend.CreateModule("staticlang")
 	if err != nil {
+		return "", fmt.Errorf("failed to create LLVM module: %v", err)
+	}
+	g.beginModule(module)
+
+	// Generate the code
+	if err := node.Accept(g); err != nil {
 		return "", err
 	}
 
-	return g.output.String(), nil
-}
+	// Local variables are promoted to SSA values at every level
+	if err := backend.Optimize(module, g.optimization); err != nil {
+		return "", fmt.Errorf("failed to optimize LLVM module: %v", err)
+	}
 
-// Helper methods for code generation
-func (g *Generator) emit(format string, args ...interface{}) {
-	indent := strings.Repeat("  ", g.indentLevel)
-	g.output.WriteString(indent)
-	g.output.WriteString(fmt.Sprintf(format, args...))
-	g.output.WriteString("\n")
+	var output strings.Builder
+	module.Print(&output)
+	return output.String(), nil
 }
 
-func (g *Generator) emitRaw(text string) {
-	g.output.WriteString(text)
+// beginModule resets the generator to fill the given module
+func (g *Generator) beginModule(module interfaces.LLVMModule) {
+	g.module = module
+	g.builder = module.CreateBuilder()
+	g.function = nil
+	g.labelCounter = 0
+	g.beginFunctionScope()
+	g.sourceFiles = make(map[string]interfaces.LLVMValue)
+	g.stringPool = make(map[string]interfaces.LLVMValue)
+	g.globals = make(map[string]interfaces.LLVMValue)
+	g.constants = make(map[string]interfaces.LLVMValue)
+	g.loops = nil
+	g.di = nil
+	g.diScope = nil
 }
 
 func (g *Generator) newLabel(prefix string) string {
 	g.labelCounter++
-	return fmt.Sprintf("%s%d", prefix, g.labelCounter)
+	return g.names.unique(fmt.Sprintf("%s%d", prefix, g.labelCounter))
+}
+
+// newBlock creates a basic block with a fresh label in the current function
+func (g *Generator) newBlock(prefix string) interfaces.LLVMBasicBlock {
+	return g.function.CreateBasicBlock(g.newLabel(prefix))
+}
+
+// startBlock continues generation in block. A block that is still open
+// falls through to the new one, so every block is terminated.
+func (g *Generator) startBlock(block interfaces.LLVMBasicBlock) {
+	g.branchTo(block)
+	g.builder.PositionAtEnd(block)
+}
+
+// branchTo branches to block unless the current block is already terminated
+func (g *Generator) branchTo(block interfaces.LLVMBasicBlock) {
+	if !g.isTerminated() {
+		g.builder.CreateBr(block)
+	}
+}
+
+// isTerminated reports whether the current block already ends in a terminator
+func (g *Generator) isTerminated() bool {
+	return g.builder.GetInsertBlock().IsTerminated()
+}
+
+// newTemp returns a fresh temporary value name
+func (g *Generator) newTemp() string {
+	tempReg := g.names.unique(fmt.Sprintf("temp_%d", g.labelCounter))
+	g.labelCounter++
+	return tempReg
 }
 
 // Visitor pattern implementation for AST nodes
 func (g *Generator) VisitProgram(prog *domain.Program) error {
-	// Emit LLVM module header
-	g.emit("; ModuleID = 'staticlang'")
-	g.emit("target datalayout = \"e-m:o-i64:64-f80:128-n8:16:32:64-S128\"")
-	g.emit("target triple = \"x86_64-apple-macosx10.15.0\"")
-	g.emit("")
-
-	// Emit external function declarations
-	g.emit("; External function declarations")
-	g.emit("declare i32 @printf(i8*, ...)")
-	g.emit("declare i8* @malloc(i64)")
-	g.emit("declare void @free(i8*)")
-	g.emit("")
-
-	// Emit StaticLang builtin functions
-	g.emit("; StaticLang builtin functions")
-	g.emit("declare void @sl_print_int(i32)")
-	g.emit("declare void @sl_print_double(double)")
-	g.emit("declare void @sl_print_string(i8*)")
-	g.emit("declare i8* @sl_alloc_string(i8*)")
-	g.emit("declare i8* @sl_concat_string(i8*, i8*)")
-	g.emit("declare i32 @sl_compare_string(i8*, i8*)")
-	g.emit("declare i8* @sl_alloc_array(i64, i64)")
-	g.emit("")
-
-	// Process all declarations
+	for _, rt := range runtimeFunctions {
+		g.runtimeFunction(rt.name)
+	}
+
+	// Functions and globals are declared up front so they may be used before
+	// their definitions
 	for _, decl := range prog.Declarations {
+		switch d := decl.(type) {
+		case *domain.FunctionDecl:
+			g.declareFunction(d.Name, parameterTypes(d.Parameters), d.ReturnType)
+		case *domain.VarDeclStmt:
+			if d.Constant {
+				// Constants have no storage; their uses get the value
+				if err := d.Initializer.Accept(g); err != nil {
+					return err
+				}
+				g.constants[d.Name] = g.currentValue
+				continue
+			}
+			if err := g.generateGlobalVariable(d); err != nil {
+				return err
+			}
+		}
+	}
+
+	g.beginDebugInfo(prog)
+
+	// Process all function and struct declarations
+	for _, decl := range prog.Declarations {
+		if _, global := decl.(*domain.VarDeclStmt); global {
+			continue
+		}
 		if err := decl.Accept(g); err != nil {
 			return err
 		}
 	}
 
+	if g.di != nil {
+		g.di.Finalize()
+	}
 	return nil
 }
 
-func (g *Generator) generateGlobalVariable(varDecl *domain.VarDeclStmt) error {
-	if varDecl.Initializer != nil {
-		// Initialize with value
-		if lit, ok := varDecl.Initializer.(*domain.LiteralExpr); ok {
-			switch varDecl.Type_.String() {
-			case "int":
-				g.emit("@%s = global i32 %s, align 4", varDecl.Name, lit.Value)
-			case "double":
-				g.emit("@%s = global double %s, align 8", varDecl.Name, lit.Value)
-			case "string":
-				// String literals need special handling
-				strValue := strings.Trim(lit.Value.(string), "\"")
-				length := len(strValue) + 1
-				g.emit("@%s.str = private unnamed_addr constant [%d x i8] c\"%s\\00\", align 1", varDecl.Name, length, strValue)
-				g.emit("@%s = global i8* getelementptr inbounds ([%d x i8], [%d x i8]* @%s.str, i32 0, i32 0), align 8", varDecl.Name, length, length, varDecl.Name)
-			}
-		} else {
-			// Initialize with zero
-			switch varDecl.Type_.String() {
-			case "int":
-				g.emit("@%s = global i32 0, align 4", varDecl.Name)
-			case "double":
-				g.emit("@%s = global double 0.0, align 8", varDecl.Name)
-			case "string":
-				g.emit("@%s = global i8* null, align 8", varDecl.Name)
-			}
+// runtimeFunction returns a runtime function, declaring it on first use
+func (g *Generator) runtimeFunction(name string) interfaces.LLVMFunction {
+	if fn, ok := g.module.GetFunction(name); ok {
+		return fn
+	}
+	for _, rt := range runtimeFunctions {
+		if rt.name != name {
+			continue
 		}
-	} else {
-		// Initialize with zero/null
-		switch varDecl.Type_.String() {
-		case "int":
-			g.emit("@%s = global i32 0, align 4", varDecl.Name)
-		case "double":
-			g.emit("@%s = global double 0.0, align 8", varDecl.Name)
-		case "string":
-			g.emit("@%s = global i8* null, align 8", varDecl.Name)
+		params := make([]interfaces.LLVMType, len(rt.params))
+		for i, param := range rt.params {
+			params[i] = g.runtimeType(param)
 		}
+		return g.module.AddFunction(name, g.module.FunctionType(g.runtimeType(rt.result), params, rt.variadic))
 	}
+	panic("unknown runtime function " + name)
+}
 
-	return nil
+// runtimeType returns the LLVM type named in a runtime function signature
+func (g *Generator) runtimeType(name string) interfaces.LLVMType {
+	switch name {
+	case "void":
+		return g.module.VoidType()
+	case "double":
+		return g.module.DoubleType()
+	case "ptr":
+		return g.stringType()
+	case "size":
+		return g.sizeType()
+	}
+	bits, _ := strconv.Atoi(strings.TrimPrefix(name, "i"))
+	return g.module.IntType(bits)
 }
 
-func (g *Generator) VisitFunctionDecl(node *domain.FunctionDecl) error {
-	g.functionName = node.Name
-	returnType := g.getLLVMType(node.ReturnType)
+// declareFunction returns the module function for a source function,
+// declaring it with the lowered signature on first use
+func (g *Generator) declareFunction(name string, params []domain.Type, result domain.Type) interfaces.LLVMFunction {
+	if fn, ok := g.module.GetFunction(name); ok {
+		return fn
+	}
 
-	// Clear and track parameters for this function
-	g.parameters = make(map[string]bool)
-	for _, param := range node.Parameters {
-		g.parameters[param.Name] = true
+	resultType := g.getLLVMType(result)
+	if name == "main" && domain.IsIntegerType(result) {
+		// main hands its result to the C runtime as the process exit status
+		resultType = g.getLLVMType(mainExitType)
 	}
+	paramTypes := make([]interfaces.LLVMType, len(params))
+	for i, param := range params {
+		paramTypes[i] = g.getLLVMType(param)
+	}
+	return g.module.AddFunction(name, g.module.FunctionType(resultType, paramTypes, false))
+}
 
-	// Generate function signature
-	paramStr := ""
-	for i, param := range node.Parameters {
-		if i > 0 {
-			paramStr += ", "
-		}
-		paramStr += fmt.Sprintf("%s %%%s", g.getLLVMType(param.Type), param.Name)
+// parameterTypes returns the types of a parameter list
+func parameterTypes(params []domain.Parameter) []domain.Type {
+	types := make([]domain.Type, len(params))
+	for i, param := range params {
+		types[i] = param.Type
 	}
+	return types
+}
 
-	g.emit("define %s @%s(%s) {", returnType, node.Name, paramStr)
-	g.emit("entry:")
-	g.indentLevel++
+// stringConstant returns a pointer to the first byte of a pooled module-level
+// constant holding value. Identical literals share one constant.
+func (g *Generator) stringConstant(value string) interfaces.LLVMValue {
+	global, ok := g.stringPool[value]
+	if !ok {
+		global = g.module.AddStringConstant(fmt.Sprintf(".str.%d", len(g.stringPool)), value)
+		g.stringPool[value] = global
+	}
+	zero := g.constInt64(0)
+	arrayType := g.module.ArrayType(g.module.IntType(8), len(value)+1)
+	return g.module.ConstGEP(arrayType, global, []interfaces.LLVMValue{zero, zero})
+}
 
-	// Allocate parameters on stack
-	for _, param := range node.Parameters {
-		g.emit("%%%s.addr = alloca %s, align %d", param.Name, g.getLLVMType(param.Type), g.getTypeAlign(param.Type))
-		g.emit("store %s %%%s, ptr %%%s.addr, align %d", g.getLLVMType(param.Type), param.Name, param.Name, g.getTypeAlign(param.Type))
+// sourceFileConstant returns the global holding a source file name for runtime diagnostics
+func (g *Generator) sourceFileConstant(filename string) interfaces.LLVMValue {
+	if global, ok := g.sourceFiles[filename]; ok {
+		return global
 	}
+	global := g.module.AddStringConstant(fmt.Sprintf(".srcfile.%d", len(g.sourceFiles)), filename)
+	g.sourceFiles[filename] = global
+	return global
+}
 
-	// Generate function body
-	if err := node.Body.Accept(g); err != nil {
-		return err
+func (g *Generator) generateGlobalVariable(varDecl *domain.VarDeclStmt) error {
+	switch varDecl.Type_.(type) {
+	case *domain.ArrayType, *domain.StructType:
+		// Dynamic arrays, including those nested in the variable, start out
+		// referencing a static empty header
+		var init interfaces.LLVMValue
+		if hasDynamicArrays(varDecl.Type_) {
+			header := g.module.AddGlobal(varDecl.Name+".header", g.dynamicArrayHeader(), nil)
+			init = g.emptyArraysConstant(varDecl.Type_, header)
+		}
+		g.globals[varDecl.Name] = g.module.AddGlobal(varDecl.Name, g.getLLVMType(varDecl.Type_), init)
+		return nil
 	}
 
-	// Check if the function already has a return statement by examining the output
-	// If not, add a default return
-	outputStr := g.output.String()
-	lines := strings.Split(outputStr, "\n")
-	hasReturn := false
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line == "" || line == "}" {
-			continue
+	basic, ok := varDecl.Type_.(*domain.BasicType)
+	if !ok {
+		return fmt.Errorf("unsupported type %s for global variable %s", varDecl.Type_, varDecl.Name)
+	}
+	llvmType := g.getLLVMType(basic)
+
+	// Literal initializers become the initial value, otherwise globals are zeroed
+	lit, hasLiteral := varDecl.Initializer.(*domain.LiteralExpr)
+	var init interfaces.LLVMValue
+	switch {
+	case basic.IsInteger():
+		var value int64
+		if hasLiteral {
+			value, _ = lit.Value.(int64)
 		}
-		if strings.HasPrefix(line, "ret ") {
-			hasReturn = true
-			break
+		init = g.module.ConstInt(llvmType, value)
+	case basic.IsFloat():
+		value := 0.0
+		if hasLiteral {
+			value, _ = lit.Value.(float64)
 		}
-		// If we encounter any other instruction, we assume no return
-		if line != "" {
-			break
+		init = g.module.ConstFloat(llvmType, value)
+	case basic.Kind == domain.BoolType:
+		var value int64
+		if hasLiteral {
+			if b, _ := lit.Value.(bool); b {
+				value = 1
+			}
 		}
+		llvmType = g.getMemoryType(basic)
+		init = g.module.ConstInt(llvmType, value)
+	case basic.Kind == domain.StringType:
+		// Strings start out empty rather than null, so they compare and print as ""
+		value := ""
+		if hasLiteral {
+			value, _ = lit.Value.(string)
+		}
+		init = g.stringConstant(value)
+	default:
+		return fmt.Errorf("unsupported type %s for global variable %s", basic, varDecl.Name)
 	}
+	g.globals[varDecl.Name] = g.module.AddGlobal(varDecl.Name, llvmType, init)
 
-	// Only add default return if there's no explicit return
-	if !hasReturn {
-		if node.ReturnType.String() == "void" {
-			g.emit("ret void")
-		} else if node.Name == "main" {
-			g.emit("ret i32 0")
-		}
+	return nil
+}
+
+func (g *Generator) VisitFunctionDecl(node *domain.FunctionDecl) error {
+	g.functionName = node.Name
+	g.returnType = node.ReturnType
+	defer func() { g.returnType = nil }()
+
+	fn := g.declareFunction(node.Name, parameterTypes(node.Parameters), node.ReturnType)
+	g.function = fn
+	g.functionBody = node.Body
+	g.beginFunctionScope()
+	g.builder.PositionAtEnd(fn.CreateBasicBlock(g.names.unique("entry")))
+	g.beginFunctionDebugInfo(node, fn)
+	defer g.endFunctionDebugInfo()
+
+	// Parameters are spilled to stack slots so they can be assigned
+	for i, param := range node.Parameters {
+		value := fn.GetParameter(i)
+		value.SetName(g.names.unique(param.Name))
+		slot := g.builder.CreateAlloca(g.getMemoryType(param.Type), g.names.unique(param.Name+".addr"))
+		g.storeValue(param.Type, value, slot)
+		g.declareVariable(param.Name, param.Type, slot, node.Location, i+1)
+		g.declareLocal(param.Name, param.Type, interfaces.ParameterSymbol, slot)
 	}
 
-	g.indentLevel--
-	g.emit("}")
-	g.emit("")
+	// Generate function body
+	if err := node.Body.Accept(g); err != nil {
+		return err
+	}
+
+	// Control reaching the end of the body gets a default return
+	if !g.isTerminated() {
+		switch {
+		case isVoidType(node.ReturnType):
+			g.builder.CreateRetVoid()
+		case node.Name == "main":
+			g.builder.CreateRet(g.module.ConstInt(g.getLLVMType(mainExitType), 0))
+		default:
+			g.builder.CreateUnreachable()
+		}
+	}
 
 	return nil
 }
 
 func (g *Generator) VisitStructDecl(node *domain.StructDecl) error {
-	// Not implemented for now
-	return fmt.Errorf("struct declarations not yet implemented")
+	// Define a named LLVM type whose fields follow the declaration order
+	fieldTypes := make([]interfaces.LLVMType, len(node.Fields))
+	for i, field := range node.Fields {
+		fieldTypes[i] = g.getMemoryType(field.Type)
+	}
+	g.module.SetStructBody(g.module.NamedStructType("struct."+node.Name), fieldTypes)
+
+	return nil
 }
 
 func (g *Generator) VisitBlockStmt(node *domain.BlockStmt) error {
+	outer := g.enterScope(node)
+	defer func() { g.diScope = outer }()
+	g.pushScope()
+	defer g.popScope()
+
 	for _, stmt := range node.Statements {
-		if err := stmt.Accept(g); err != nil {
+		// Statements after a return, break or continue can never run
+		if g.isTerminated() {
+			break
+		}
+		if err := g.visitStatement(stmt); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// visitStatement generates a statement with its source location attached
+func (g *Generator) visitStatement(stmt domain.Statement) error {
+	g.emitLocation(stmt)
+	return stmt.Accept(g)
+}
+
 func (g *Generator) VisitVarDeclStmt(node *domain.VarDeclStmt) error {
-	llvmType := g.getLLVMType(node.Type_)
-	align := g.getTypeAlign(node.Type_)
+	if node.Constant {
+		// The semantic analyzer folded the initializer to a literal, which
+		// uses of the constant get instead of a load
+		if err := node.Initializer.Accept(g); err != nil {
+			return err
+		}
+		g.declareLocal(node.Name, node.Type_, interfaces.ConstantSymbol, g.currentValue)
+		return nil
+	}
 
 	// Allocate local variable
-	g.emit("%%%s = alloca %s, align %d", node.Name, llvmType, align)
+	slot := g.builder.CreateAlloca(g.getMemoryType(node.Type_), g.names.unique(node.Name))
+	g.declareVariable(node.Name, node.Type_, slot, node.Location, 0)
 
 	// Initialize if there's an initializer
 	if node.Initializer != nil {
 		if err := node.Initializer.Accept(g); err != nil {
 			return err
 		}
-		// The expression result should be in g.currentValue
-		g.emit("store %s %s, ptr %%%s, align %d", llvmType, g.currentValue, node.Name, align)
+		value := g.coerceValue(g.currentValue, node.Initializer.GetType(), node.Type_)
+		g.storeValue(node.Type_, value, slot)
+	} else if arrayType, ok := node.Type_.(*domain.ArrayType); ok && arrayType.Size == -1 {
+		// Dynamic arrays always reference a header, starting out empty
+		header, _ := g.allocDynamicArray(arrayType.ElementType, g.constInt64(0))
+		g.builder.CreateStore(header, slot)
+	} else if isAggregateType(node.Type_) {
+		// Fixed arrays and structs start out zeroed, with empty dynamic arrays
+		memset := g.runtimeFunction("memset")
+		size := g.module.ConstSizeOf(g.getMemoryType(node.Type_))
+		g.builder.CreateCall(memset, []interfaces.LLVMValue{slot, g.module.ConstInt(g.module.IntType(32), 0), size}, g.newTemp())
+		g.initDynamicArrays(node.Type_, slot)
 	}
 
+	// The variable is visible after its initializer
+	g.declareLocal(node.Name, node.Type_, interfaces.VariableSymbol, slot)
 	return nil
 }
 
@@ -278,207 +556,227 @@ func (g *Generator) VisitAssignStmt(node *domain.AssignStmt) error {
 		return err
 	}
 
+	value := g.coerceValue(g.currentValue, node.Value.GetType(), node.Target.GetType())
+
 	// Store the result into the target
-	varType := g.getLLVMType(node.Target.GetType())
-	align := g.getTypeAlign(node.Target.GetType())
+	address, err := g.getAddress(node.Target)
+	if err != nil {
+		return err
+	}
+	g.storeValue(node.Target.GetType(), value, address)
 
-	if ident, ok := node.Target.(*domain.IdentifierExpr); ok {
-		g.emit("store %s %s, ptr %%%s, align %d", varType, g.currentValue, ident.Name, align)
+	return nil
+}
+
+func (g *Generator) VisitCompoundAssignStmt(node *domain.CompoundAssignStmt) error {
+	targetType := node.Target.GetType()
+
+	// The target address is computed once, before the value, so side
+	// effects of its index expressions happen once and left to right
+	address, err := g.getAddress(node.Target)
+	if err != nil {
+		return err
+	}
+
+	// Increments and decrements add or subtract one
+	var value interfaces.LLVMValue
+	if node.Value == nil {
+		if domain.IsFloatType(targetType) {
+			value = g.module.ConstFloat(g.getLLVMType(targetType), 1)
+		} else {
+			value = g.module.ConstInt(g.getLLVMType(targetType), 1)
+		}
+	} else {
+		if err := node.Value.Accept(g); err != nil {
+			return err
+		}
+		value = g.coerceValue(g.currentValue, node.Value.GetType(), targetType)
+	}
+
+	current := g.loadValue(targetType, address, g.newTemp())
+	result, err := g.emitArithmetic(node, node.Operator, targetType, current, value, g.newTemp())
+	if err != nil {
+		return err
 	}
+	g.storeValue(targetType, result, address)
 
 	return nil
 }
 
 func (g *Generator) VisitIfStmt(node *domain.IfStmt) error {
-	thenLabel := g.newLabel("if.then")
-	elseLabel := g.newLabel("if.else")
-	endLabel := g.newLabel("if.end")
+	thenBlock := g.newBlock("if.then")
+	elseBlock := g.newBlock("if.else")
+	endBlock := g.newBlock("if.end")
 
 	// Generate condition
 	if err := node.Condition.Accept(g); err != nil {
 		return err
 	}
-	conditionReg := g.currentValue
 
 	// Branch based on condition
 	if node.ElseStmt != nil {
-		g.emit("br i1 %s, label %%%s, label %%%s", conditionReg, thenLabel, elseLabel)
+		g.builder.CreateCondBr(g.currentValue, thenBlock, elseBlock)
 	} else {
-		g.emit("br i1 %s, label %%%s, label %%%s", conditionReg, thenLabel, endLabel)
+		g.builder.CreateCondBr(g.currentValue, thenBlock, endBlock)
 	}
 
 	// Then block
-	g.indentLevel--
-	g.emit("%s:", thenLabel)
-	g.indentLevel++
-	if err := node.ThenStmt.Accept(g); err != nil {
+	g.startBlock(thenBlock)
+	if err := g.visitStatement(node.ThenStmt); err != nil {
 		return err
 	}
-
-	// Check if the then block ends with a return statement
-	// If so, don't add a branch to avoid unreachable code
-	outputStr := g.output.String()
-	lines := strings.Split(outputStr, "\n")
-	lastNonEmptyLine := ""
-	for i := len(lines) - 1; i >= 0; i-- {
-		line := strings.TrimSpace(lines[i])
-		if line != "" {
-			lastNonEmptyLine = line
-			break
-		}
-	}
-
-	thenHasReturn := strings.HasPrefix(lastNonEmptyLine, "ret ")
-	if !thenHasReturn {
-		g.emit("br label %%%s", endLabel)
-	}
+	needsEndBlock := node.ElseStmt == nil || !g.isTerminated()
+	g.branchTo(endBlock)
 
 	// Else block (if exists)
 	if node.ElseStmt != nil {
-		g.indentLevel--
-		g.emit("%s:", elseLabel)
-		g.indentLevel++
-		if err := node.ElseStmt.Accept(g); err != nil {
+		g.startBlock(elseBlock)
+		if err := g.visitStatement(node.ElseStmt); err != nil {
 			return err
 		}
-
-		// Check if the else block ends with a return statement
-		outputStr = g.output.String()
-		lines = strings.Split(outputStr, "\n")
-		lastNonEmptyLine = ""
-		for i := len(lines) - 1; i >= 0; i-- {
-			line := strings.TrimSpace(lines[i])
-			if line != "" {
-				lastNonEmptyLine = line
-				break
-			}
-		}
-
-		elseHasReturn := strings.HasPrefix(lastNonEmptyLine, "ret ")
-		if !elseHasReturn {
-			g.emit("br label %%%s", endLabel)
-		}
-	}
-
-	// Only emit the end block if it's reachable
-	// (i.e., if at least one branch doesn't end with return)
-	needsEndBlock := !thenHasReturn || (node.ElseStmt != nil && !strings.HasPrefix(lastNonEmptyLine, "ret "))
-	if node.ElseStmt == nil {
-		needsEndBlock = true // Always need end block if there's no else
+		needsEndBlock = needsEndBlock || !g.isTerminated()
+		g.branchTo(endBlock)
 	}
 
+	// The end block is only reachable if some branch falls through; otherwise
+	// the current block stays terminated and following statements are dead
 	if needsEndBlock {
-		g.indentLevel--
-		g.emit("%s:", endLabel)
-		g.indentLevel++
+		g.startBlock(endBlock)
 	}
 
 	return nil
 }
 
 func (g *Generator) VisitWhileStmt(node *domain.WhileStmt) error {
-	condLabel := g.newLabel("while.cond")
-	bodyLabel := g.newLabel("while.body")
-	endLabel := g.newLabel("while.end")
-
-	// Jump to condition
-	g.emit("br label %%%s", condLabel)
+	condBlock := g.newBlock("while.cond")
+	bodyBlock := g.newBlock("while.body")
+	endBlock := g.newBlock("while.end")
 
 	// Condition block
-	g.indentLevel--
-	g.emit("%s:", condLabel)
-	g.indentLevel++
+	g.startBlock(condBlock)
+	g.emitLocation(node.Condition)
 	if err := node.Condition.Accept(g); err != nil {
 		return err
 	}
-	conditionReg := g.currentValue
-	g.emit("br i1 %s, label %%%s, label %%%s", conditionReg, bodyLabel, endLabel)
+	g.builder.CreateCondBr(g.currentValue, bodyBlock, endBlock)
 
 	// Body block
-	g.indentLevel--
-	g.emit("%s:", bodyLabel)
-	g.indentLevel++
-	if err := node.Body.Accept(g); err != nil {
+	g.startBlock(bodyBlock)
+	g.loops = append(g.loops, loopTarget{label: node.Label, breakBlock: endBlock, continueBlock: condBlock})
+	err := g.visitStatement(node.Body)
+	g.loops = g.loops[:len(g.loops)-1]
+	if err != nil {
 		return err
 	}
-	g.emit("br label %%%s", condLabel)
+	g.branchTo(condBlock)
 
 	// End block
-	g.indentLevel--
-	g.emit("%s:", endLabel)
-	g.indentLevel++
+	g.startBlock(endBlock)
 
 	return nil
 }
 
 func (g *Generator) VisitForStmt(node *domain.ForStmt) error {
+	// Variables declared by the init statement are scoped to the loop
+	g.pushScope()
+	defer g.popScope()
+
 	// Initialize
 	if node.Init != nil {
-		if err := node.Init.Accept(g); err != nil {
+		if err := g.visitStatement(node.Init); err != nil {
 			return err
 		}
 	}
 
-	condLabel := g.newLabel("for.cond")
-	bodyLabel := g.newLabel("for.body")
-	incLabel := g.newLabel("for.inc")
-	endLabel := g.newLabel("for.end")
-
-	// Jump to condition
-	g.emit("br label %%%s", condLabel)
+	condBlock := g.newBlock("for.cond")
+	bodyBlock := g.newBlock("for.body")
+	incBlock := g.newBlock("for.inc")
+	endBlock := g.newBlock("for.end")
 
 	// Condition block
-	g.indentLevel--
-	g.emit("%s:", condLabel)
-	g.indentLevel++
+	g.startBlock(condBlock)
 	if node.Condition != nil {
+		g.emitLocation(node.Condition)
 		if err := node.Condition.Accept(g); err != nil {
 			return err
 		}
-		conditionReg := g.currentValue
-		g.emit("br i1 %s, label %%%s, label %%%s", conditionReg, bodyLabel, endLabel)
-	} else {
-		g.emit("br label %%%s", bodyLabel)
+		g.builder.CreateCondBr(g.currentValue, bodyBlock, endBlock)
 	}
 
 	// Body block
-	g.indentLevel--
-	g.emit("%s:", bodyLabel)
-	g.indentLevel++
-	if err := node.Body.Accept(g); err != nil {
+	g.startBlock(bodyBlock)
+	g.loops = append(g.loops, loopTarget{label: node.Label, breakBlock: endBlock, continueBlock: incBlock})
+	err := g.visitStatement(node.Body)
+	g.loops = g.loops[:len(g.loops)-1]
+	if err != nil {
 		return err
 	}
-	g.emit("br label %%%s", incLabel)
 
 	// Increment block
-	g.indentLevel--
-	g.emit("%s:", incLabel)
-	g.indentLevel++
+	g.startBlock(incBlock)
 	if node.Update != nil {
-		if err := node.Update.Accept(g); err != nil {
+		if err := g.visitStatement(node.Update); err != nil {
 			return err
 		}
 	}
-	g.emit("br label %%%s", condLabel)
+	g.branchTo(condBlock)
 
 	// End block
-	g.indentLevel--
-	g.emit("%s:", endLabel)
-	g.indentLevel++
+	g.startBlock(endBlock)
 
 	return nil
 }
 
-func (g *Generator) VisitReturnStmt(node *domain.ReturnStmt) error {
-	if node.Value != nil {
-		if err := node.Value.Accept(g); err != nil {
-			return err
+func (g *Generator) VisitBreakStmt(node *domain.BreakStmt) error {
+	loop, err := g.findLoop(node.Label)
+	if err != nil {
+		return fmt.Errorf("break: %v", err)
+	}
+	g.builder.CreateBr(loop.breakBlock)
+	return nil
+}
+
+func (g *Generator) VisitContinueStmt(node *domain.ContinueStmt) error {
+	loop, err := g.findLoop(node.Label)
+	if err != nil {
+		return fmt.Errorf("continue: %v", err)
+	}
+	g.builder.CreateBr(loop.continueBlock)
+	return nil
+}
+
+// findLoop returns the innermost enclosing loop, or the one with the given label
+func (g *Generator) findLoop(label string) (loopTarget, error) {
+	for i := len(g.loops) - 1; i >= 0; i-- {
+		if label == "" || g.loops[i].label == label {
+			return g.loops[i], nil
 		}
-		returnType := g.getLLVMType(node.Value.GetType())
-		g.emit("ret %s %s", returnType, g.currentValue)
-	} else {
-		g.emit("ret void")
 	}
+	if label != "" {
+		return loopTarget{}, fmt.Errorf("no enclosing loop labeled %s", label)
+	}
+	return loopTarget{}, fmt.Errorf("not inside a loop")
+}
+
+func (g *Generator) VisitReturnStmt(node *domain.ReturnStmt) error {
+	if node.Value == nil {
+		g.builder.CreateRetVoid()
+		return nil
+	}
+
+	if err := node.Value.Accept(g); err != nil {
+		return err
+	}
+	value := g.currentValue
+	valueType := node.Value.GetType()
+	if g.returnType != nil {
+		value = g.coerceValue(value, valueType, g.returnType)
+		valueType = g.returnType
+	}
+	if g.functionName == "main" && domain.IsIntegerType(valueType) {
+		value = g.resizeInteger(value, valueType, mainExitType)
+	}
+	g.builder.CreateRet(value)
 	return nil
 }
 
@@ -487,92 +785,235 @@ func (g *Generator) VisitExprStmt(node *domain.ExprStmt) error {
 }
 
 func (g *Generator) VisitBinaryExpr(node *domain.BinaryExpr) error {
+	// Logical operators only evaluate the right operand when needed
+	if node.Operator == domain.And || node.Operator == domain.Or {
+		return g.generateLogicalExpr(node)
+	}
+
 	// Generate left operand
 	if err := node.Left.Accept(g); err != nil {
 		return err
 	}
-	leftReg := g.currentValue
+	left := g.currentValue
 
 	// Generate right operand
 	if err := node.Right.Accept(g); err != nil {
 		return err
 	}
-	rightReg := g.currentValue
+	right := g.currentValue
+	operandType := node.Left.GetType()
+	if node.Operator == domain.Shl || node.Operator == domain.Shr {
+		// LLVM shifts take the count in the type of the shifted value
+		right = g.resizeInteger(right, node.Right.GetType(), operandType)
+	}
 
 	// Generate unique temporary register
-	tempReg := fmt.Sprintf("%%temp_%d", g.labelCounter)
-	g.labelCounter++
+	tempReg := g.newTemp()
 
-	// Perform operation based on operator
-	resultType := g.getLLVMType(node.GetType())
+	unsigned := domain.IsUnsignedType(operandType)
 
 	switch node.Operator {
-	case domain.Add:
-		if resultType == "i32" {
-			g.emit("%s = add i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if resultType == "double" {
-			g.emit("%s = fadd double %s, %s", tempReg, leftReg, rightReg)
+	case domain.Eq, domain.Ne, domain.Lt, domain.Gt, domain.Le, domain.Ge:
+		switch {
+		case domain.IsFloatType(operandType):
+			g.currentValue = g.builder.CreateFCmp(floatComparePredicate(node.Operator), left, right, tempReg)
+		case isStringType(operandType):
+			// The runtime orders strings like strcmp, so compare its result with zero
+			cmp := g.builder.CreateCall(g.runtimeFunction("sl_compare_string"), []interfaces.LLVMValue{left, right}, g.newTemp())
+			zero := g.module.ConstInt(g.module.IntType(32), 0)
+			g.currentValue = g.builder.CreateICmp(intComparePredicate(node.Operator, false), cmp, zero, tempReg)
+		default:
+			g.currentValue = g.builder.CreateICmp(intComparePredicate(node.Operator, unsigned), left, right, tempReg)
 		}
-	case domain.Sub:
-		if resultType == "i32" {
-			g.emit("%s = sub i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if resultType == "double" {
-			g.emit("%s = fsub double %s, %s", tempReg, leftReg, rightReg)
+	default:
+		value, err := g.emitArithmetic(node, node.Operator, operandType, left, right, tempReg)
+		if err != nil {
+			return err
 		}
-	case domain.Mul:
-		if resultType == "i32" {
-			g.emit("%s = mul i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if resultType == "double" {
-			g.emit("%s = fmul double %s, %s", tempReg, leftReg, rightReg)
+		g.currentValue = value
+	}
+
+	return nil
+}
+
+// emitArithmetic applies an arithmetic operator to two values of operandType,
+// concatenating for + on strings. Division checks report the location of node.
+func (g *Generator) emitArithmetic(node domain.Node, operator domain.BinaryOperator, operandType domain.Type, left, right interfaces.LLVMValue, name string) (interfaces.LLVMValue, error) {
+	if operator == domain.Add && isStringType(operandType) {
+		return g.builder.CreateCall(g.runtimeFunction("sl_concat_string"), []interfaces.LLVMValue{left, right}, name), nil
+	}
+	op, ok := binaryOpcode(operator, operandType)
+	if !ok {
+		return nil, fmt.Errorf("unsupported operator %v for %s", operator, operandType)
+	}
+	if g.divChecks && (operator == domain.Div || operator == domain.Mod) && domain.IsIntegerType(operandType) {
+		g.emitDivisionCheck(node, right)
+	}
+	return g.builder.CreateBinOp(op, left, right, name), nil
+}
+
+// binaryOpcode returns the arithmetic instruction for an operator applied to
+// operands of type t
+func binaryOpcode(op domain.BinaryOperator, t domain.Type) (interfaces.BinaryOpcode, bool) {
+	if domain.IsFloatType(t) {
+		switch op {
+		case domain.Add:
+			return interfaces.BinaryFAdd, true
+		case domain.Sub:
+			return interfaces.BinaryFSub, true
+		case domain.Mul:
+			return interfaces.BinaryFMul, true
+		case domain.Div:
+			return interfaces.BinaryFDiv, true
+		case domain.Mod:
+			return interfaces.BinaryFRem, true
 		}
+		return 0, false
+	}
+	if !domain.IsIntegerType(t) {
+		return 0, false
+	}
+
+	switch op {
+	case domain.Add:
+		return interfaces.BinaryAdd, true
+	case domain.Sub:
+		return interfaces.BinarySub, true
+	case domain.Mul:
+		return interfaces.BinaryMul, true
 	case domain.Div:
-		if resultType == "i32" {
-			g.emit("%s = sdiv i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if resultType == "double" {
-			g.emit("%s = fdiv double %s, %s", tempReg, leftReg, rightReg)
+		if domain.IsUnsignedType(t) {
+			return interfaces.BinaryUDiv, true
 		}
-	case domain.Eq:
-		if node.Left.GetType().String() == "int" {
-			g.emit("%s = icmp eq i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if node.Left.GetType().String() == "double" {
-			g.emit("%s = fcmp oeq double %s, %s", tempReg, leftReg, rightReg)
+		return interfaces.BinarySDiv, true
+	case domain.Mod:
+		if domain.IsUnsignedType(t) {
+			return interfaces.BinaryURem, true
+		}
+		return interfaces.BinarySRem, true
+	case domain.BitAnd:
+		return interfaces.BinaryAnd, true
+	case domain.BitOr:
+		return interfaces.BinaryOr, true
+	case domain.BitXor:
+		return interfaces.BinaryXor, true
+	case domain.Shl:
+		return interfaces.BinaryShl, true
+	case domain.Shr:
+		if domain.IsUnsignedType(t) {
+			return interfaces.BinaryLShr, true
 		}
+		return interfaces.BinaryAShr, true
+	}
+	return 0, false
+}
+
+// intComparePredicate returns the icmp predicate for a comparison operator
+func intComparePredicate(op domain.BinaryOperator, unsigned bool) interfaces.IntPredicate {
+	switch op {
+	case domain.Eq:
+		return interfaces.IntEQ
 	case domain.Ne:
-		if node.Left.GetType().String() == "int" {
-			g.emit("%s = icmp ne i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if node.Left.GetType().String() == "double" {
-			g.emit("%s = fcmp one double %s, %s", tempReg, leftReg, rightReg)
+		return interfaces.IntNE
+	}
+
+	if unsigned {
+		switch op {
+		case domain.Lt:
+			return interfaces.IntULT
+		case domain.Gt:
+			return interfaces.IntUGT
+		case domain.Le:
+			return interfaces.IntULE
+		default:
+			return interfaces.IntUGE
 		}
+	}
+	switch op {
 	case domain.Lt:
-		if node.Left.GetType().String() == "int" {
-			g.emit("%s = icmp slt i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if node.Left.GetType().String() == "double" {
-			g.emit("%s = fcmp olt double %s, %s", tempReg, leftReg, rightReg)
-		}
+		return interfaces.IntSLT
 	case domain.Gt:
-		if node.Left.GetType().String() == "int" {
-			g.emit("%s = icmp sgt i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if node.Left.GetType().String() == "double" {
-			g.emit("%s = fcmp ogt double %s, %s", tempReg, leftReg, rightReg)
-		}
+		return interfaces.IntSGT
 	case domain.Le:
-		if node.Left.GetType().String() == "int" {
-			g.emit("%s = icmp sle i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if node.Left.GetType().String() == "double" {
-			g.emit("%s = fcmp ole double %s, %s", tempReg, leftReg, rightReg)
-		}
-	case domain.Ge:
-		if node.Left.GetType().String() == "int" {
-			g.emit("%s = icmp sge i32 %s, %s", tempReg, leftReg, rightReg)
-		} else if node.Left.GetType().String() == "double" {
-			g.emit("%s = fcmp oge double %s, %s", tempReg, leftReg, rightReg)
+		return interfaces.IntSLE
+	default:
+		return interfaces.IntSGE
+	}
+}
+
+// floatComparePredicate returns the ordered fcmp predicate for a comparison operator
+func floatComparePredicate(op domain.BinaryOperator) interfaces.FloatPredicate {
+	switch op {
+	case domain.Eq:
+		return interfaces.FloatOEQ
+	case domain.Ne:
+		return interfaces.FloatONE
+	case domain.Lt:
+		return interfaces.FloatOLT
+	case domain.Gt:
+		return interfaces.FloatOGT
+	case domain.Le:
+		return interfaces.FloatOLE
+	default:
+		return interfaces.FloatOGE
+	}
+}
+
+// resizeInteger converts an integer value of type from to the width of type to,
+// extending according to the signedness of the source type
+func (g *Generator) resizeInteger(value interfaces.LLVMValue, from, to domain.Type) interfaces.LLVMValue {
+	fromBits, toBits := from.GetSize()*8, to.GetSize()*8
+	if fromBits == toBits {
+		return value
+	}
+
+	op := interfaces.CastTrunc
+	if fromBits < toBits {
+		op = interfaces.CastSExt
+		if domain.IsUnsignedType(from) {
+			op = interfaces.CastZExt
 		}
 	}
+	return g.builder.CreateCast(op, value, g.module.IntType(toBits), g.newTemp())
+}
+
+// generateLogicalExpr lowers && and || with short-circuit evaluation. The right
+// operand gets its own block and the result is merged with a phi.
+func (g *Generator) generateLogicalExpr(node *domain.BinaryExpr) error {
+	prefix, shortValue := "land", int64(0)
+	if node.Operator == domain.Or {
+		prefix, shortValue = "lor", 1
+	}
 
-	// Update current value for parent expressions
-	g.currentValue = tempReg
-	g.currentType = resultType
+	// Generate left operand
+	if err := node.Left.Accept(g); err != nil {
+		return err
+	}
+	left := g.currentValue
+	leftBlock := g.builder.GetInsertBlock()
 
+	rhsBlock := g.newBlock(prefix + ".rhs")
+	endBlock := g.newBlock(prefix + ".end")
+	if node.Operator == domain.And {
+		g.builder.CreateCondBr(left, rhsBlock, endBlock)
+	} else {
+		g.builder.CreateCondBr(left, endBlock, rhsBlock)
+	}
+
+	// Right operand, reached only when the left one does not decide the result
+	g.startBlock(rhsBlock)
+	if err := node.Right.Accept(g); err != nil {
+		return err
+	}
+	right := g.currentValue
+	rightBlock := g.builder.GetInsertBlock()
+
+	g.startBlock(endBlock)
+	boolType := g.module.IntType(1)
+	g.currentValue = g.builder.CreatePhi(boolType,
+		[]interfaces.LLVMValue{g.module.ConstInt(boolType, shortValue), right},
+		[]interfaces.LLVMBasicBlock{leftBlock, rightBlock},
+		g.newTemp())
 	return nil
 }
 
@@ -580,16 +1021,21 @@ func (g *Generator) VisitUnaryExpr(node *domain.UnaryExpr) error {
 	if err := node.Operand.Accept(g); err != nil {
 		return err
 	}
+	operand := g.currentValue
 
 	switch node.Operator {
 	case domain.Neg:
-		if node.GetType().String() == "int" {
-			g.emit("%%temp_result = sub i32 0, %%temp_result")
-		} else if node.GetType().String() == "double" {
-			g.emit("%%temp_result = fsub double 0.0, %%temp_result")
+		if domain.IsFloatType(node.Operand.GetType()) {
+			g.currentValue = g.builder.CreateFNeg(operand, g.newTemp())
+		} else {
+			g.currentValue = g.builder.CreateNeg(operand, g.newTemp())
 		}
-	case domain.Not:
-		g.emit("%%temp_result = icmp eq i1 %%temp_result, false")
+	case domain.Not, domain.BitNot:
+		// xor with all ones flips every bit; bools are i1 in registers, so
+		// this flips them too
+		g.currentValue = g.builder.CreateNot(operand, g.newTemp())
+	default:
+		return fmt.Errorf("unsupported unary operator %s", node.Operator)
 	}
 
 	return nil
@@ -600,44 +1046,48 @@ func (g *Generator) VisitCallExpr(node *domain.CallExpr) error {
 	if ident, ok := node.Function.(*domain.IdentifierExpr); ok && ident.Name == "print" {
 		return g.handlePrintFunction(node)
 	}
+	if ident, ok := node.Function.(*domain.IdentifierExpr); ok && ident.Name == "len" {
+		return g.handleLenFunction(node)
+	}
+
+	ident, ok := node.Function.(*domain.IdentifierExpr)
+	if !ok {
+		return fmt.Errorf("unsupported call target %T", node.Function)
+	}
+
+	// Parameter types drive conversions such as fixed to dynamic arrays
+	funcType, _ := node.Function.GetType().(*domain.FunctionType)
+	var paramTypes []domain.Type
+	if funcType != nil {
+		paramTypes = funcType.ParameterTypes
+	}
 
 	// Generate arguments for regular function calls
-	var argValues []string
-	var argTypes []string
-	for _, arg := range node.Args {
+	var args []interfaces.LLVMValue
+	for i, arg := range node.Args {
 		if err := arg.Accept(g); err != nil {
 			return err
 		}
-		argType := g.getLLVMType(arg.GetType())
-		argTypes = append(argTypes, argType)
-		argValues = append(argValues, fmt.Sprintf("%s %s", argType, g.currentValue))
-	}
-
-	// Determine function name (assume identifier)
-	funcName := "<unknown>"
-	if ident, ok := node.Function.(*domain.IdentifierExpr); ok {
-		funcName = ident.Name
+		value := g.currentValue
+		if i < len(paramTypes) {
+			value = g.coerceValue(value, arg.GetType(), paramTypes[i])
+		}
+		args = append(args, value)
 	}
 
-	// Generate unique temporary register for the result
-	tempReg := fmt.Sprintf("%%temp_%d", g.labelCounter)
-	g.labelCounter++
-
-	// Generate function call
-	returnType := g.getLLVMType(node.GetType())
-	argsStr := ""
-	if len(argValues) > 0 {
-		argsStr = fmt.Sprintf("%s", strings.Join(argValues, ", "))
+	fn, ok := g.module.GetFunction(ident.Name)
+	if !ok {
+		if funcType == nil {
+			return fmt.Errorf("undefined function %s", ident.Name)
+		}
+		fn = g.declareFunction(ident.Name, funcType.ParameterTypes, funcType.ReturnType)
 	}
 
-	if returnType == "void" {
-		g.emit("call void @%s(%s)", funcName, argsStr)
-		g.currentValue = ""
-		g.currentType = "void"
+	result := g.builder.CreateCall(fn, args, g.newTemp())
+	if isVoidType(node.GetType()) {
+		g.currentValue = nil
 	} else {
-		g.emit("%s = call %s @%s(%s)", tempReg, returnType, funcName, argsStr)
-		g.currentValue = tempReg
-		g.currentType = returnType
+		g.currentValue = result
 	}
 
 	return nil
@@ -655,55 +1105,77 @@ func (g *Generator) handlePrintFunction(node *domain.CallExpr) error {
 			return err
 		}
 
-		argType := node.Args[0].GetType().String()
-		switch argType {
-		case "int":
-			g.emit("call void @sl_print_int(i32 %s)", g.currentValue)
-		case "double":
-			g.emit("call void @sl_print_double(double %s)", g.currentValue)
-		case "string":
-			g.emit("call void @sl_print_string(i8* %s)", g.currentValue)
+		var printer string
+		value := g.currentValue
+		argType := node.Args[0].GetType()
+		switch {
+		case domain.IsIntegerType(argType):
+			// Integers of every width are printed through the 64-bit runtime functions
+			value = g.resizeInteger(value, argType, domain.NewIntType())
+			printer = "sl_print_int"
+			if domain.IsUnsignedType(argType) {
+				printer = "sl_print_uint"
+			}
+		case domain.IsFloatType(argType):
+			value = g.extendFloat(value, argType)
+			printer = "sl_print_double"
+		case isStringType(argType):
+			printer = "sl_print_string"
+		case isBoolType(argType):
+			value = g.builder.CreateCast(interfaces.CastZExt, value, g.module.IntType(32), g.newTemp())
+			printer = "sl_print_bool"
 		default:
 			return fmt.Errorf("unsupported type for print: %s", argType)
 		}
+		g.builder.CreateCall(g.runtimeFunction(printer), []interfaces.LLVMValue{value}, "")
 
 		// print functions are void, set current value accordingly
-		g.currentValue = ""
-		g.currentType = "void"
+		g.currentValue = nil
 		return nil
 	}
 
 	// Multiple arguments: formatted printing with printf
 	// First argument should be format string
-	if err := node.Args[0].Accept(g); err != nil {
-		return err
-	}
-
-	if node.Args[0].GetType().String() != "string" {
+	if !isStringType(node.Args[0].GetType()) {
 		return fmt.Errorf("first argument to print must be a string for formatted printing")
 	}
 
-	formatValue := g.currentValue
-
-	// Get the actual format string for validation (if it's a literal)
+	// Get the actual format string for validation (if it's a literal).
+	// Integer arguments are passed as i64, so literal formats are widened to match.
+	format := node.Args[0]
 	var formatStr string
 	if lit, ok := node.Args[0].(*domain.LiteralExpr); ok {
 		if strVal, ok := lit.Value.(string); ok {
-			formatStr = strings.Trim(strVal, "\"")
+			formatStr = strVal
+			widened := *lit
+			widened.Value = widenIntegerFormats(strVal)
+			format = &widened
 		}
 	}
 
+	if err := format.Accept(g); err != nil {
+		return err
+	}
+	args := []interfaces.LLVMValue{g.currentValue}
+
 	// Generate remaining arguments
-	var argValues []string
 	var argTypes []string
 	for i := 1; i < len(node.Args); i++ {
 		if err := node.Args[i].Accept(g); err != nil {
 			return err
 		}
-		argType := g.getLLVMType(node.Args[i].GetType())
+		value := g.currentValue
 		typeStr := node.Args[i].GetType().String()
+		if domain.IsIntegerType(node.Args[i].GetType()) {
+			value = g.resizeInteger(value, node.Args[i].GetType(), domain.NewIntType())
+			typeStr = "int"
+		} else if domain.IsFloatType(node.Args[i].GetType()) {
+			// Variadic float arguments are promoted to double
+			value = g.extendFloat(value, node.Args[i].GetType())
+			typeStr = "float"
+		}
 		argTypes = append(argTypes, typeStr)
-		argValues = append(argValues, fmt.Sprintf("%s %s", argType, g.currentValue))
+		args = append(args, value)
 	}
 
 	// Validate format string if we have it as a literal
@@ -714,123 +1186,662 @@ func (g *Generator) handlePrintFunction(node *domain.CallExpr) error {
 	}
 
 	// Generate printf call
-	tempReg := fmt.Sprintf("%%temp_%d", g.labelCounter)
-	g.labelCounter++
+	g.currentValue = g.builder.CreateCall(g.runtimeFunction("printf"), args, g.newTemp())
 
-	argsStr := fmt.Sprintf("i8* %s", formatValue)
-	if len(argValues) > 0 {
-		argsStr += ", " + strings.Join(argValues, ", ")
+	return nil
+}
+
+// handleLenFunction returns the length of a string or array as an int
+func (g *Generator) handleLenFunction(node *domain.CallExpr) error {
+	if len(node.Args) != 1 {
+		return fmt.Errorf("len function requires exactly one argument")
 	}
+	arg := node.Args[0]
 
-	g.emit("%s = call i32 (i8*, ...) @printf(%s)", tempReg, argsStr)
-	g.currentValue = tempReg
-	g.currentType = "i32"
+	// Fixed array lengths are known statically
+	if arrayType, ok := arg.GetType().(*domain.ArrayType); ok && arrayType.Size != -1 && g.isAddressable(arg) {
+		g.currentValue = g.constInt64(int64(arrayType.Size))
+		return nil
+	}
 
+	if err := arg.Accept(g); err != nil {
+		return err
+	}
+
+	switch argType := arg.GetType().(type) {
+	case *domain.ArrayType:
+		if argType.Size == -1 {
+			g.currentValue = g.dynamicArrayLength(g.currentValue)
+		} else {
+			g.currentValue = g.constInt64(int64(argType.Size))
+		}
+	default:
+		g.currentValue = g.builder.CreateCall(g.runtimeFunction("sl_string_length"), []interfaces.LLVMValue{g.currentValue}, g.newTemp())
+	}
 	return nil
 }
 
 func (g *Generator) VisitIdentifierExpr(node *domain.IdentifierExpr) error {
-	varType := g.getLLVMType(node.GetType())
-	align := g.getTypeAlign(node.GetType())
+	if value, ok := g.constantValue(node.Name); ok {
+		g.currentValue = value
+		return nil
+	}
 
 	// Generate a unique temporary register name
-	tempReg := fmt.Sprintf("%%temp_%d", g.labelCounter)
-	g.labelCounter++
+	tempReg := g.newTemp()
 
-	// Determine whether to use .addr suffix based on whether it's a parameter
-	var varName string
-	if g.parameters[node.Name] {
-		// This is a function parameter, use .addr suffix
-		varName = fmt.Sprintf("%%%s.addr", node.Name)
-	} else {
-		// This is a local variable, use direct name
-		varName = fmt.Sprintf("%%%s", node.Name)
+	slot, err := g.variableAddress(node.Name)
+	if err != nil {
+		return err
 	}
 
-	g.emit("%s = load %s, ptr %s, align %d", tempReg, varType, varName, align)
-
 	// Store the result for use by parent expressions
-	g.currentValue = tempReg
-	g.currentType = varType
+	g.currentValue = g.loadValue(node.GetType(), slot, tempReg)
 
 	return nil
 }
 
 func (g *Generator) VisitLiteralExpr(node *domain.LiteralExpr) error {
-	switch node.GetType().String() {
-	case "int":
-		if val, ok := node.Value.(int64); ok {
-			// For integer literals, we can directly use the value in ret statement
-			// But if we need a temp variable, we should allocate and store
-			g.currentValue = fmt.Sprintf("%d", val)
-			g.currentType = "i32"
-		} else {
-			// Fallback for safety, though parser should ensure int64
-			g.currentValue = fmt.Sprintf("%s", node.Value)
-			g.currentType = "i32"
+	switch {
+	case domain.IsIntegerType(node.GetType()):
+		// Integer literals are used directly as constants of the literal's type
+		val, err := strconv.ParseInt(fmt.Sprint(node.Value), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer literal %v", node.Value)
 		}
-	case "double":
-		if val, ok := node.Value.(float64); ok {
-			g.currentValue = fmt.Sprintf("%f", val)
-			g.currentType = "double"
-		} else {
-			// Fallback for safety
-			g.currentValue = fmt.Sprintf("%s", node.Value)
-			g.currentType = "double"
-		}
-	case "string":
-		// String literals need special handling
-		strValue := strings.Trim(node.Value.(string), "\"")
-		length := len(strValue) + 1
-		labelName := g.newLabel("str")
-		g.emit("@%s = private unnamed_addr constant [%d x i8] c\"%s\\00\", align 1", labelName, length, strValue)
-		g.currentValue = fmt.Sprintf("getelementptr inbounds ([%d x i8], [%d x i8]* @%s, i32 0, i32 0)", length, length, labelName)
-		g.currentType = "i8*"
+		g.currentValue = g.module.ConstInt(g.getLLVMType(node.GetType()), val)
+	case domain.IsFloatType(node.GetType()):
+		val, err := strconv.ParseFloat(fmt.Sprint(node.Value), 64)
+		if err != nil {
+			return fmt.Errorf("invalid float literal %v", node.Value)
+		}
+		g.currentValue = g.module.ConstFloat(g.getLLVMType(node.GetType()), val)
+	case isStringType(node.GetType()):
+		// String literals are pooled at module scope and referenced by address
+		g.currentValue = g.stringConstant(node.Value.(string))
+	case isBoolType(node.GetType()):
+		val := int64(0)
+		if b, _ := node.Value.(bool); b {
+			val = 1
+		}
+		g.currentValue = g.module.ConstInt(g.getLLVMType(node.GetType()), val)
 	}
 	return nil
 }
 
 func (g *Generator) VisitIndexExpr(node *domain.IndexExpr) error {
-	// Not implemented for now
-	return fmt.Errorf("index expressions not yet implemented")
+	elemPtr, err := g.getIndexAddress(node)
+	if err != nil {
+		return err
+	}
+
+	g.currentValue = g.loadValue(node.GetType(), elemPtr, g.newTemp())
+	return nil
 }
 
 func (g *Generator) VisitMemberExpr(node *domain.MemberExpr) error {
-	// Not implemented for now
-	return fmt.Errorf("member expressions not yet implemented")
+	// Addressable objects are accessed in place through getelementptr
+	if g.isAddressable(node.Object) {
+		fieldPtr, err := g.getMemberAddress(node)
+		if err != nil {
+			return err
+		}
+
+		g.currentValue = g.loadValue(node.GetType(), fieldPtr, g.newTemp())
+		return nil
+	}
+
+	// Struct values such as call results are not in memory, so extract the field
+	_, index, err := g.resolveField(node)
+	if err != nil {
+		return err
+	}
+	if err := node.Object.Accept(g); err != nil {
+		return err
+	}
+
+	g.currentValue = g.builder.CreateExtractValue(g.currentValue, index, g.newTemp())
+	if isBoolType(node.GetType()) {
+		g.currentValue = g.builder.CreateCast(interfaces.CastTrunc, g.currentValue, g.module.IntType(1), g.newTemp())
+	}
+	return nil
 }
 
-// Helper functions
-func (g *Generator) getLLVMType(t domain.Type) string {
-	switch t.String() {
-	case "int":
-		return "i32"
-	case "double":
-		return "double"
-	case "string":
-		return "i8*"
-	case "bool":
-		return "i1"
-	case "void":
-		return "void"
+func (g *Generator) VisitCastExpr(node *domain.CastExpr) error {
+	if err := node.Value.Accept(g); err != nil {
+		return err
+	}
+
+	value, err := g.convertValue(g.currentValue, node.Value.GetType(), node.Target)
+	if err != nil {
+		return err
+	}
+
+	g.currentValue = value
+	return nil
+}
+
+// convertValue lowers an explicit conversion between basic types. Numeric and
+// bool conversions map to LLVM cast instructions, conversions to string call
+// the runtime formatting functions.
+func (g *Generator) convertValue(value interfaces.LLVMValue, from, to domain.Type) (interfaces.LLVMValue, error) {
+	if from.Equals(to) {
+		return value, nil
+	}
+	fromBasic, ok := from.(*domain.BasicType)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %s to %s", from, to)
+	}
+	toBasic, ok := to.(*domain.BasicType)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert %s to %s", from, to)
+	}
+
+	if toBasic.Kind == domain.StringType {
+		return g.formatValue(value, fromBasic), nil
+	}
+	if toBasic.IsInteger() && fromBasic.IsInteger() {
+		return g.resizeInteger(value, from, to), nil
+	}
+
+	fromType, toType := g.getLLVMType(from), g.getLLVMType(to)
+	fromBool := fromBasic.Kind == domain.BoolType
+	tempReg := g.newTemp()
+
+	switch {
+	case toBasic.Kind == domain.BoolType:
+		if fromBasic.IsFloat() {
+			return g.builder.CreateFCmp(interfaces.FloatUNE, value, g.module.ConstFloat(fromType, 0), tempReg), nil
+		}
+		return g.builder.CreateICmp(interfaces.IntNE, value, g.module.ConstInt(fromType, 0), tempReg), nil
+	case toBasic.IsInteger():
+		op := interfaces.CastFPToSI
+		if fromBool {
+			op = interfaces.CastZExt
+		} else if toBasic.IsUnsigned() {
+			op = interfaces.CastFPToUI
+		}
+		return g.builder.CreateCast(op, value, toType, tempReg), nil
+	case toBasic.IsFloat():
+		var op interfaces.CastOpcode
+		switch {
+		case fromBool || fromBasic.IsUnsigned():
+			op = interfaces.CastUIToFP
+		case fromBasic.IsInteger():
+			op = interfaces.CastSIToFP
+		case fromBasic.Kind == domain.Float32Type:
+			op = interfaces.CastFPExt
+		default:
+			op = interfaces.CastFPTrunc
+		}
+		return g.builder.CreateCast(op, value, toType, tempReg), nil
 	default:
-		return "i32" // fallback
+		return nil, fmt.Errorf("cannot convert %s to %s", from, to)
 	}
 }
 
-func (g *Generator) getTypeAlign(t domain.Type) int {
-	switch t.String() {
-	case "int":
-		return 4
-	case "double":
-		return 8
-	case "string":
-		return 8
-	case "bool":
-		return 1
+// formatValue converts a numeric or bool value to a newly allocated string
+// through the runtime formatting functions
+func (g *Generator) formatValue(value interfaces.LLVMValue, from *domain.BasicType) interfaces.LLVMValue {
+	var formatter string
+	switch {
+	case from.Kind == domain.BoolType:
+		value = g.builder.CreateCast(interfaces.CastZExt, value, g.module.IntType(32), g.newTemp())
+		formatter = "sl_bool_to_string"
+	case from.IsFloat():
+		value = g.extendFloat(value, from)
+		formatter = "sl_float_to_string"
+	case from.IsUnsigned():
+		value = g.resizeInteger(value, from, domain.NewIntType())
+		formatter = "sl_uint_to_string"
+	default:
+		value = g.resizeInteger(value, from, domain.NewIntType())
+		formatter = "sl_int_to_string"
+	}
+
+	return g.builder.CreateCall(g.runtimeFunction(formatter), []interfaces.LLVMValue{value}, g.newTemp())
+}
+
+// isAddressable reports whether an expression denotes a memory location
+func (g *Generator) isAddressable(expr domain.Expression) bool {
+	switch e := expr.(type) {
+	case *domain.IdentifierExpr:
+		return true
+	case *domain.MemberExpr:
+		return g.isAddressable(e.Object)
+	case *domain.IndexExpr:
+		// Dynamic array elements live on the heap regardless of the object
+		if arrayType, ok := e.Object.GetType().(*domain.ArrayType); ok && arrayType.Size == -1 {
+			return true
+		}
+		return g.isAddressable(e.Object)
+	default:
+		return false
+	}
+}
+
+// getAddress emits the code computing the address of an assignable expression
+// and returns the resulting pointer
+func (g *Generator) getAddress(expr domain.Expression) (interfaces.LLVMValue, error) {
+	switch e := expr.(type) {
+	case *domain.IdentifierExpr:
+		return g.variableAddress(e.Name)
+	case *domain.MemberExpr:
+		return g.getMemberAddress(e)
+	case *domain.IndexExpr:
+		return g.getIndexAddress(e)
 	default:
-		return 4
+		return nil, fmt.Errorf("cannot take the address of %T", expr)
+	}
+}
+
+// getIndexAddress emits a getelementptr to the array element selected by an index expression
+func (g *Generator) getIndexAddress(node *domain.IndexExpr) (interfaces.LLVMValue, error) {
+	arrayType, ok := node.Object.GetType().(*domain.ArrayType)
+	if !ok {
+		return nil, fmt.Errorf("index access on non-array value")
+	}
+
+	// Locate the storage that holds the elements
+	var basePtr interfaces.LLVMValue
+	if arrayType.Size == -1 {
+		// Dynamic arrays are referenced through their {len, ptr} header
+		if err := node.Object.Accept(g); err != nil {
+			return nil, err
+		}
+		basePtr = g.currentValue
+	} else if g.isAddressable(node.Object) {
+		address, err := g.getAddress(node.Object)
+		if err != nil {
+			return nil, err
+		}
+		basePtr = address
+	} else {
+		// Fixed array values such as call results are spilled so they can be indexed
+		if err := node.Object.Accept(g); err != nil {
+			return nil, err
+		}
+		arrayValue := g.currentValue
+		basePtr = g.builder.CreateAlloca(g.getLLVMType(arrayType), g.newTemp())
+		g.builder.CreateStore(arrayValue, basePtr)
+	}
+
+	if err := node.Index.Accept(g); err != nil {
+		return nil, err
+	}
+	// Indices are widened to i64 so unsigned values are not sign-extended by getelementptr
+	index := g.resizeInteger(g.currentValue, node.Index.GetType(), domain.NewIntType())
+
+	if g.boundsChecks {
+		g.emitBoundsCheck(node, arrayType, basePtr, index)
+	}
+
+	if arrayType.Size == -1 {
+		dataField := g.builder.CreateInBoundsGEP(g.dynamicArrayHeader(), basePtr, g.fieldIndices(1), g.newTemp())
+		data := g.builder.CreateTypedLoad(g.module.PointerType(nil), dataField, g.newTemp())
+		elemType := g.getMemoryType(arrayType.ElementType)
+		return g.builder.CreateInBoundsGEP(elemType, data, []interfaces.LLVMValue{index}, g.newTemp()), nil
+	}
+
+	indices := []interfaces.LLVMValue{g.constInt64(0), index}
+	return g.builder.CreateInBoundsGEP(g.getLLVMType(arrayType), basePtr, indices, g.newTemp()), nil
+}
+
+// emitBoundsCheck traps with the source location of an index expression when
+// the index is outside [0, len). Negative indices wrap to large unsigned values,
+// so a single unsigned comparison covers both ends.
+func (g *Generator) emitBoundsCheck(node *domain.IndexExpr, arrayType *domain.ArrayType, basePtr, index interfaces.LLVMValue) {
+	length := g.constInt64(int64(arrayType.Size))
+	if arrayType.Size == -1 {
+		length = g.dynamicArrayLength(basePtr)
+	}
+
+	inRange := g.builder.CreateICmp(interfaces.IntULT, index, length, g.newTemp())
+	okBlock := g.newBlock("bounds.ok")
+	failBlock := g.newBlock("bounds.fail")
+	g.builder.CreateCondBr(inRange, okBlock, failBlock)
+
+	pos := node.Location.Start
+	i32 := g.module.IntType(32)
+	g.startBlock(failBlock)
+	g.builder.CreateCall(g.runtimeFunction("sl_bounds_check_failed"), []interfaces.LLVMValue{
+		g.sourceFileConstant(pos.Filename),
+		g.module.ConstInt(i32, int64(pos.Line)),
+		g.module.ConstInt(i32, int64(pos.Column)),
+		index,
+		length,
+	}, "")
+	g.builder.CreateUnreachable()
+	g.startBlock(okBlock)
+}
+
+// emitDivisionCheck traps with the source location of a division or remainder
+// when its integer divisor is zero
+func (g *Generator) emitDivisionCheck(node domain.Node, divisor interfaces.LLVMValue) {
+	zero := g.module.ConstInt(divisor.GetType(), 0)
+	nonZero := g.builder.CreateICmp(interfaces.IntNE, divisor, zero, g.newTemp())
+	okBlock := g.newBlock("div.ok")
+	failBlock := g.newBlock("div.fail")
+	g.builder.CreateCondBr(nonZero, okBlock, failBlock)
+
+	pos := node.GetLocation().Start
+	i32 := g.module.IntType(32)
+	g.startBlock(failBlock)
+	g.builder.CreateCall(g.runtimeFunction("sl_division_by_zero"), []interfaces.LLVMValue{
+		g.sourceFileConstant(pos.Filename),
+		g.module.ConstInt(i32, int64(pos.Line)),
+		g.module.ConstInt(i32, int64(pos.Column)),
+	}, "")
+	g.builder.CreateUnreachable()
+	g.startBlock(okBlock)
+}
+
+// dynamicArrayHeader returns the heap-allocated {len, ptr} header type behind a dynamic array value
+func (g *Generator) dynamicArrayHeader() interfaces.LLVMType {
+	return g.module.StructType([]interfaces.LLVMType{g.module.IntType(64), g.module.PointerType(nil)})
+}
+
+// fieldIndices returns the getelementptr indices selecting a struct field
+func (g *Generator) fieldIndices(field int) []interfaces.LLVMValue {
+	i32 := g.module.IntType(32)
+	return []interfaces.LLVMValue{g.module.ConstInt(i32, 0), g.module.ConstInt(i32, int64(field))}
+}
+
+// dynamicArrayLength loads the element count from a dynamic array header
+func (g *Generator) dynamicArrayLength(header interfaces.LLVMValue) interfaces.LLVMValue {
+	lenField := g.builder.CreateInBoundsGEP(g.dynamicArrayHeader(), header, g.fieldIndices(0), g.newTemp())
+	return g.builder.CreateTypedLoad(g.module.IntType(64), lenField, g.newTemp())
+}
+
+// allocDynamicArray allocates a dynamic array header and zeroed storage for
+// length elements on the heap, returning the header and data pointers
+func (g *Generator) allocDynamicArray(elementType domain.Type, length interfaces.LLVMValue) (interfaces.LLVMValue, interfaces.LLVMValue) {
+	allocArray := g.runtimeFunction("sl_alloc_array")
+	elemSize := g.module.ConstSizeOf(g.getMemoryType(elementType))
+	headerSize := g.module.ConstSizeOf(g.dynamicArrayHeader())
+
+	// Lengths are i64 in headers but size_t in the runtime
+	count := length
+	if sizeType := g.sizeType(); g.module.SizeOf(sizeType) < 8 {
+		count = g.builder.CreateCast(interfaces.CastTrunc, length, sizeType, g.newTemp())
+	}
+	data := g.builder.CreateCall(allocArray, []interfaces.LLVMValue{elemSize, count}, g.newTemp())
+	header := g.builder.CreateCall(allocArray, []interfaces.LLVMValue{headerSize, g.module.ConstInt(g.sizeType(), 1)}, g.newTemp())
+
+	lenField := g.builder.CreateInBoundsGEP(g.dynamicArrayHeader(), header, g.fieldIndices(0), g.newTemp())
+	g.builder.CreateStore(length, lenField)
+	dataField := g.builder.CreateInBoundsGEP(g.dynamicArrayHeader(), header, g.fieldIndices(1), g.newTemp())
+	g.builder.CreateStore(data, dataField)
+
+	return header, data
+}
+
+// initDynamicArrays points every dynamic array nested in the value of type t
+// at ptr to a new empty array
+func (g *Generator) initDynamicArrays(t domain.Type, ptr interfaces.LLVMValue) {
+	switch typ := t.(type) {
+	case *domain.ArrayType:
+		if typ.Size == -1 {
+			header, _ := g.allocDynamicArray(typ.ElementType, g.constInt64(0))
+			g.builder.CreateStore(header, ptr)
+			return
+		}
+		if !hasDynamicArrays(typ.ElementType) {
+			return
+		}
+
+		// Elements are initialized in a loop rather than unrolled
+		i64 := g.module.IntType(64)
+		counter := g.builder.CreateAlloca(i64, g.newTemp())
+		g.builder.CreateStore(g.constInt64(0), counter)
+		condBlock := g.newBlock("init.cond")
+		bodyBlock := g.newBlock("init.body")
+		endBlock := g.newBlock("init.end")
+
+		g.startBlock(condBlock)
+		index := g.builder.CreateTypedLoad(i64, counter, g.newTemp())
+		more := g.builder.CreateICmp(interfaces.IntULT, index, g.constInt64(int64(typ.Size)), g.newTemp())
+		g.builder.CreateCondBr(more, bodyBlock, endBlock)
+
+		g.startBlock(bodyBlock)
+		indices := []interfaces.LLVMValue{g.constInt64(0), index}
+		g.initDynamicArrays(typ.ElementType, g.builder.CreateInBoundsGEP(g.getLLVMType(typ), ptr, indices, g.newTemp()))
+		g.builder.CreateStore(g.builder.CreateAdd(index, g.constInt64(1), g.newTemp()), counter)
+		g.branchTo(condBlock)
+
+		g.startBlock(endBlock)
+	case *domain.StructType:
+		for i, name := range typ.Order {
+			if field := typ.Fields[name]; hasDynamicArrays(field) {
+				fieldPtr := g.builder.CreateInBoundsGEP(g.getLLVMType(typ), ptr, g.fieldIndices(i), g.newTemp())
+				g.initDynamicArrays(field, fieldPtr)
+			}
+		}
+	}
+}
+
+// emptyArraysConstant returns the zero value of type t with every dynamic
+// array in it referencing header. Headers are not written after they are
+// allocated, so the arrays can share one.
+func (g *Generator) emptyArraysConstant(t domain.Type, header interfaces.LLVMValue) interfaces.LLVMValue {
+	if !hasDynamicArrays(t) {
+		return g.module.ConstZero(g.getMemoryType(t))
+	}
+
+	var elems []interfaces.LLVMValue
+	switch typ := t.(type) {
+	case *domain.ArrayType:
+		if typ.Size == -1 {
+			return header
+		}
+		elem := g.emptyArraysConstant(typ.ElementType, header)
+		for i := 0; i < typ.Size; i++ {
+			elems = append(elems, elem)
+		}
+	case *domain.StructType:
+		for _, name := range typ.Order {
+			elems = append(elems, g.emptyArraysConstant(typ.Fields[name], header))
+		}
+	}
+	return g.module.ConstAggregate(g.getLLVMType(t), elems)
+}
+
+// coerceValue converts a value to the representation of an assignable target type.
+// Fixed-size arrays assigned to dynamic arrays are copied into a new heap array.
+func (g *Generator) coerceValue(value interfaces.LLVMValue, from, to domain.Type) interfaces.LLVMValue {
+	fromArray, ok := from.(*domain.ArrayType)
+	if !ok || fromArray.Size == -1 {
+		return value
+	}
+	toArray, ok := to.(*domain.ArrayType)
+	if !ok || toArray.Size != -1 {
+		return value
+	}
+
+	header, data := g.allocDynamicArray(fromArray.ElementType, g.constInt64(int64(fromArray.Size)))
+	g.builder.CreateStore(value, data)
+	return header
+}
+
+// getMemberAddress emits a getelementptr to the field selected by a member expression
+func (g *Generator) getMemberAddress(node *domain.MemberExpr) (interfaces.LLVMValue, error) {
+	structType, index, err := g.resolveField(node)
+	if err != nil {
+		return nil, err
+	}
+
+	basePtr, err := g.getAddress(node.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.builder.CreateInBoundsGEP(g.getLLVMType(structType), basePtr, g.fieldIndices(index), g.newTemp()), nil
+}
+
+// resolveField looks up the struct type and field index accessed by a member expression
+func (g *Generator) resolveField(node *domain.MemberExpr) (*domain.StructType, int, error) {
+	structType, ok := node.Object.GetType().(*domain.StructType)
+	if !ok {
+		return nil, 0, fmt.Errorf("member access on non-struct value")
+	}
+
+	for i, fieldName := range structType.Order {
+		if fieldName == node.Member {
+			return structType, i, nil
+		}
+	}
+
+	return nil, 0, fmt.Errorf("struct %s has no member %s", structType.Name, node.Member)
+}
+
+// Helper functions
+func (g *Generator) getLLVMType(t domain.Type) interfaces.LLVMType {
+	switch typ := t.(type) {
+	case *domain.StructType:
+		return g.module.NamedStructType("struct." + typ.Name)
+	case *domain.ArrayType:
+		if typ.Size == -1 {
+			// Dynamic arrays are pointers to a {len, ptr} header
+			return g.module.PointerType(nil)
+		}
+		return g.module.ArrayType(g.getMemoryType(typ.ElementType), typ.Size)
+	}
+
+	basic, ok := t.(*domain.BasicType)
+	if !ok {
+		return g.module.IntType(32) // fallback
+	}
+	if basic.IsInteger() {
+		return g.module.IntType(basic.GetSize() * 8)
+	}
+
+	switch basic.Kind {
+	case domain.FloatType:
+		return g.module.DoubleType()
+	case domain.Float32Type:
+		return g.module.FloatType()
+	case domain.StringType:
+		return g.stringType()
+	case domain.BoolType:
+		return g.module.IntType(1)
+	case domain.VoidType:
+		return g.module.VoidType()
+	default:
+		return g.module.IntType(32) // fallback
+	}
+}
+
+// getMemoryType returns the LLVM type values of t are stored as. Bools are i1
+// values but take a byte in memory, as in C.
+func (g *Generator) getMemoryType(t domain.Type) interfaces.LLVMType {
+	if isBoolType(t) {
+		return g.module.IntType(8)
+	}
+	return g.getLLVMType(t)
+}
+
+// loadValue loads a value of type t from memory
+func (g *Generator) loadValue(t domain.Type, ptr interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	if !isBoolType(t) {
+		return g.builder.CreateTypedLoad(g.getLLVMType(t), ptr, name)
+	}
+	stored := g.builder.CreateTypedLoad(g.getMemoryType(t), ptr, g.newTemp())
+	return g.builder.CreateCast(interfaces.CastTrunc, stored, g.getLLVMType(t), name)
+}
+
+// storeValue stores a value of type t to memory
+func (g *Generator) storeValue(t domain.Type, value, ptr interfaces.LLVMValue) {
+	if isBoolType(t) {
+		value = g.builder.CreateCast(interfaces.CastZExt, value, g.getMemoryType(t), g.newTemp())
+	}
+	g.builder.CreateStore(value, ptr)
+}
+
+// stringType returns the C string type strings are represented as
+func (g *Generator) stringType() interfaces.LLVMType {
+	return g.module.PointerType(g.module.IntType(8))
+}
+
+// sizeType returns the integer type of C's size_t, which is as wide as a
+// pointer on the target
+func (g *Generator) sizeType() interfaces.LLVMType {
+	return g.module.IntType(g.module.SizeOf(g.module.PointerType(nil)) * 8)
+}
+
+// constInt64 returns an i64 constant
+func (g *Generator) constInt64(value int64) interfaces.LLVMValue {
+	return g.module.ConstInt(g.module.IntType(64), value)
+}
+
+// isStringType reports whether t is the builtin string type
+func isStringType(t domain.Type) bool {
+	basic, ok := t.(*domain.BasicType)
+	return ok && basic.Kind == domain.StringType
+}
+
+// isAggregateType reports whether t is a fixed-size array or struct type
+func isAggregateType(t domain.Type) bool {
+	switch typ := t.(type) {
+	case *domain.ArrayType:
+		return typ.Size != -1
+	case *domain.StructType:
+		return true
+	}
+	return false
+}
+
+// hasDynamicArrays reports whether values of type t are or contain dynamic arrays
+func hasDynamicArrays(t domain.Type) bool {
+	switch typ := t.(type) {
+	case *domain.ArrayType:
+		return typ.Size == -1 || hasDynamicArrays(typ.ElementType)
+	case *domain.StructType:
+		for _, name := range typ.Order {
+			if hasDynamicArrays(typ.Fields[name]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isBoolType reports whether t is the builtin bool type
+func isBoolType(t domain.Type) bool {
+	basic, ok := t.(*domain.BasicType)
+	return ok && basic.Kind == domain.BoolType
+}
+
+// isVoidType reports whether t is the builtin void type
+func isVoidType(t domain.Type) bool {
+	basic, ok := t.(*domain.BasicType)
+	return ok && basic.Kind == domain.VoidType
+}
+
+// extendFloat widens an f32 value to double; double values are returned unchanged
+func (g *Generator) extendFloat(value interfaces.LLVMValue, from domain.Type) interfaces.LLVMValue {
+	if basic, ok := from.(*domain.BasicType); !ok || basic.Kind != domain.Float32Type {
+		return value
+	}
+	return g.builder.CreateCast(interfaces.CastFPExt, value, g.module.DoubleType(), g.newTemp())
+}
+
+// widenIntegerFormats rewrites %d and %i conversions to their 64-bit forms
+func widenIntegerFormats(format string) string {
+	var sb strings.Builder
+	for i := 0; i < len(format); i++ {
+		sb.WriteByte(format[i])
+		if format[i] != '%' || i+1 >= len(format) {
+			continue
+		}
+		switch format[i+1] {
+		case 'd', 'i':
+			sb.WriteString("ll")
+		case '%':
+			sb.WriteByte('%')
+			i++
+		}
 	}
+	return sb.String()
 }
 
 // parseFormatString analyzes a printf-style format string and returns expected argument types
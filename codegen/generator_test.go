@@ -1,12 +1,47 @@
 package codegen
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	"github.com/sokoide/llvm5/internal/domain"
+	"github.com/sokoide/llvm5/internal/infrastructure/llvmir"
+	"github.com/sokoide/llvm5/internal/interfaces"
 )
 
+// newModuleGenerator returns a generator filling an empty llvmir module
+func newModuleGenerator() *Generator {
+	generator := NewGenerator()
+	generator.beginModule(llvmir.NewModule("test", "x86_64-apple-macosx10.15.0"))
+	return generator
+}
+
+// newTestGenerator returns a generator positioned in the entry block of a
+// scratch function, with the variables used by the tests bound to pointers
+func newTestGenerator() *Generator {
+	generator := newModuleGenerator()
+	module := generator.module
+	generator.function = module.AddFunction("test", module.FunctionType(module.VoidType(), nil, false))
+	generator.builder.PositionAtEnd(generator.function.CreateBasicBlock("entry"))
+	for _, name := range []string{"a", "b", "c", "i", "v", "x", "arr", "p", "fixed"} {
+		generator.declareLocal(name, nil, interfaces.VariableSymbol, &llvmir.Param{Name: name, Typ: llvmir.Ptr})
+	}
+	return generator
+}
+
+// irText prints the module a generator fills
+func irText(generator *Generator) string {
+	var sb strings.Builder
+	generator.module.Print(&sb)
+	return sb.String()
+}
+
+// ident returns how a value is referenced as an operand
+func ident(value interfaces.LLVMValue) string {
+	return value.(llvmir.Value).Ident()
+}
+
 // TestNewGenerator tests the constructor
 func TestNewGenerator(t *testing.T) {
 	generator := NewGenerator()
@@ -19,28 +54,8 @@ func TestNewGenerator(t *testing.T) {
 		t.Error("New generator should have labelCounter initialized to 0")
 	}
 	
-	if generator.parameters == nil {
-		t.Error("New generator should have parameters map initialized")
-	}
-}
-
-// TestEmitMethods tests the emit helper methods
-func TestEmitMethods(t *testing.T) {
-	generator := NewGenerator()
-	
-	// Test emit method
-	generator.emit("test instruction %s", "arg1")
-	output := generator.output.String()
-	if !strings.Contains(output, "test instruction arg1") {
-		t.Error("emit should format and write the instruction")
-	}
-	
-	// Test emitRaw method
-	generator.output.Reset()
-	generator.emitRaw("raw text")
-	output = generator.output.String()
-	if output != "raw text" {
-		t.Error("emitRaw should write raw text without formatting")
+	if generator.locals == nil {
+		t.Error("New generator should have locals map initialized")
 	}
 }
 
@@ -64,22 +79,42 @@ func TestNewLabel(t *testing.T) {
 	}
 }
 
+// TestLocalNames tests that value and block names are unique per function
+func TestLocalNames(t *testing.T) {
+	names := newLocalNames()
+	for _, expected := range []string{"x", "x.1", "x.2"} {
+		if got := names.unique("x"); got != expected {
+			t.Errorf("unique(x) = %s, expected %s", got, expected)
+		}
+	}
+	if got := names.unique("x.1"); got != "x.1.1" {
+		t.Errorf("unique(x.1) = %s, expected x.1.1", got)
+	}
+
+	// Temporaries skip names taken by source variables
+	generator := NewGenerator()
+	generator.names.unique("temp_0")
+	if temp := generator.newTemp(); temp == "temp_0" {
+		t.Error("newTemp should not reuse a variable name")
+	}
+}
+
 // TestGetLLVMType tests LLVM type conversion
 func TestGetLLVMType(t *testing.T) {
-	generator := NewGenerator()
+	generator := newModuleGenerator()
 	
 	tests := []struct {
 		domainType   domain.Type
 		expectedLLVM string
 	}{
-		{domain.NewIntType(), "i32"},
+		{domain.NewIntType(), "i64"},
 		{domain.NewBoolType(), "i1"},
-		{domain.NewStringType(), "i8*"},
+		{domain.NewStringType(), "ptr"},
 		{domain.NewVoidType(), "void"},
 	}
 	
 	for _, test := range tests {
-		result := generator.getLLVMType(test.domainType)
+		result := fmt.Sprint(generator.getLLVMType(test.domainType))
 		if result != test.expectedLLVM {
 			t.Errorf("getLLVMType(%s) = %s, expected %s", 
 				test.domainType.String(), result, test.expectedLLVM)
@@ -87,28 +122,6 @@ func TestGetLLVMType(t *testing.T) {
 	}
 }
 
-// TestGetTypeAlign tests type alignment calculation
-func TestGetTypeAlign(t *testing.T) {
-	generator := NewGenerator()
-	
-	tests := []struct {
-		domainType    domain.Type
-		expectedAlign int
-	}{
-		{domain.NewIntType(), 4},
-		{domain.NewBoolType(), 1},
-		{domain.NewStringType(), 8},
-	}
-	
-	for _, test := range tests {
-		result := generator.getTypeAlign(test.domainType)
-		if result != test.expectedAlign {
-			t.Errorf("getTypeAlign(%s) = %d, expected %d", 
-				test.domainType.String(), result, test.expectedAlign)
-		}
-	}
-}
-
 // TestParseFormatString tests format string parsing
 func TestParseFormatString(t *testing.T) {
 	generator := NewGenerator()
@@ -192,21 +205,22 @@ func TestVisitBinaryExpr(t *testing.T) {
 		right    interface{}
 		expected string
 	}{
-		{"add_int", domain.Add, int64(5), int64(3), "add i32"},
-		{"sub_int", domain.Sub, int64(10), int64(2), "sub i32"},
-		{"mul_int", domain.Mul, int64(4), int64(3), "mul i32"},
-		{"div_int", domain.Div, int64(12), int64(3), "sdiv i32"},
-		{"eq_int", domain.Eq, int64(5), int64(5), "icmp eq i32"},
-		{"ne_int", domain.Ne, int64(5), int64(3), "icmp ne i32"},
-		{"lt_int", domain.Lt, int64(3), int64(5), "icmp slt i32"},
-		{"gt_int", domain.Gt, int64(5), int64(3), "icmp sgt i32"},
-		{"le_int", domain.Le, int64(3), int64(5), "icmp sle i32"},
-		{"ge_int", domain.Ge, int64(5), int64(3), "icmp sge i32"},
+		{"add_int", domain.Add, int64(5), int64(3), "add i64"},
+		{"sub_int", domain.Sub, int64(10), int64(2), "sub i64"},
+		{"mul_int", domain.Mul, int64(4), int64(3), "mul i64"},
+		{"div_int", domain.Div, int64(12), int64(3), "sdiv i64"},
+		{"mod_int", domain.Mod, int64(12), int64(5), "srem i64"},
+		{"eq_int", domain.Eq, int64(5), int64(5), "icmp eq i64"},
+		{"ne_int", domain.Ne, int64(5), int64(3), "icmp ne i64"},
+		{"lt_int", domain.Lt, int64(3), int64(5), "icmp slt i64"},
+		{"gt_int", domain.Gt, int64(5), int64(3), "icmp sgt i64"},
+		{"le_int", domain.Le, int64(3), int64(5), "icmp sle i64"},
+		{"ge_int", domain.Ge, int64(5), int64(3), "icmp sge i64"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			generator := NewGenerator()
+			generator := newTestGenerator()
 			
 			left := &domain.LiteralExpr{
 				Type_: domain.NewIntType(),
@@ -232,7 +246,98 @@ func TestVisitBinaryExpr(t *testing.T) {
 				t.Fatalf("VisitBinaryExpr failed: %v", err)
 			}
 			
-			output := generator.output.String()
+			output := irText(generator)
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q in output, got: %s", tt.expected, output)
+			}
+		})
+	}
+}
+
+// TestVisitLogicalExpr tests short-circuit lowering of && and ||
+func TestVisitLogicalExpr(t *testing.T) {
+	testCases := []struct {
+		op       domain.BinaryOperator
+		branch   string
+		phiShort string
+	}{
+		{domain.And, "br i1 %temp_0, label %land.rhs3, label %land.end4", "[ false, %entry ]"},
+		{domain.Or, "br i1 %temp_0, label %lor.end4, label %lor.rhs3", "[ true, %entry ]"},
+	}
+	
+	for _, tc := range testCases {
+		generator := newTestGenerator()
+		
+		left := &domain.IdentifierExpr{Name: "a"}
+		left.SetType(domain.NewBoolType())
+		right := &domain.IdentifierExpr{Name: "b"}
+		right.SetType(domain.NewBoolType())
+		expr := &domain.BinaryExpr{Left: left, Operator: tc.op, Right: right}
+		expr.SetType(domain.NewBoolType())
+		
+		if err := expr.Accept(generator); err != nil {
+			t.Fatalf("VisitBinaryExpr failed for %v: %v", tc.op, err)
+		}
+		
+		output := irText(generator)
+		if !strings.Contains(output, tc.branch) {
+			t.Errorf("Expected conditional branch %q, got: %s", tc.branch, output)
+		}
+		if !strings.Contains(output, "phi i1 "+tc.phiShort+", [ %temp_4, %") {
+			t.Errorf("Expected phi merging both paths, got: %s", output)
+		}
+		if resultType := fmt.Sprint(generator.currentValue.GetType()); resultType != "i1" {
+			t.Errorf("Expected i1 result, got %s", resultType)
+		}
+	}
+}
+
+// TestSizedIntegerBinaryExpr tests width and signedness of sized integer operations
+func TestSizedIntegerBinaryExpr(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     domain.BasicTypeKind
+		op       domain.BinaryOperator
+		expected string
+	}{
+		{"div_i32", domain.Int32Type, domain.Div, "sdiv i32"},
+		{"div_u32", domain.UInt32Type, domain.Div, "udiv i32"},
+		{"mod_i32", domain.Int32Type, domain.Mod, "srem i32"},
+		{"mod_u32", domain.UInt32Type, domain.Mod, "urem i32"},
+		{"mod_float", domain.FloatType, domain.Mod, "frem double"},
+		{"add_u8", domain.UInt8Type, domain.Add, "add i8"},
+		{"lt_i16", domain.Int16Type, domain.Lt, "icmp slt i16"},
+		{"lt_u64", domain.UInt64Type, domain.Lt, "icmp ult i64"},
+		{"ge_u16", domain.UInt16Type, domain.Ge, "icmp uge i16"},
+		{"and_u8", domain.UInt8Type, domain.BitAnd, "and i8"},
+		{"or_i32", domain.Int32Type, domain.BitOr, "or i32"},
+		{"xor_u16", domain.UInt16Type, domain.BitXor, "xor i16"},
+		{"shl_int", domain.IntType, domain.Shl, "shl i64"},
+		{"shr_i32", domain.Int32Type, domain.Shr, "ashr i32"},
+		{"shr_u32", domain.UInt32Type, domain.Shr, "lshr i32"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := newTestGenerator()
+			operandType := &domain.BasicType{Kind: tt.kind}
+
+			left := &domain.IdentifierExpr{Name: "a"}
+			left.SetType(operandType)
+			right := &domain.IdentifierExpr{Name: "b"}
+			right.SetType(operandType)
+			expr := &domain.BinaryExpr{Left: left, Operator: tt.op, Right: right}
+			if tt.op == domain.Lt || tt.op == domain.Ge {
+				expr.SetType(domain.NewBoolType())
+			} else {
+				expr.SetType(operandType)
+			}
+
+			if err := generator.VisitBinaryExpr(expr); err != nil {
+				t.Fatalf("VisitBinaryExpr failed: %v", err)
+			}
+
+			output := irText(generator)
 			if !strings.Contains(output, tt.expected) {
 				t.Errorf("Expected %q in output, got: %s", tt.expected, output)
 			}
@@ -240,6 +345,269 @@ func TestVisitBinaryExpr(t *testing.T) {
 	}
 }
 
+// TestShiftCountWidth tests that shift counts are resized to the shifted type
+func TestShiftCountWidth(t *testing.T) {
+	generator := newTestGenerator()
+	byteType := &domain.BasicType{Kind: domain.UInt8Type}
+
+	value := &domain.IdentifierExpr{Name: "b"}
+	value.SetType(byteType)
+	count := &domain.IdentifierExpr{Name: "i"}
+	count.SetType(domain.NewIntType())
+	expr := &domain.BinaryExpr{Left: value, Operator: domain.Shr, Right: count}
+	expr.SetType(byteType)
+
+	if err := generator.VisitBinaryExpr(expr); err != nil {
+		t.Fatalf("VisitBinaryExpr failed: %v", err)
+	}
+
+	output := irText(generator)
+	expected := []string{
+		"%temp_2 = trunc i64 %temp_1 to i8",
+		"%temp_3 = lshr i8 %temp_0, %temp_2",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+// TestFloatCodegen tests double and f32 lowering
+func TestFloatCodegen(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     domain.BasicTypeKind
+		llvmType string
+		mul      string
+		literal  string
+	}{
+		{"float", domain.FloatType, "double", "fmul double", "0x3FB999999999999A"},
+		{"f32", domain.Float32Type, "float", "fmul float", "0x3FB99999A0000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := newTestGenerator()
+			floatType := &domain.BasicType{Kind: tt.kind}
+
+			if got := fmt.Sprint(generator.getLLVMType(floatType)); got != tt.llvmType {
+				t.Errorf("getLLVMType() = %s, want %s", got, tt.llvmType)
+			}
+
+			left := &domain.IdentifierExpr{Name: "a"}
+			left.SetType(floatType)
+			right := &domain.LiteralExpr{Value: 0.1}
+			right.SetType(floatType)
+			expr := &domain.BinaryExpr{Left: left, Operator: domain.Mul, Right: right}
+			expr.SetType(floatType)
+
+			if err := generator.VisitBinaryExpr(expr); err != nil {
+				t.Fatalf("VisitBinaryExpr failed: %v", err)
+			}
+
+			output := irText(generator)
+			if !strings.Contains(output, tt.mul+" %temp_0, "+tt.literal) {
+				t.Errorf("Expected %q with constant %s, got: %s", tt.mul, tt.literal, output)
+			}
+		})
+	}
+}
+
+// TestVisitCastExpr tests lowering of explicit conversions
+func TestVisitCastExpr(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     domain.Type
+		to       domain.Type
+		expected string
+	}{
+		{"int_to_float", domain.NewIntType(), domain.NewFloatType(), "sitofp i64 %temp_0 to double"},
+		{"u32_to_float", &domain.BasicType{Kind: domain.UInt32Type}, domain.NewFloatType(), "uitofp i32 %temp_0 to double"},
+		{"float_to_int", domain.NewFloatType(), domain.NewIntType(), "fptosi double %temp_0 to i64"},
+		{"float_to_u8", domain.NewFloatType(), &domain.BasicType{Kind: domain.UInt8Type}, "fptoui double %temp_0 to i8"},
+		{"int_to_i16", domain.NewIntType(), &domain.BasicType{Kind: domain.Int16Type}, "trunc i64 %temp_0 to i16"},
+		{"u8_to_int", &domain.BasicType{Kind: domain.UInt8Type}, domain.NewIntType(), "zext i8 %temp_0 to i64"},
+		{"float_to_f32", domain.NewFloatType(), &domain.BasicType{Kind: domain.Float32Type}, "fptrunc double %temp_0 to float"},
+		{"bool_to_int", domain.NewBoolType(), domain.NewIntType(), "zext i1 %temp_0 to i64"},
+		{"int_to_bool", domain.NewIntType(), domain.NewBoolType(), "icmp ne i64 %temp_0, 0"},
+		{"int_to_string", domain.NewIntType(), domain.NewStringType(), "call ptr @sl_int_to_string(i64 %temp_0)"},
+		{"f32_to_string", &domain.BasicType{Kind: domain.Float32Type}, domain.NewStringType(), "call ptr @sl_float_to_string(double %temp_1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := newTestGenerator()
+
+			value := &domain.IdentifierExpr{Name: "v"}
+			value.SetType(tt.from)
+			cast := &domain.CastExpr{Target: tt.to, Value: value}
+			cast.SetType(tt.to)
+
+			if err := generator.VisitCastExpr(cast); err != nil {
+				t.Fatalf("VisitCastExpr failed: %v", err)
+			}
+
+			output := irText(generator)
+			if !strings.Contains(output, tt.expected) {
+				t.Errorf("Expected %q in output, got: %s", tt.expected, output)
+			}
+			if got, want := fmt.Sprint(generator.currentValue.GetType()), fmt.Sprint(generator.getLLVMType(tt.to)); got != want {
+				t.Errorf("Expected result type %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+// TestStringOperators tests that string operators call the runtime
+func TestStringOperators(t *testing.T) {
+	tests := []struct {
+		name     string
+		op       domain.BinaryOperator
+		expected []string
+	}{
+		{"concat", domain.Add, []string{"%temp_2 = call ptr @sl_concat_string(ptr %temp_0, ptr %temp_1)"}},
+		{"eq", domain.Eq, []string{"%temp_3 = call i32 @sl_compare_string(ptr %temp_0, ptr %temp_1)", "%temp_2 = icmp eq i32 %temp_3, 0"}},
+		{"lt", domain.Lt, []string{"icmp slt i32 %temp_3, 0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := newTestGenerator()
+
+			left := &domain.IdentifierExpr{Name: "a"}
+			left.SetType(domain.NewStringType())
+			right := &domain.IdentifierExpr{Name: "b"}
+			right.SetType(domain.NewStringType())
+			expr := &domain.BinaryExpr{Left: left, Operator: tt.op, Right: right}
+			if tt.op == domain.Add {
+				expr.SetType(domain.NewStringType())
+			} else {
+				expr.SetType(domain.NewBoolType())
+			}
+
+			if err := generator.VisitBinaryExpr(expr); err != nil {
+				t.Fatalf("VisitBinaryExpr failed: %v", err)
+			}
+
+			output := irText(generator)
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected %q in output, got: %s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+// TestLenFunction tests len on strings and arrays
+func TestLenFunction(t *testing.T) {
+	tests := []struct {
+		name     string
+		argType  domain.Type
+		expected string
+		value    string
+	}{
+		{"string", domain.NewStringType(), "call i64 @sl_string_length(ptr %temp_0)", "%temp_1"},
+		{"fixed_array", &domain.ArrayType{ElementType: domain.NewIntType(), Size: 4}, "", "4"},
+		{"dynamic_array", &domain.ArrayType{ElementType: domain.NewIntType(), Size: -1}, "load i64, ptr %temp_1, align 8", "%temp_2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generator := newTestGenerator()
+
+			arg := &domain.IdentifierExpr{Name: "v"}
+			arg.SetType(tt.argType)
+			call := &domain.CallExpr{Function: &domain.IdentifierExpr{Name: "len"}, Args: []domain.Expression{arg}}
+			call.SetType(domain.NewIntType())
+
+			if err := generator.VisitCallExpr(call); err != nil {
+				t.Fatalf("VisitCallExpr failed: %v", err)
+			}
+
+			if !strings.Contains(irText(generator), tt.expected) {
+				t.Errorf("Expected %q in output, got: %s", tt.expected, irText(generator))
+			}
+			if got, gotType := ident(generator.currentValue), fmt.Sprint(generator.currentValue.GetType()); got != tt.value || gotType != "i64" {
+				t.Errorf("Expected i64 %s, got %s %s", tt.value, gotType, got)
+			}
+		})
+	}
+}
+
+// TestStringPool tests deduplication, escaping and module-level emission of string literals
+func TestStringPool(t *testing.T) {
+	generator := NewGenerator()
+
+	literal := func(value string) *domain.ExprStmt {
+		lit := &domain.LiteralExpr{Value: value}
+		lit.SetType(domain.NewStringType())
+		call := &domain.CallExpr{Function: &domain.IdentifierExpr{Name: "print"}, Args: []domain.Expression{lit}}
+		call.SetType(domain.NewVoidType())
+		return &domain.ExprStmt{Expression: call}
+	}
+	fn := &domain.FunctionDecl{
+		Name:       "main",
+		ReturnType: domain.NewVoidType(),
+		Body: &domain.BlockStmt{Statements: []domain.Statement{
+			literal("say \"hi\"\n"),
+			literal("héllo"),
+			literal("say \"hi\"\n"),
+		}},
+	}
+
+	output, err := generator.Generate(&domain.Program{Declarations: []domain.Declaration{fn}})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	expected := []string{
+		`@.str.0 = private unnamed_addr constant [10 x i8] c"say \22hi\22\0A\00", align 1`,
+		`@.str.1 = private unnamed_addr constant [7 x i8] c"h\C3\A9llo\00", align 1`,
+		"call void @sl_print_string(ptr getelementptr inbounds ([10 x i8], ptr @.str.0, i64 0, i64 0))",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if strings.Count(output, "@.str.0 = ") != 1 || strings.Contains(output, "@.str.2") {
+		t.Errorf("Expected identical literals to share one constant, got: %s", output)
+	}
+	if strings.Index(output, "@.str.0 = ") > strings.Index(output, "define") {
+		t.Errorf("Expected string constants at module scope before the functions, got: %s", output)
+	}
+}
+
+// TestMainReturnsInt32 tests that main keeps a C-compatible exit code
+func TestMainReturnsInt32(t *testing.T) {
+	generator := newModuleGenerator()
+
+	value := &domain.IdentifierExpr{Name: "code"}
+	value.SetType(domain.NewIntType())
+	fn := &domain.FunctionDecl{
+		Name:       "main",
+		ReturnType: domain.NewIntType(),
+		Body: &domain.BlockStmt{Statements: []domain.Statement{
+			&domain.VarDeclStmt{Name: "code", Type_: domain.NewIntType()},
+			&domain.ReturnStmt{Value: value},
+		}},
+	}
+
+	if err := generator.VisitFunctionDecl(fn); err != nil {
+		t.Fatalf("VisitFunctionDecl failed: %v", err)
+	}
+
+	output := irText(generator)
+	if !strings.Contains(output, "define i32 @main()") {
+		t.Errorf("Expected main to return i32, got: %s", output)
+	}
+	if !strings.Contains(output, "trunc i64") || !strings.Contains(output, "ret i32") {
+		t.Errorf("Expected exit code truncated to i32, got: %s", output)
+	}
+}
+
 // TestVisitUnaryExpr tests unary expression code generation
 func TestVisitUnaryExpr(t *testing.T) {
 	tests := []struct {
@@ -249,13 +617,15 @@ func TestVisitUnaryExpr(t *testing.T) {
 		type_    domain.Type
 		expected string
 	}{
-		{"neg_int", domain.Neg, int64(5), domain.NewIntType(), "sub i32 0"},
-		{"not_bool", domain.Not, true, domain.NewBoolType(), "icmp eq i1"},
+		{"neg_int", domain.Neg, int64(5), domain.NewIntType(), "sub i64 0, 5"},
+		{"neg_float", domain.Neg, 1.5, domain.NewFloatType(), "fneg double 0x3FF8000000000000"},
+		{"not_bool", domain.Not, true, domain.NewBoolType(), "xor i1 true, true"},
+		{"bitnot_int", domain.BitNot, int64(5), domain.NewIntType(), "xor i64 5, -1"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			generator := NewGenerator()
+			generator := newTestGenerator()
 			
 			operand := &domain.LiteralExpr{
 				Type_: tt.type_,
@@ -274,7 +644,7 @@ func TestVisitUnaryExpr(t *testing.T) {
 				t.Fatalf("VisitUnaryExpr failed: %v", err)
 			}
 			
-			output := generator.output.String()
+			output := irText(generator)
 			if !strings.Contains(output, tt.expected) {
 				t.Errorf("Expected %q in output, got: %s", tt.expected, output)
 			}
@@ -282,6 +652,38 @@ func TestVisitUnaryExpr(t *testing.T) {
 	}
 }
 
+// TestNestedUnaryExpr tests that each unary operator gets its own register
+func TestNestedUnaryExpr(t *testing.T) {
+	generator := newTestGenerator()
+
+	// -(-(a + b))
+	a := &domain.IdentifierExpr{Name: "a"}
+	a.SetType(domain.NewIntType())
+	b := &domain.IdentifierExpr{Name: "b"}
+	b.SetType(domain.NewIntType())
+	sum := &domain.BinaryExpr{Left: a, Operator: domain.Add, Right: b}
+	sum.SetType(domain.NewIntType())
+	inner := &domain.UnaryExpr{Operator: domain.Neg, Operand: sum}
+	inner.SetType(domain.NewIntType())
+	outer := &domain.UnaryExpr{Operator: domain.Neg, Operand: inner}
+	outer.SetType(domain.NewIntType())
+
+	if err := outer.Accept(generator); err != nil {
+		t.Fatalf("VisitUnaryExpr failed: %v", err)
+	}
+	innerValue := "%temp_3 = sub i64 0, %temp_2"
+	outerValue := "%temp_4 = sub i64 0, %temp_3"
+	output := irText(generator)
+	for _, e := range []string{innerValue, outerValue} {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if ident(generator.currentValue) != "%temp_4" {
+		t.Errorf("Expected the outer negation as the current value, got %s", ident(generator.currentValue))
+	}
+}
+
 // TestVisitLiteralExpr tests literal expression code generation
 func TestVisitLiteralExpr(t *testing.T) {
 	tests := []struct {
@@ -297,7 +699,7 @@ func TestVisitLiteralExpr(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			generator := NewGenerator()
+			generator := newTestGenerator()
 			
 			literal := &domain.LiteralExpr{
 				Type_: tt.type_,
@@ -311,10 +713,14 @@ func TestVisitLiteralExpr(t *testing.T) {
 			}
 			
 			if tt.name == "string_literal" {
-				output := generator.output.String()
-				if !strings.Contains(output, "hello") {
+				// String constants are pooled and emitted at module scope, not inline
+				if _, ok := generator.stringPool["hello"]; !ok {
 					t.Errorf("Expected string literal processing")
 				}
+				output := irText(generator)
+				if strings.Contains(output[strings.Index(output, "define"):], "hello") {
+					t.Errorf("String constant should not be emitted inside the function")
+				}
 			}
 		})
 	}
@@ -322,10 +728,10 @@ func TestVisitLiteralExpr(t *testing.T) {
 
 // TestVisitIdentifierExpr tests identifier expression code generation
 func TestVisitIdentifierExpr(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
 	// Set up a parameter so the identifier resolution works
-	generator.parameters["x"] = true
+	generator.declareLocal("x", nil, interfaces.ParameterSymbol, &llvmir.Param{Name: "x.addr", Typ: llvmir.Ptr})
 	
 	identifier := &domain.IdentifierExpr{
 		Name:  "x",
@@ -338,8 +744,8 @@ func TestVisitIdentifierExpr(t *testing.T) {
 		t.Fatalf("VisitIdentifierExpr failed: %v", err)
 	}
 	
-	output := generator.output.String()
-	if !strings.Contains(output, "load i32") {
+	output := irText(generator)
+	if !strings.Contains(output, "load i64") {
 		t.Error("Expected load instruction for identifier")
 	}
 	if !strings.Contains(output, "%x.addr") {
@@ -357,12 +763,12 @@ func TestVisitReturnStmt(t *testing.T) {
 		expected  string
 	}{
 		{"return_void", false, nil, nil, "ret void"},
-		{"return_int", true, int64(42), domain.NewIntType(), "ret i32"},
+		{"return_int", true, int64(42), domain.NewIntType(), "ret i64"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			generator := NewGenerator()
+			generator := newTestGenerator()
 			
 			var returnStmt *domain.ReturnStmt
 			if tt.hasValue {
@@ -381,7 +787,7 @@ func TestVisitReturnStmt(t *testing.T) {
 				t.Fatalf("VisitReturnStmt failed: %v", err)
 			}
 			
-			output := generator.output.String()
+			output := irText(generator)
 			if !strings.Contains(output, tt.expected) {
 				t.Errorf("Expected %q in output, got: %s", tt.expected, output)
 			}
@@ -391,7 +797,7 @@ func TestVisitReturnStmt(t *testing.T) {
 
 // TestVisitVarDeclStmt tests variable declaration code generation
 func TestVisitVarDeclStmt(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
 	initializer := &domain.LiteralExpr{
 		Type_: domain.NewIntType(),
@@ -410,18 +816,18 @@ func TestVisitVarDeclStmt(t *testing.T) {
 		t.Fatalf("VisitVarDeclStmt failed: %v", err)
 	}
 	
-	output := generator.output.String()
-	if !strings.Contains(output, "%x = alloca i32") {
+	output := irText(generator)
+	if !strings.Contains(output, "%x = alloca i64") {
 		t.Error("Expected variable allocation")
 	}
-	if !strings.Contains(output, "store i32") {
+	if !strings.Contains(output, "store i64") {
 		t.Error("Expected variable initialization")
 	}
 }
 
 // TestVisitAssignStmt tests assignment statement code generation
 func TestVisitAssignStmt(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
 	target := &domain.IdentifierExpr{
 		Name:  "x",
@@ -445,16 +851,59 @@ func TestVisitAssignStmt(t *testing.T) {
 		t.Fatalf("VisitAssignStmt failed: %v", err)
 	}
 	
-	output := generator.output.String()
-	if !strings.Contains(output, "store i32") {
+	output := irText(generator)
+	if !strings.Contains(output, "store i64") {
 		t.Error("Expected store instruction for assignment")
 	}
 }
 
+// TestBoolCodegen tests that bools are i1 values held in i8 memory
+func TestBoolCodegen(t *testing.T) {
+	generator := newTestGenerator()
+	
+	initializer := &domain.LiteralExpr{Value: true}
+	initializer.SetType(domain.NewBoolType())
+	varDecl := &domain.VarDeclStmt{Name: "flag", Type_: domain.NewBoolType(), Initializer: initializer}
+	if err := varDecl.Accept(generator); err != nil {
+		t.Fatalf("VisitVarDeclStmt failed: %v", err)
+	}
+	
+	flag := &domain.IdentifierExpr{Name: "flag"}
+	flag.SetType(domain.NewBoolType())
+	printCall := &domain.CallExpr{Function: &domain.IdentifierExpr{Name: "print"}, Args: []domain.Expression{flag}}
+	printCall.SetType(domain.NewVoidType())
+	if err := generator.handlePrintFunction(printCall); err != nil {
+		t.Fatalf("handlePrintFunction failed: %v", err)
+	}
+	
+	output := irText(generator)
+	expected := []string{
+		"%flag = alloca i8",
+		"store i8 1, ptr %flag",
+		"load i8, ptr %flag",
+		"%temp_1 = trunc i8 %temp_2 to i1",
+		"zext i1 %temp_1 to i32",
+		"call void @sl_print_bool(i32 %temp_3)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	
+	// Conditions branch on the loaded i1 directly
+	ifStmt := &domain.IfStmt{Condition: flag, ThenStmt: &domain.BlockStmt{}}
+	if err := ifStmt.Accept(generator); err != nil {
+		t.Fatalf("VisitIfStmt failed: %v", err)
+	}
+	if output := irText(generator); !strings.Contains(output, "br i1 %temp_7, label %if.then") {
+		t.Errorf("Expected a branch on the bool value, got: %s", output)
+	}
+}
+
 // TestVisitIfStmt tests if statement code generation
 func TestVisitIfStmt(t *testing.T) {
-	generator := NewGenerator()
-	generator.indentLevel = 1
+	generator := newTestGenerator()
 	
 	condition := &domain.LiteralExpr{
 		Type_: domain.NewBoolType(),
@@ -489,7 +938,7 @@ func TestVisitIfStmt(t *testing.T) {
 		t.Fatalf("VisitIfStmt failed: %v", err)
 	}
 	
-	output := generator.output.String()
+	output := irText(generator)
 	if !strings.Contains(output, "br i1") {
 		t.Error("Expected conditional branch")
 	}
@@ -501,10 +950,81 @@ func TestVisitIfStmt(t *testing.T) {
 	}
 }
 
+// TestBasicBlockTermination tests that every block ends in exactly one terminator
+func TestBasicBlockTermination(t *testing.T) {
+	generator := newModuleGenerator()
+
+	cond := &domain.IdentifierExpr{Name: "c"}
+	cond.SetType(domain.NewBoolType())
+	returnValue := func(v int64) *domain.ReturnStmt {
+		lit := &domain.LiteralExpr{Value: v}
+		lit.SetType(domain.NewIntType())
+		return &domain.ReturnStmt{Value: lit}
+	}
+	printCall := &domain.CallExpr{Function: &domain.IdentifierExpr{Name: "print"}, Args: []domain.Expression{returnValue(9).Value}}
+	printCall.SetType(domain.NewVoidType())
+
+	// if (c) { if (c) { return 1; } } else { return 2; }  print(9);
+	// if (c) { return 3; } else { return 4; }  print(9);
+	fn := &domain.FunctionDecl{
+		Name:       "f",
+		Parameters: []domain.Parameter{{Name: "c", Type: domain.NewBoolType()}},
+		ReturnType: domain.NewIntType(),
+		Body: &domain.BlockStmt{Statements: []domain.Statement{
+			&domain.IfStmt{
+				Condition: cond,
+				ThenStmt:  &domain.IfStmt{Condition: cond, ThenStmt: returnValue(1)},
+				ElseStmt:  returnValue(2),
+			},
+			&domain.ExprStmt{Expression: printCall},
+			&domain.IfStmt{Condition: cond, ThenStmt: returnValue(3), ElseStmt: returnValue(4)},
+			&domain.ExprStmt{Expression: printCall},
+		}},
+	}
+
+	if err := generator.VisitFunctionDecl(fn); err != nil {
+		t.Fatalf("VisitFunctionDecl failed: %v", err)
+	}
+	output := irText(generator)
+
+	// Walk the blocks: each label must be preceded by a terminator, and no
+	// instruction may follow a terminator within a block
+	terminated := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, ";") || strings.HasPrefix(line, "target") ||
+			strings.HasPrefix(line, "declare") || strings.HasPrefix(line, "define"):
+			continue
+		case line == "}":
+			if !terminated {
+				t.Errorf("Function ends with an unterminated block: %s", output)
+			}
+		case strings.HasSuffix(line, ":"):
+			if line != "entry:" && !terminated {
+				t.Errorf("Block before %s is not terminated: %s", line, output)
+			}
+			terminated = false
+		default:
+			if terminated {
+				t.Errorf("Instruction %q follows a terminator: %s", line, output)
+			}
+			terminated = strings.HasPrefix(line, "br ") || strings.HasPrefix(line, "ret ") || line == "unreachable"
+		}
+	}
+
+	// The first print is reachable through the inner if, the second is dead
+	if strings.Count(output, "call void @sl_print_int") != 1 {
+		t.Errorf("Expected only the reachable print to be generated, got: %s", output)
+	}
+	if !generator.isTerminated() {
+		t.Error("Expected the current block to be terminated after the function")
+	}
+}
+
 // TestVisitWhileStmt tests while loop code generation
 func TestVisitWhileStmt(t *testing.T) {
-	generator := NewGenerator()
-	generator.indentLevel = 1
+	generator := newTestGenerator()
 	
 	condition := &domain.LiteralExpr{
 		Type_: domain.NewBoolType(),
@@ -526,7 +1046,7 @@ func TestVisitWhileStmt(t *testing.T) {
 		t.Fatalf("VisitWhileStmt failed: %v", err)
 	}
 	
-	output := generator.output.String()
+	output := irText(generator)
 	if !strings.Contains(output, "while.cond") {
 		t.Error("Expected while condition label")
 	}
@@ -538,10 +1058,52 @@ func TestVisitWhileStmt(t *testing.T) {
 	}
 }
 
+// TestVisitBreakContinue tests break and continue branching to loop labels
+func TestVisitBreakContinue(t *testing.T) {
+	generator := newTestGenerator()
+	
+	condition := &domain.LiteralExpr{Value: true}
+	condition.SetType(domain.NewBoolType())
+	
+	// outer: while (c) { for (; c; ) { if (c) { continue outer; } break; } }
+	inner := &domain.ForStmt{
+		Condition: condition,
+		Body: &domain.BlockStmt{Statements: []domain.Statement{
+			&domain.IfStmt{Condition: condition, ThenStmt: &domain.ContinueStmt{Label: "outer"}},
+			&domain.BreakStmt{},
+		}},
+	}
+	outer := &domain.WhileStmt{
+		Label:     "outer",
+		Condition: condition,
+		Body:      inner,
+	}
+	
+	err := outer.Accept(generator)
+	if err != nil {
+		t.Fatalf("VisitWhileStmt failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if !strings.Contains(output, "br label %while.cond1\n") {
+		t.Errorf("Expected labeled continue to branch to outer condition, got: %s", output)
+	}
+	if !strings.Contains(output, "br label %for.end7\n") {
+		t.Errorf("Expected break to branch to inner loop end, got: %s", output)
+	}
+	if len(generator.loops) != 0 {
+		t.Error("Loop stack should be empty after the loops are generated")
+	}
+	
+	// Jumps without an enclosing loop are rejected
+	if err := generator.VisitBreakStmt(&domain.BreakStmt{}); err == nil {
+		t.Error("VisitBreakStmt should fail outside a loop")
+	}
+}
+
 // TestVisitForStmt tests for loop code generation
 func TestVisitForStmt(t *testing.T) {
-	generator := NewGenerator()
-	generator.indentLevel = 1
+	generator := newTestGenerator()
 	
 	// for (int i = 0; i < 10; i++)
 	init := &domain.VarDeclStmt{
@@ -607,7 +1169,7 @@ func TestVisitForStmt(t *testing.T) {
 		t.Fatalf("VisitForStmt failed: %v", err)
 	}
 	
-	output := generator.output.String()
+	output := irText(generator)
 	if !strings.Contains(output, "for.cond") {
 		t.Error("Expected for condition label")
 	}
@@ -624,7 +1186,7 @@ func TestVisitForStmt(t *testing.T) {
 
 // TestVisitCallExpr tests function call code generation
 func TestVisitCallExpr(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
 	// Test print function call
 	printFunc := &domain.IdentifierExpr{
@@ -650,7 +1212,7 @@ func TestVisitCallExpr(t *testing.T) {
 		t.Fatalf("VisitCallExpr failed: %v", err)
 	}
 	
-	output := generator.output.String()
+	output := irText(generator)
 	if !strings.Contains(output, "call void @sl_print_string") {
 		t.Error("Expected print function call")
 	}
@@ -658,7 +1220,7 @@ func TestVisitCallExpr(t *testing.T) {
 
 // TestVisitExprStmt tests expression statement code generation
 func TestVisitExprStmt(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
 	expr := &domain.LiteralExpr{
 		Type_: domain.NewIntType(),
@@ -676,14 +1238,14 @@ func TestVisitExprStmt(t *testing.T) {
 	}
 	
 	// ExprStmt should generate code for its expression
-	if generator.currentValue != "42" {
+	if ident(generator.currentValue) != "42" {
 		t.Error("Expression statement should evaluate its expression")
 	}
 }
 
 // TestVisitBlockStmt tests block statement code generation
 func TestVisitBlockStmt(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
 	stmt1 := &domain.VarDeclStmt{
 		Name:  "x",
@@ -714,54 +1276,362 @@ func TestVisitBlockStmt(t *testing.T) {
 		t.Fatalf("VisitBlockStmt failed: %v", err)
 	}
 	
-	output := generator.output.String()
-	if !strings.Contains(output, "%x = alloca i32") {
+	output := irText(generator)
+	if !strings.Contains(output, "%x = alloca i64") {
 		t.Error("Block should contain first variable")
 	}
-	if !strings.Contains(output, "%y = alloca i32") {
+	if !strings.Contains(output, "%y = alloca i64") {
 		t.Error("Block should contain second variable")
 	}
 }
 
-// TestVisitIndexExpr tests index expression error handling
+// TestVisitIndexExpr tests array element access code generation
 func TestVisitIndexExpr(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
-	indexExpr := &domain.IndexExpr{
-		Object: &domain.IdentifierExpr{Name: "arr"},
-		Index:  &domain.LiteralExpr{Value: int64(0)},
-	}
+	fixedType := &domain.ArrayType{ElementType: domain.NewIntType(), Size: 4}
+	object := &domain.IdentifierExpr{Name: "arr"}
+	object.SetType(fixedType)
+	index := &domain.LiteralExpr{Value: int64(2)}
+	index.SetType(domain.NewIntType())
+	indexExpr := &domain.IndexExpr{Object: object, Index: index}
+	indexExpr.SetType(domain.NewIntType())
 	
 	err := indexExpr.Accept(generator)
-	if err == nil {
-		t.Error("VisitIndexExpr should return error for unimplemented feature")
+	if err != nil {
+		t.Fatalf("VisitIndexExpr failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if !strings.Contains(output, "getelementptr inbounds [4 x i64], ptr %arr, i64 0, i64 2") {
+		t.Errorf("Expected fixed array element address, got: %s", output)
+	}
+	if !strings.Contains(output, "load i64, ptr %temp_0") {
+		t.Errorf("Expected element load, got: %s", output)
+	}
+	
+	// Dynamic arrays index through the data pointer of their header
+	generator = newTestGenerator()
+	object.SetType(&domain.ArrayType{ElementType: domain.NewIntType(), Size: -1})
+	if err := indexExpr.Accept(generator); err != nil {
+		t.Fatalf("VisitIndexExpr failed for dynamic array: %v", err)
+	}
+	
+	output = irText(generator)
+	if !strings.Contains(output, "getelementptr inbounds { i64, ptr }, ptr %temp_0, i32 0, i32 1") {
+		t.Errorf("Expected header data field access, got: %s", output)
+	}
+	if !strings.Contains(output, "getelementptr inbounds i64, ptr %temp_2, i64 2") {
+		t.Errorf("Expected dynamic array element address, got: %s", output)
+	}
+	
+	// Indexing a non-array value is reported
+	object.SetType(domain.NewIntType())
+	if err := indexExpr.Accept(generator); err == nil {
+		t.Error("VisitIndexExpr should fail for non-array object")
+	}
+}
+
+// TestBoundsChecks tests runtime index checking for array accesses
+func TestBoundsChecks(t *testing.T) {
+	generator := newTestGenerator()
+	
+	object := &domain.IdentifierExpr{Name: "arr"}
+	object.SetType(&domain.ArrayType{ElementType: domain.NewIntType(), Size: 4})
+	index := &domain.IdentifierExpr{Name: "i"}
+	index.SetType(domain.NewIntType())
+	indexExpr := &domain.IndexExpr{Object: object, Index: index}
+	indexExpr.Location = domain.SourceRange{Start: domain.SourcePosition{Filename: "main.sl", Line: 3, Column: 9}}
+	indexExpr.SetType(domain.NewIntType())
+	
+	// Checks are off unless requested
+	if err := indexExpr.Accept(generator); err != nil {
+		t.Fatalf("VisitIndexExpr failed: %v", err)
+	}
+	if strings.Contains(irText(generator), "sl_bounds_check_failed") {
+		t.Error("Bounds checks should not be emitted when disabled")
+	}
+	
+	generator = newTestGenerator()
+	generator.SetBoundsChecks(true)
+	if err := indexExpr.Accept(generator); err != nil {
+		t.Fatalf("VisitIndexExpr failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if !strings.Contains(output, "icmp ult i64 %temp_0, 4") {
+		t.Errorf("Expected unsigned index comparison against length, got: %s", output)
+	}
+	if !strings.Contains(output, "call void @sl_bounds_check_failed(ptr @.srcfile.0, i32 3, i32 9, i64 %temp_0, i64 4)") {
+		t.Errorf("Expected trap call with source location, got: %s", output)
+	}
+	if !strings.Contains(output, "unreachable") {
+		t.Errorf("Expected unreachable after trap, got: %s", output)
+	}
+	if !strings.Contains(output, "@.srcfile.0 = private unnamed_addr constant [8 x i8] c\"main.sl\\00\"") {
+		t.Errorf("Expected source file constant, got: %s", output)
+	}
+}
+
+// TestDivisionChecks tests runtime divisor checking for integer division and remainder
+func TestDivisionChecks(t *testing.T) {
+	newDivision := func(op domain.BinaryOperator, operandType domain.Type) *domain.BinaryExpr {
+		left := &domain.IdentifierExpr{Name: "a"}
+		left.SetType(operandType)
+		right := &domain.IdentifierExpr{Name: "b"}
+		right.SetType(operandType)
+		expr := &domain.BinaryExpr{Left: left, Operator: op, Right: right}
+		expr.Location = domain.SourceRange{Start: domain.SourcePosition{Filename: "main.sl", Line: 5, Column: 12}}
+		expr.SetType(operandType)
+		return expr
+	}
+	
+	// Checks are off unless requested
+	generator := newTestGenerator()
+	if err := newDivision(domain.Div, domain.NewIntType()).Accept(generator); err != nil {
+		t.Fatalf("VisitBinaryExpr failed: %v", err)
+	}
+	if strings.Contains(irText(generator), "sl_division_by_zero") {
+		t.Error("Division checks should not be emitted when disabled")
+	}
+	
+	generator = newTestGenerator()
+	generator.SetDivisionChecks(true)
+	if err := newDivision(domain.Mod, &domain.BasicType{Kind: domain.UInt32Type}).Accept(generator); err != nil {
+		t.Fatalf("VisitBinaryExpr failed: %v", err)
+	}
+	
+	output := irText(generator)
+	expected := []string{
+		"icmp ne i32 %temp_1, 0",
+		"call void @sl_division_by_zero(ptr @.srcfile.0, i32 5, i32 12)",
+		"unreachable",
+		"urem i32 %temp_0, %temp_1",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	
+	// Float remainders do not trap, so they are not checked
+	generator = newTestGenerator()
+	generator.SetDivisionChecks(true)
+	if err := newDivision(domain.Mod, domain.NewFloatType()).Accept(generator); err != nil {
+		t.Fatalf("VisitBinaryExpr failed: %v", err)
+	}
+	if strings.Contains(irText(generator), "sl_division_by_zero") {
+		t.Error("Float remainders should not be checked")
+	}
+}
+
+// TestVisitAssignStmtIndexTarget tests assignment to an array element
+func TestVisitAssignStmtIndexTarget(t *testing.T) {
+	generator := newTestGenerator()
+	
+	object := &domain.IdentifierExpr{Name: "arr"}
+	object.SetType(&domain.ArrayType{ElementType: domain.NewIntType(), Size: 4})
+	index := &domain.LiteralExpr{Value: int64(1)}
+	index.SetType(domain.NewIntType())
+	target := &domain.IndexExpr{Object: object, Index: index}
+	target.SetType(domain.NewIntType())
+	
+	value := &domain.LiteralExpr{Value: int64(9)}
+	value.SetType(domain.NewIntType())
+	
+	err := generator.VisitAssignStmt(&domain.AssignStmt{Target: target, Value: value})
+	if err != nil {
+		t.Fatalf("VisitAssignStmt failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if !strings.Contains(output, "store i64 9, ptr %temp_0") {
+		t.Errorf("Expected store to array element, got: %s", output)
+	}
+}
+
+// TestVisitCompoundAssignStmt tests that compound assignments compute their
+// target address once and that increments add one of the target type
+func TestVisitCompoundAssignStmt(t *testing.T) {
+	generator := newTestGenerator()
+	generator.declareFunction("next", nil, domain.NewIntType())
+	
+	object := &domain.IdentifierExpr{Name: "arr"}
+	object.SetType(&domain.ArrayType{ElementType: domain.NewIntType(), Size: 4})
+	call := &domain.CallExpr{Function: &domain.IdentifierExpr{Name: "next"}}
+	call.SetType(domain.NewIntType())
+	target := &domain.IndexExpr{Object: object, Index: call}
+	target.SetType(domain.NewIntType())
+	value := &domain.LiteralExpr{Value: int64(5)}
+	value.SetType(domain.NewIntType())
+	
+	if err := generator.VisitCompoundAssignStmt(&domain.CompoundAssignStmt{Target: target, Operator: domain.Mul, Value: value}); err != nil {
+		t.Fatalf("VisitCompoundAssignStmt failed: %v", err)
+	}
+	
+	counter := &domain.IdentifierExpr{Name: "v"}
+	counter.SetType(domain.NewFloatType())
+	if err := generator.VisitCompoundAssignStmt(&domain.CompoundAssignStmt{Target: counter, Operator: domain.Sub}); err != nil {
+		t.Fatalf("VisitCompoundAssignStmt failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if strings.Count(output, "call i64 @next()") != 1 {
+		t.Errorf("Expected the index to be evaluated once, got: %s", output)
+	}
+	expected := []string{
+		"%temp_2 = load i64, ptr %temp_1",
+		"%temp_3 = mul i64 %temp_2, 5",
+		"store i64 %temp_3, ptr %temp_1",
+		"%temp_4 = load double, ptr %v",
+		"%temp_5 = fsub double %temp_4, 0x3FF0000000000000",
+		"store double %temp_5, ptr %v",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+// TestCompoundAssignEvaluationOrder tests that a[f()] += g() calls f before g
+// and loads the target after both
+func TestCompoundAssignEvaluationOrder(t *testing.T) {
+	generator := newTestGenerator()
+	generator.declareFunction("f", nil, domain.NewIntType())
+	generator.declareFunction("g", nil, domain.NewIntType())
+	call := func(name string) *domain.CallExpr {
+		expr := &domain.CallExpr{Function: &domain.IdentifierExpr{Name: name}}
+		expr.SetType(domain.NewIntType())
+		return expr
+	}
+
+	object := &domain.IdentifierExpr{Name: "arr"}
+	object.SetType(&domain.ArrayType{ElementType: domain.NewIntType(), Size: 4})
+	target := &domain.IndexExpr{Object: object, Index: call("f")}
+	target.SetType(domain.NewIntType())
+
+	if err := generator.VisitCompoundAssignStmt(&domain.CompoundAssignStmt{Target: target, Operator: domain.Add, Value: call("g")}); err != nil {
+		t.Fatalf("VisitCompoundAssignStmt failed: %v", err)
 	}
-	if !strings.Contains(err.Error(), "not yet implemented") {
-		t.Error("Error should indicate not implemented")
+
+	output := irText(generator)
+	index := strings.Index(output, "call i64 @f()")
+	value := strings.Index(output, "call i64 @g()")
+	load := strings.Index(output, "= load i64")
+	if index < 0 || value < 0 || load < 0 || !(index < value && value < load) {
+		t.Errorf("Expected the index, then the value, then the load of the target, got: %s", output)
 	}
 }
 
-// TestVisitMemberExpr tests member expression error handling
+// TestDynamicArrayFromFixed tests conversion of fixed arrays into dynamic arrays
+func TestDynamicArrayFromFixed(t *testing.T) {
+	generator := newTestGenerator()
+	
+	fixedType := &domain.ArrayType{ElementType: domain.NewIntType(), Size: 3}
+	init := &domain.IdentifierExpr{Name: "fixed"}
+	init.SetType(fixedType)
+	
+	varDecl := &domain.VarDeclStmt{
+		Name:        "dynamic",
+		Type_:       &domain.ArrayType{ElementType: domain.NewIntType(), Size: -1},
+		Initializer: init,
+	}
+	
+	err := generator.VisitVarDeclStmt(varDecl)
+	if err != nil {
+		t.Fatalf("VisitVarDeclStmt failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if !strings.Contains(output, "%dynamic = alloca ptr, align 8") {
+		t.Errorf("Expected dynamic array slot, got: %s", output)
+	}
+	if !strings.Contains(output, "call ptr @sl_alloc_array") {
+		t.Errorf("Expected runtime array allocation, got: %s", output)
+	}
+	if !strings.Contains(output, "store i64 3, ptr") {
+		t.Errorf("Expected array length store, got: %s", output)
+	}
+	if !strings.Contains(output, "store [3 x i64] %temp_0, ptr") {
+		t.Errorf("Expected element copy into heap storage, got: %s", output)
+	}
+}
+
+// TestVisitMemberExpr tests struct field access code generation
 func TestVisitMemberExpr(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
+	pointType := &domain.StructType{
+		Name:   "Point",
+		Fields: map[string]domain.Type{"x": domain.NewIntType(), "y": domain.NewIntType()},
+		Order:  []string{"x", "y"},
+	}
+	
+	object := &domain.IdentifierExpr{Name: "p"}
+	object.SetType(pointType)
 	memberExpr := &domain.MemberExpr{
-		Object: &domain.IdentifierExpr{Name: "obj"},
-		Member: "field",
+		Object: object,
+		Member: "y",
 	}
+	memberExpr.SetType(domain.NewIntType())
 	
 	err := memberExpr.Accept(generator)
-	if err == nil {
-		t.Error("VisitMemberExpr should return error for unimplemented feature")
+	if err != nil {
+		t.Fatalf("VisitMemberExpr failed: %v", err)
 	}
-	if !strings.Contains(err.Error(), "not yet implemented") {
-		t.Error("Error should indicate not implemented")
+	
+	output := irText(generator)
+	if !strings.Contains(output, "getelementptr inbounds %struct.Point, ptr %p, i32 0, i32 1") {
+		t.Errorf("Expected field address computation, got: %s", output)
+	}
+	if !strings.Contains(output, "load i64, ptr %temp_0") {
+		t.Errorf("Expected field load, got: %s", output)
+	}
+	
+	// Unknown members are reported
+	memberExpr.Member = "z"
+	if err := memberExpr.Accept(generator); err == nil {
+		t.Error("VisitMemberExpr should fail for unknown member")
+	}
+}
+
+// TestVisitAssignStmtMemberTarget tests assignment to a struct field
+func TestVisitAssignStmtMemberTarget(t *testing.T) {
+	generator := newTestGenerator()
+	generator.declareLocal("p", nil, interfaces.ParameterSymbol, &llvmir.Param{Name: "p.addr", Typ: llvmir.Ptr})
+	
+	pointType := &domain.StructType{
+		Name:   "Point",
+		Fields: map[string]domain.Type{"x": domain.NewIntType(), "y": domain.NewIntType()},
+		Order:  []string{"x", "y"},
+	}
+	
+	object := &domain.IdentifierExpr{Name: "p"}
+	object.SetType(pointType)
+	target := &domain.MemberExpr{Object: object, Member: "x"}
+	target.SetType(domain.NewIntType())
+	
+	value := &domain.LiteralExpr{Value: int64(7)}
+	value.SetType(domain.NewIntType())
+	
+	err := generator.VisitAssignStmt(&domain.AssignStmt{Target: target, Value: value})
+	if err != nil {
+		t.Fatalf("VisitAssignStmt failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if !strings.Contains(output, "getelementptr inbounds %struct.Point, ptr %p.addr, i32 0, i32 0") {
+		t.Errorf("Expected field address through parameter slot, got: %s", output)
+	}
+	if !strings.Contains(output, "store i64 7, ptr %temp_0") {
+		t.Errorf("Expected store to field, got: %s", output)
 	}
 }
 
 // TestGenerateGlobalVariable tests global variable generation
 func TestGenerateGlobalVariable(t *testing.T) {
-	generator := NewGenerator()
+	generator := newModuleGenerator()
 	
 	globalVar := &domain.VarDeclStmt{
 		Name:  "global_x",
@@ -778,13 +1648,148 @@ func TestGenerateGlobalVariable(t *testing.T) {
 		t.Fatalf("generateGlobalVariable failed: %v", err)
 	}
 	
-	output := generator.output.String()
-	if !strings.Contains(output, "@global_x = global i32") {
+	output := irText(generator)
+	if !strings.Contains(output, "@global_x = global i64") {
 		t.Error("Expected global variable declaration")
 	}
 	if !strings.Contains(output, "42") {
 		t.Error("Expected global variable value")
 	}
+	
+	initializer := &domain.LiteralExpr{Value: true}
+	initializer.SetType(domain.NewBoolType())
+	boolVar := &domain.VarDeclStmt{Name: "global_flag", Type_: domain.NewBoolType(), Initializer: initializer}
+	if err := generator.generateGlobalVariable(boolVar); err != nil {
+		t.Fatalf("generateGlobalVariable failed: %v", err)
+	}
+	if output := irText(generator); !strings.Contains(output, "@global_flag = global i8 1") {
+		t.Errorf("Expected bool global stored as a byte, got: %s", output)
+	}
+
+	nameVar := &domain.VarDeclStmt{Name: "global_name", Type_: domain.NewStringType()}
+	if err := generator.generateGlobalVariable(nameVar); err != nil {
+		t.Fatalf("generateGlobalVariable failed: %v", err)
+	}
+	output = irText(generator)
+	for _, expected := range []string{
+		`@.str.0 = private unnamed_addr constant [1 x i8] c"\00"`,
+		"@global_name = global ptr getelementptr inbounds ([1 x i8], ptr @.str.0, i64 0, i64 0)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected uninitialized string global to start out empty (%q), got: %s", expected, output)
+		}
+	}
+}
+
+// TestGlobalVariableAccess tests that functions load and store globals
+// through their @ names unless a local shadows them
+func TestGlobalVariableAccess(t *testing.T) {
+	generator := newModuleGenerator()
+	intType := domain.NewIntType()
+	
+	ident := func(name string) *domain.IdentifierExpr {
+		expr := &domain.IdentifierExpr{Name: name}
+		expr.SetType(intType)
+		return expr
+	}
+	one := &domain.LiteralExpr{Value: int64(1)}
+	one.SetType(intType)
+	sum := &domain.BinaryExpr{Left: ident("count"), Operator: domain.Add, Right: one}
+	sum.SetType(intType)
+	
+	program := &domain.Program{Declarations: []domain.Declaration{
+		&domain.FunctionDecl{
+			Name:       "bump",
+			ReturnType: domain.NewVoidType(),
+			Body: &domain.BlockStmt{Statements: []domain.Statement{
+				&domain.AssignStmt{Target: ident("count"), Value: sum},
+				&domain.VarDeclStmt{Name: "items", Type_: intType, Initializer: ident("items")},
+				&domain.AssignStmt{Target: ident("items"), Value: one},
+			}},
+		},
+		&domain.VarDeclStmt{Name: "count", Type_: intType},
+		&domain.VarDeclStmt{Name: "items", Type_: intType},
+		&domain.VarDeclStmt{Name: "list", Type_: &domain.ArrayType{ElementType: intType, Size: -1}},
+	}}
+	if err := generator.VisitProgram(program); err != nil {
+		t.Fatalf("VisitProgram failed: %v", err)
+	}
+	
+	output := irText(generator)
+	expected := []string{
+		"@count = global i64 0",
+		"@list.header = global { i64, ptr } zeroinitializer",
+		"@list = global ptr @list.header",
+		"%temp_0 = load i64, ptr @count",
+		"store i64 %temp_1, ptr @count",
+		"load i64, ptr @items",
+		"store i64 1, ptr %items",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if strings.Contains(output, "store i64 1, ptr @items") {
+		t.Errorf("Expected the local to shadow the global, got: %s", output)
+	}
+}
+
+// TestConstantCodegen tests that uses of constants get their values instead of loads
+func TestConstantCodegen(t *testing.T) {
+	generator := newModuleGenerator()
+	intType := domain.NewIntType()
+	
+	literal := func(value int64) *domain.LiteralExpr {
+		expr := &domain.LiteralExpr{Value: value}
+		expr.SetType(intType)
+		return expr
+	}
+	ident := func(name string) *domain.IdentifierExpr {
+		expr := &domain.IdentifierExpr{Name: name}
+		expr.SetType(intType)
+		return expr
+	}
+	sum := &domain.BinaryExpr{Left: ident("limit"), Operator: domain.Add, Right: ident("step")}
+	sum.SetType(intType)
+	
+	program := &domain.Program{Declarations: []domain.Declaration{
+		&domain.VarDeclStmt{Name: "limit", Type_: intType, Constant: true, Initializer: literal(1024)},
+		&domain.FunctionDecl{
+			Name:       "next",
+			ReturnType: intType,
+			Body: &domain.BlockStmt{Statements: []domain.Statement{
+				&domain.VarDeclStmt{Name: "step", Type_: intType, Constant: true, Initializer: literal(8)},
+				&domain.VarDeclStmt{Name: "total", Type_: intType, Initializer: sum},
+				&domain.BlockStmt{Statements: []domain.Statement{
+					// A variable shadowing the constant is loaded as usual
+					&domain.VarDeclStmt{Name: "limit", Type_: intType, Initializer: ident("limit")},
+					&domain.AssignStmt{Target: ident("total"), Value: ident("limit")},
+				}},
+				&domain.ReturnStmt{Value: ident("total")},
+			}},
+		},
+	}}
+	if err := generator.VisitProgram(program); err != nil {
+		t.Fatalf("VisitProgram failed: %v", err)
+	}
+	
+	output := irText(generator)
+	expected := []string{
+		"add i64 1024, 8",
+		"store i64 1024, ptr %limit",
+		"load i64, ptr %limit",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	for _, unexpected := range []string{"@limit", "%step"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Expected no storage for constants, got: %s", output)
+		}
+	}
 }
 
 // TestValidateFormatArguments tests format validation
@@ -841,7 +1846,7 @@ func TestSetterMethods(t *testing.T) {
 
 // TestVisitProgram tests program traversal
 func TestVisitProgram(t *testing.T) {
-	generator := NewGenerator()
+	generator := newModuleGenerator()
 	
 	// Create a program with a simple function
 	program := &domain.Program{
@@ -858,7 +1863,7 @@ func TestVisitProgram(t *testing.T) {
 	// Visit the program
 	generator.VisitProgram(program)
 	
-	output := generator.output.String()
+	output := irText(generator)
 	if !strings.Contains(output, "define void @main()") {
 		t.Error("VisitProgram should generate function definition")
 	}
@@ -866,29 +1871,31 @@ func TestVisitProgram(t *testing.T) {
 
 // TestVisitStructDecl tests struct declaration generation
 func TestVisitStructDecl(t *testing.T) {
-	generator := NewGenerator()
+	generator := newModuleGenerator()
 	
 	// Create a struct declaration
 	structDecl := &domain.StructDecl{
 		Name: "Point",
 		Fields: []domain.StructField{
 			{Name: "x", Type: &domain.BasicType{Kind: domain.IntType}},
-			{Name: "y", Type: &domain.BasicType{Kind: domain.IntType}},
+			{Name: "y", Type: &domain.BasicType{Kind: domain.StringType}},
 		},
 	}
 	
-	// Visit the struct - just test it doesn't panic
-	defer func() {
-		if r := recover(); r != nil {
-			t.Errorf("VisitStructDecl should not panic: %v", r)
-		}
-	}()
-	generator.VisitStructDecl(structDecl)
+	err := generator.VisitStructDecl(structDecl)
+	if err != nil {
+		t.Fatalf("VisitStructDecl failed: %v", err)
+	}
+	
+	output := irText(generator)
+	if !strings.Contains(output, "%struct.Point = type { i64, ptr }") {
+		t.Errorf("Expected named struct type, got: %s", output)
+	}
 }
 
 // TestHandlePrintFunction tests print function handling
 func TestHandlePrintFunction(t *testing.T) {
-	generator := NewGenerator()
+	generator := newTestGenerator()
 	
 	// Create a print function call
 	callExpr := &domain.CallExpr{
@@ -902,8 +1909,320 @@ func TestHandlePrintFunction(t *testing.T) {
 	// Test print handling
 	generator.handlePrintFunction(callExpr)
 	
-	output := generator.output.String()
+	output := irText(generator)
 	if !strings.Contains(output, "printf") || len(output) == 0 {
 		t.Errorf("handlePrintFunction should generate printf call, got: %s", output)
 	}
-}
\ No newline at end of file
+}
+// TestTargetTriple tests that the module targets the configured triple, defaulting to the host
+func TestTargetTriple(t *testing.T) {
+	program := &domain.Program{}
+
+	generator := NewGenerator()
+	generator.SetTargetTriple("aarch64-unknown-linux-gnu")
+	output, err := generator.Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(output, `target triple = "aarch64-unknown-linux-gnu"`) {
+		t.Errorf("Expected configured target triple, got: %s", output)
+	}
+	if !strings.Contains(output, `target datalayout = "e-m:e-i8:8:32-i16:16:32-i64:64-i128:128-n32:64-S128"`) {
+		t.Errorf("Expected data layout matching the target, got: %s", output)
+	}
+
+	output, err = NewGenerator().Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if !strings.Contains(output, `target triple = "`+llvmir.HostTriple()+`"`) {
+		t.Errorf("Expected host target triple by default, got: %s", output)
+	}
+}
+
+// TestNestedDynamicArrays tests that dynamic arrays nested in fixed arrays and
+// structs start out empty rather than null
+func TestNestedDynamicArrays(t *testing.T) {
+	listType := &domain.ArrayType{ElementType: domain.NewIntType(), Size: -1}
+	bagType := &domain.StructType{
+		Name:   "Bag",
+		Fields: map[string]domain.Type{"n": domain.NewIntType(), "items": listType},
+		Order:  []string{"n", "items"},
+	}
+	gridType := &domain.ArrayType{ElementType: listType, Size: 2}
+	program := &domain.Program{Declarations: []domain.Declaration{
+		&domain.StructDecl{Name: "Bag", Fields: []domain.StructField{
+			{Name: "n", Type: domain.NewIntType()},
+			{Name: "items", Type: listType},
+		}},
+		&domain.VarDeclStmt{Name: "grid", Type_: gridType},
+		&domain.VarDeclStmt{Name: "bag", Type_: bagType},
+		&domain.FunctionDecl{
+			Name:       "f",
+			ReturnType: &domain.BasicType{Kind: domain.VoidType},
+			Body: &domain.BlockStmt{Statements: []domain.Statement{
+				&domain.VarDeclStmt{Name: "rows", Type_: gridType},
+				&domain.VarDeclStmt{Name: "local", Type_: bagType},
+			}},
+		},
+	}}
+
+	generator := NewGenerator()
+	output, err := generator.Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	expected := []string{
+		"@grid = global [2 x ptr] [ptr @grid.header, ptr @grid.header]",
+		"@bag = global %struct.Bag { i64 zeroinitializer, ptr @bag.header }",
+		"call ptr @memset(ptr %rows, i32 0, i64 ptrtoint (ptr getelementptr ([2 x ptr], ptr null, i32 1) to i64))",
+		"call ptr @memset(ptr %local, i32 0, i64 ptrtoint (ptr getelementptr (%struct.Bag, ptr null, i32 1) to i64))",
+		"getelementptr inbounds [2 x ptr], ptr %rows, i64 0, i64 %",
+		"getelementptr inbounds %struct.Bag, ptr %local, i32 0, i32 1",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	// The loop over the rows and the struct field each allocate the header
+	// and storage of an empty array
+	if count := strings.Count(output, "call ptr @sl_alloc_array"); count != 4 {
+		t.Errorf("Expected 4 allocations, got %d: %s", count, output)
+	}
+}
+
+// TestTargetAlignment tests that memory accesses are aligned for the target
+func TestTargetAlignment(t *testing.T) {
+	recordType := &domain.StructType{
+		Name:   "R",
+		Fields: map[string]domain.Type{"tag": &domain.BasicType{Kind: domain.Int32Type}, "name": domain.NewStringType(), "v": domain.NewIntType()},
+		Order:  []string{"tag", "name", "v"},
+	}
+	field := func(name string, fieldType domain.Type) *domain.MemberExpr {
+		object := &domain.IdentifierExpr{Name: "r"}
+		object.SetType(recordType)
+		member := &domain.MemberExpr{Object: object, Member: name}
+		member.SetType(fieldType)
+		return member
+	}
+	message := &domain.LiteralExpr{Value: "hi"}
+	message.SetType(domain.NewStringType())
+	count := &domain.LiteralExpr{Value: int64(1)}
+	count.SetType(domain.NewIntType())
+	program := &domain.Program{Declarations: []domain.Declaration{
+		&domain.StructDecl{Name: "R", Fields: []domain.StructField{
+			{Name: "tag", Type: recordType.Fields["tag"]},
+			{Name: "name", Type: recordType.Fields["name"]},
+			{Name: "v", Type: recordType.Fields["v"]},
+		}},
+		&domain.FunctionDecl{
+			Name:       "f",
+			ReturnType: &domain.BasicType{Kind: domain.VoidType},
+			Body: &domain.BlockStmt{Statements: []domain.Statement{
+				&domain.VarDeclStmt{Name: "r", Type_: recordType},
+				&domain.AssignStmt{Target: field("name", domain.NewStringType()), Value: message},
+				&domain.AssignStmt{Target: field("v", domain.NewIntType()), Value: count},
+			}},
+		},
+	}}
+
+	generator := NewGenerator()
+	generator.SetTargetTriple("i686-pc-linux-gnu")
+	output, err := generator.Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	expected := []string{
+		"%r = alloca %struct.R, align 4",
+		"ptr %temp_1, align 4",
+		"store i64 1, ptr %temp_2, align 4",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+func TestDebugInfo(t *testing.T) {
+	at := func(line, column int) domain.BaseNode {
+		return domain.BaseNode{Location: domain.SourceRange{Start: domain.SourcePosition{Filename: "/src/test.sl", Line: line, Column: column}}}
+	}
+	program := &domain.Program{
+		BaseNode: at(1, 1),
+		Declarations: []domain.Declaration{
+			&domain.FunctionDecl{
+				BaseNode:   at(1, 1),
+				Name:       "f",
+				Parameters: []domain.Parameter{{Name: "n", Type: domain.NewIntType()}},
+				ReturnType: &domain.BasicType{Kind: domain.VoidType},
+				Body: &domain.BlockStmt{BaseNode: at(1, 20), Statements: []domain.Statement{
+					&domain.VarDeclStmt{BaseNode: at(2, 5), Name: "flag", Type_: domain.NewBoolType()},
+					&domain.BlockStmt{BaseNode: at(3, 5)},
+				}},
+			},
+		},
+	}
+
+	output, err := NewGenerator().Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if strings.Contains(output, "!dbg") {
+		t.Errorf("Expected no debug information by default, got: %s", output)
+	}
+
+	generator := NewGenerator()
+	generator.SetDebugInfo(true)
+	output, err = generator.Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	expected := []string{
+		`!DIFile(filename: "test.sl", directory: "/src")`,
+		`distinct !DISubprogram(name: "f"`,
+		`!DILocalVariable(name: "n", arg: 1`,
+		`!DILocalVariable(name: "flag", scope: !`,
+		`!DIBasicType(name: "bool", size: 8, encoding: DW_ATE_boolean)`,
+		"!DISubroutineType(types: !",
+		"!{null, !",
+		"!DILocation(line: 2, column: 5",
+		"distinct !DILexicalBlock(scope: !",
+		"call void @llvm.dbg.value(metadata i64 %n, metadata !",
+		"ret void, !dbg !",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+// TestTargetSizeType tests that size_t arguments are as wide as the target's pointers
+func TestTargetSizeType(t *testing.T) {
+	program := &domain.Program{Declarations: []domain.Declaration{&domain.FunctionDecl{
+		Name:       "f",
+		ReturnType: &domain.BasicType{Kind: domain.VoidType},
+		Body: &domain.BlockStmt{Statements: []domain.Statement{
+			&domain.VarDeclStmt{Name: "d", Type_: &domain.ArrayType{ElementType: domain.NewIntType(), Size: -1}},
+		}},
+	}}}
+
+	generator := NewGenerator()
+	generator.SetTargetTriple("wasm32-unknown-unknown")
+	output, err := generator.Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	expected := []string{
+		"declare ptr @malloc(i32)",
+		"declare ptr @sl_alloc_array(i32, i32)",
+		"call ptr @sl_alloc_array(i32 ptrtoint (ptr getelementptr (i64, ptr null, i32 1) to i32), i32 0)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+// TestDebugInfoTargetLayout tests that debug types are laid out for the target
+func TestDebugInfoTargetLayout(t *testing.T) {
+	at := func(line, column int) domain.BaseNode {
+		return domain.BaseNode{Location: domain.SourceRange{Start: domain.SourcePosition{Filename: "/src/test.sl", Line: line, Column: column}}}
+	}
+	recordType := &domain.StructType{
+		Name:   "R",
+		Fields: map[string]domain.Type{"tag": &domain.BasicType{Kind: domain.Int32Type}, "name": domain.NewStringType(), "v": domain.NewIntType()},
+		Order:  []string{"tag", "name", "v"},
+	}
+	program := &domain.Program{
+		BaseNode: at(1, 1),
+		Declarations: []domain.Declaration{
+			&domain.StructDecl{BaseNode: at(1, 1), Name: "R", Fields: []domain.StructField{
+				{Name: "tag", Type: recordType.Fields["tag"]},
+				{Name: "name", Type: recordType.Fields["name"]},
+				{Name: "v", Type: recordType.Fields["v"]},
+			}},
+			&domain.FunctionDecl{
+				BaseNode:   at(2, 1),
+				Name:       "f",
+				ReturnType: &domain.BasicType{Kind: domain.VoidType},
+				Body: &domain.BlockStmt{BaseNode: at(2, 20), Statements: []domain.Statement{
+					&domain.VarDeclStmt{BaseNode: at(3, 5), Name: "r", Type_: recordType},
+				}},
+			},
+		},
+	}
+
+	generator := NewGenerator()
+	generator.SetDebugInfo(true)
+	generator.SetTargetTriple("i686-pc-linux-gnu")
+	output, err := generator.Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	expected := []string{
+		`name: "R", scope: !0, file: !0, line: 0, size: 128, align: 32`,
+		`name: "name", scope: !`,
+		"size: 32, align: 32, offset: 32)",
+		`name: "v", scope: !`,
+		"size: 64, align: 32, offset: 64)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if strings.Contains(output, "size: 64, align: 64") {
+		t.Errorf("Expected no 64-bit aligned members on the target, got: %s", output)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "DW_TAG_pointer_type") && !strings.HasSuffix(line, "size: 32)") {
+			t.Errorf("Expected 32-bit pointers on the target, got: %s", line)
+		}
+	}
+}
+
+func TestShadowedVariables(t *testing.T) {
+	fixed := func() domain.Type { return &domain.ArrayType{ElementType: domain.NewIntType(), Size: 2} }
+	ident := func(name string) *domain.IdentifierExpr {
+		expr := &domain.IdentifierExpr{Name: name}
+		expr.SetType(domain.NewIntType())
+		return expr
+	}
+	literal := func(value string) *domain.LiteralExpr {
+		expr := &domain.LiteralExpr{Type_: domain.NewIntType(), Value: value}
+		expr.SetType(domain.NewIntType())
+		return expr
+	}
+	// func main() -> int { var x int = 1; { var x int = 2; var a [2]int; } { var a [2]int; } return x; }
+	program := &domain.Program{Declarations: []domain.Declaration{
+		&domain.FunctionDecl{
+			Name:       "main",
+			ReturnType: domain.NewIntType(),
+			Body: &domain.BlockStmt{Statements: []domain.Statement{
+				&domain.VarDeclStmt{Name: "x", Type_: domain.NewIntType(), Initializer: literal("1")},
+				&domain.BlockStmt{Statements: []domain.Statement{
+					&domain.VarDeclStmt{Name: "x", Type_: domain.NewIntType(), Initializer: literal("2")},
+					&domain.VarDeclStmt{Name: "a", Type_: fixed()},
+				}},
+				&domain.BlockStmt{Statements: []domain.Statement{
+					&domain.VarDeclStmt{Name: "a", Type_: fixed()},
+				}},
+				&domain.ReturnStmt{Value: ident("x")},
+			}},
+		},
+	}}
+
+	output, err := NewGenerator().Generate(program)
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	for _, e := range []string{"%a = alloca [2 x i64]", "%a.1 = alloca [2 x i64]", "trunc i64 1 to i32"} {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
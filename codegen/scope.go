@@ -0,0 +1,106 @@
+package codegen
+
+import (
+	"fmt"
+
+	"github.com/sokoide/llvm5/internal/domain"
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// localNames hands out the names of the values and blocks of a function,
+// which share one namespace in LLVM IR. A name is kept the first time it is
+// requested; later requests get a numeric suffix after a dot, which source
+// identifiers cannot contain.
+type localNames struct {
+	used map[string]int // Names in use, with the last suffix tried for each
+}
+
+func newLocalNames() *localNames {
+	return &localNames{used: make(map[string]int)}
+}
+
+// unique returns name, or name with a suffix if it is already in use
+func (n *localNames) unique(name string) string {
+	suffix, taken := n.used[name]
+	if !taken {
+		n.used[name] = 0
+		return name
+	}
+	for {
+		suffix++
+		candidate := fmt.Sprintf("%s.%d", name, suffix)
+		if _, taken := n.used[candidate]; !taken {
+			n.used[name] = suffix
+			n.used[candidate] = 0
+			return candidate
+		}
+	}
+}
+
+// beginFunctionScope starts the outermost scope of a function, holding its
+// parameters, and the names of its values
+func (g *Generator) beginFunctionScope() {
+	g.scope = newScope(nil)
+	g.locals = make(map[*interfaces.Symbol]interfaces.LLVMValue)
+	g.names = newLocalNames()
+}
+
+// pushScope opens a scope for the declarations of a block or for statement,
+// matching the scopes the semantic analyzer resolved names in
+func (g *Generator) pushScope() {
+	scope := newScope(g.scope)
+	g.scope.Children = append(g.scope.Children, scope)
+	g.scope = scope
+}
+
+// popScope closes the innermost scope
+func (g *Generator) popScope() {
+	g.scope = g.scope.Parent
+}
+
+// newScope returns an empty scope nested in parent
+func newScope(parent *interfaces.Scope) *interfaces.Scope {
+	scope := &interfaces.Scope{Parent: parent, Symbols: make(map[string]*interfaces.Symbol)}
+	if parent != nil {
+		scope.Level = parent.Level + 1
+	}
+	return scope
+}
+
+// declareLocal binds a variable of the innermost scope to its stack slot, or
+// a constant to its value
+func (g *Generator) declareLocal(name string, t domain.Type, kind interfaces.SymbolKind, slot interfaces.LLVMValue) {
+	symbol := &interfaces.Symbol{Name: name, Type: t, Kind: kind, Scope: g.scope}
+	g.scope.Symbols[name] = symbol
+	g.locals[symbol] = slot
+}
+
+// variableAddress returns the stack slot of the innermost local variable or
+// parameter with the given name, or the global variable it refers to when no
+// local shadows it
+func (g *Generator) variableAddress(name string) (interfaces.LLVMValue, error) {
+	for scope := g.scope; scope != nil; scope = scope.Parent {
+		if symbol, ok := scope.Symbols[name]; ok {
+			return g.locals[symbol], nil
+		}
+	}
+	if global, ok := g.globals[name]; ok {
+		return global, nil
+	}
+	return nil, fmt.Errorf("undefined variable %s", name)
+}
+
+// constantValue returns the value of the innermost local or global constant
+// with the given name, unless a variable shadows it
+func (g *Generator) constantValue(name string) (interfaces.LLVMValue, bool) {
+	for scope := g.scope; scope != nil; scope = scope.Parent {
+		if symbol, ok := scope.Symbols[name]; ok {
+			if symbol.Kind != interfaces.ConstantSymbol {
+				return nil, false
+			}
+			return g.locals[symbol], true
+		}
+	}
+	value, ok := g.constants[name]
+	return value, ok
+}
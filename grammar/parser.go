@@ -66,34 +66,51 @@ const FOR = 57357
 const RETURN = 57358
 const TRUE = 57359
 const FALSE = 57360
-const PLUS = 57361
-const MINUS = 57362
-const STAR = 57363
-const SLASH = 57364
-const PERCENT = 57365
-const EQUAL = 57366
-const NOT_EQUAL = 57367
-const LESS = 57368
-const LESS_EQUAL = 57369
-const GREATER = 57370
-const GREATER_EQUAL = 57371
-const AND = 57372
-const OR = 57373
-const NOT = 57374
-const ASSIGN = 57375
-const LEFT_PAREN = 57376
-const RIGHT_PAREN = 57377
-const LEFT_BRACE = 57378
-const RIGHT_BRACE = 57379
-const LEFT_BRACKET = 57380
-const RIGHT_BRACKET = 57381
-const SEMICOLON = 57382
-const COMMA = 57383
-const DOT = 57384
-const COLON = 57385
-const ARROW = 57386
-const LOWER_THAN_ELSE = 57387
-const UNARY_MINUS = 57388
+const BREAK = 57361
+const CONTINUE = 57362
+const CONST = 57363
+const PLUS = 57364
+const MINUS = 57365
+const STAR = 57366
+const SLASH = 57367
+const PERCENT = 57368
+const EQUAL = 57369
+const NOT_EQUAL = 57370
+const LESS = 57371
+const LESS_EQUAL = 57372
+const GREATER = 57373
+const GREATER_EQUAL = 57374
+const AND = 57375
+const OR = 57376
+const NOT = 57377
+const AMPERSAND = 57378
+const PIPE = 57379
+const CARET = 57380
+const TILDE = 57381
+const SHIFT_LEFT = 57382
+const SHIFT_RIGHT = 57383
+const ASSIGN = 57384
+const DEFINE = 57385
+const PLUS_ASSIGN = 57386
+const MINUS_ASSIGN = 57387
+const STAR_ASSIGN = 57388
+const SLASH_ASSIGN = 57389
+const PERCENT_ASSIGN = 57390
+const INCREMENT = 57391
+const DECREMENT = 57392
+const LEFT_PAREN = 57393
+const RIGHT_PAREN = 57394
+const LEFT_BRACE = 57395
+const RIGHT_BRACE = 57396
+const LEFT_BRACKET = 57397
+const RIGHT_BRACKET = 57398
+const SEMICOLON = 57399
+const COMMA = 57400
+const DOT = 57401
+const COLON = 57402
+const ARROW = 57403
+const LOWER_THAN_ELSE = 57404
+const UNARY_MINUS = 57405
 
 var yyToknames = [...]string{
 	"$end",
@@ -114,6 +131,9 @@ var yyToknames = [...]string{
 	"RETURN",
 	"TRUE",
 	"FALSE",
+	"BREAK",
+	"CONTINUE",
+	"CONST",
 	"PLUS",
 	"MINUS",
 	"STAR",
@@ -128,7 +148,21 @@ var yyToknames = [...]string{
 	"AND",
 	"OR",
 	"NOT",
+	"AMPERSAND",
+	"PIPE",
+	"CARET",
+	"TILDE",
+	"SHIFT_LEFT",
+	"SHIFT_RIGHT",
 	"ASSIGN",
+	"DEFINE",
+	"PLUS_ASSIGN",
+	"MINUS_ASSIGN",
+	"STAR_ASSIGN",
+	"SLASH_ASSIGN",
+	"PERCENT_ASSIGN",
+	"INCREMENT",
+	"DECREMENT",
 	"LEFT_PAREN",
 	"RIGHT_PAREN",
 	"LEFT_BRACE",
@@ -164,6 +198,34 @@ func createBinaryExpr(left domain.Expression, op domain.BinaryOperator, right do
 	}
 }
 
+// createCompoundAssignStmt creates a compound assignment node; a nil value
+// increments or decrements the target
+func createCompoundAssignStmt(target domain.Expression, op domain.BinaryOperator, value domain.Expression) *domain.CompoundAssignStmt {
+	return &domain.CompoundAssignStmt{
+		BaseNode: domain.BaseNode{Location: target.GetLocation()},
+		Target:   target,
+		Operator: op,
+		Value:    value,
+	}
+}
+
+// castTarget returns the builtin type named by a single-argument call such as
+// float(x), or nil if the call is an ordinary function call
+func castTarget(reg domain.TypeRegistry, fn domain.Expression, args []domain.Expression) domain.Type {
+	ident, ok := fn.(*domain.IdentifierExpr)
+	if !ok || len(args) != 1 {
+		return nil
+	}
+	t, exists := reg.GetType(ident.Name)
+	if !exists {
+		return nil
+	}
+	if basic, ok := t.(*domain.BasicType); !ok || basic.Kind == domain.VoidType {
+		return nil
+	}
+	return t
+}
+
 // getLocationFromToken extracts source location from a token
 func getLocationFromToken(token interfaces.Token) domain.SourceRange {
 	pos := token.Location
@@ -203,135 +265,181 @@ var yyExca = [...]int8{
 
 const yyPrivate = 57344
 
-const yyLast = 357
+const yyLast = 583
 
 var yyAct = [...]uint8{
-	108, 72, 27, 12, 124, 73, 48, 145, 129, 74,
-	125, 119, 49, 156, 144, 130, 22, 40, 10, 155,
-	10, 117, 154, 21, 146, 14, 15, 16, 143, 85,
-	9, 53, 58, 11, 33, 12, 10, 28, 32, 78,
-	126, 10, 23, 17, 10, 12, 10, 32, 52, 24,
-	10, 34, 81, 12, 47, 35, 53, 51, 77, 54,
-	12, 12, 20, 57, 162, 11, 10, 28, 10, 161,
-	80, 148, 133, 83, 55, 12, 7, 8, 18, 79,
-	132, 82, 30, 147, 101, 102, 104, 26, 131, 53,
-	19, 11, 103, 75, 76, 31, 10, 50, 61, 62,
-	63, 106, 157, 12, 107, 11, 29, 3, 25, 105,
-	13, 86, 87, 88, 89, 90, 91, 92, 93, 94,
-	95, 96, 97, 98, 99, 56, 36, 127, 59, 60,
-	61, 62, 63, 39, 141, 135, 128, 136, 84, 116,
-	115, 138, 114, 139, 140, 113, 10, 112, 152, 153,
-	111, 110, 2, 149, 150, 158, 159, 151, 160, 137,
-	6, 5, 163, 164, 41, 42, 43, 4, 12, 1,
-	0, 118, 120, 0, 121, 122, 123, 44, 45, 0,
-	37, 59, 60, 61, 62, 63, 64, 65, 66, 67,
-	68, 69, 38, 0, 46, 0, 53, 41, 42, 43,
-	142, 12, 0, 0, 118, 120, 0, 121, 122, 123,
-	44, 45, 0, 37, 59, 60, 61, 62, 63, 0,
-	0, 66, 67, 68, 69, 38, 0, 46, 0, 53,
-	109, 41, 42, 43, 0, 12, 0, 0, 118, 120,
-	0, 121, 122, 123, 44, 45, 0, 37, 0, 0,
-	0, 41, 42, 43, 0, 12, 0, 0, 0, 38,
-	0, 46, 0, 53, 44, 45, 0, 37, 0, 41,
-	42, 43, 0, 12, 0, 0, 0, 0, 0, 38,
-	0, 46, 44, 45, 0, 37, 0, 134, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 38, 0, 46,
-	100, 59, 60, 61, 62, 63, 64, 65, 66, 67,
-	68, 69, 70, 71, 41, 42, 43, 0, 12, 0,
-	0, 0, 0, 0, 0, 0, 0, 44, 45, 0,
-	37, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 38, 0, 46, 59, 60, 61, 62, 63,
-	64, 65, 66, 67, 68, 69, 70,
+	28, 11, 130, 11, 139, 146, 136, 69, 15, 16,
+	17, 18, 73, 220, 135, 13, 155, 219, 19, 11,
+	198, 11, 218, 158, 9, 159, 160, 161, 162, 163,
+	164, 165, 13, 156, 13, 197, 70, 74, 166, 217,
+	66, 11, 60, 39, 75, 41, 61, 114, 102, 13,
+	62, 213, 201, 115, 103, 38, 207, 206, 205, 204,
+	107, 203, 12, 100, 116, 77, 98, 99, 117, 11,
+	37, 11, 74, 106, 202, 11, 200, 107, 196, 12,
+	195, 13, 113, 174, 110, 104, 194, 13, 7, 8,
+	189, 188, 125, 105, 124, 108, 112, 13, 172, 111,
+	10, 13, 40, 11, 70, 11, 107, 119, 13, 36,
+	122, 120, 226, 13, 225, 177, 209, 208, 11, 101,
+	169, 126, 168, 128, 144, 167, 129, 118, 12, 121,
+	76, 35, 153, 221, 12, 42, 43, 44, 45, 46,
+	148, 149, 127, 109, 154, 13, 157, 71, 12, 67,
+	22, 96, 173, 175, 72, 11, 171, 68, 11, 3,
+	23, 178, 14, 180, 182, 183, 184, 185, 186, 187,
+	144, 179, 192, 190, 191, 63, 64, 65, 27, 176,
+	123, 142, 181, 199, 44, 45, 46, 21, 141, 140,
+	138, 137, 134, 133, 132, 2, 6, 5, 210, 211,
+	4, 1, 0, 0, 212, 0, 0, 214, 0, 144,
+	144, 215, 216, 0, 0, 0, 0, 0, 144, 144,
+	222, 223, 144, 0, 224, 0, 144, 144, 227, 228,
+	78, 79, 80, 81, 82, 83, 84, 85, 86, 87,
+	88, 89, 90, 91, 92, 93, 94, 95, 29, 30,
+	31, 0, 13, 0, 0, 143, 147, 0, 148, 149,
+	150, 32, 33, 151, 152, 145, 0, 24, 0, 0,
+	0, 0, 0, 0, 29, 30, 31, 0, 13, 25,
+	0, 143, 147, 26, 148, 149, 150, 32, 33, 151,
+	152, 145, 0, 24, 0, 34, 0, 107, 0, 0,
+	0, 193, 0, 0, 0, 25, 0, 0, 0, 26,
+	0, 0, 0, 0, 0, 0, 0, 0, 29, 30,
+	31, 34, 13, 107, 131, 143, 147, 0, 148, 149,
+	150, 32, 33, 151, 152, 145, 0, 24, 29, 30,
+	31, 0, 13, 0, 0, 0, 0, 0, 0, 25,
+	0, 32, 33, 26, 0, 0, 0, 24, 29, 30,
+	31, 0, 13, 0, 0, 34, 0, 107, 0, 25,
+	0, 32, 33, 26, 0, 0, 0, 24, 0, 0,
+	0, 0, 29, 30, 31, 34, 13, 0, 0, 25,
+	0, 170, 0, 26, 0, 32, 33, 0, 0, 0,
+	0, 24, 29, 30, 31, 34, 13, 0, 0, 0,
+	20, 0, 0, 25, 0, 32, 33, 26, 0, 0,
+	0, 24, 42, 43, 44, 45, 46, 0, 0, 34,
+	97, 0, 0, 25, 0, 0, 0, 26, 0, 0,
+	58, 59, 0, 0, 0, 0, 0, 0, 0, 34,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 53, 54, 0, 55, 56, 57, 0, 58, 59,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 53, 0, 0, 55, 56, 57, 0, 58, 59,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 0, 0, 0, 55, 56, 57, 0, 58, 59,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 0, 0, 0, 55, 0, 57, 0, 58, 59,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 0, 0, 0, 55, 0, 0, 0, 58, 59,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 0, 0, 42, 43, 44, 45, 46, 58, 59,
+	49, 50, 51, 52, 0, 0, 0, 0, 0, 0,
+	0, 58, 59,
 }
 
 var yyPact = [...]int16{
-	67, -1000, 67, -1000, -1000, -1000, -1000, 95, 95, 95,
-	-1000, 39, -1000, -1000, 56, 26, -17, 3, 27, 52,
-	45, -1000, 310, 27, -1000, -29, 53, -1000, 27, 37,
-	-1000, -1000, 27, -8, 282, -1000, -33, 310, 310, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, 310, -1000, -5, 95,
-	27, 20, -1000, -1000, -1000, -1000, -1000, -11, -1000, 310,
-	310, 310, 310, 310, 310, 310, 310, 310, 310, 310,
-	310, 310, 265, 310, 95, -1000, -1000, 51, 27, 20,
-	-1000, -1000, 20, -1000, 193, -1000, 77, 77, -1000, -1000,
-	-1000, 195, 195, 109, 109, 109, 109, 162, 326, -31,
-	-1000, -1000, 1, -1000, -1000, 20, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 95, -25,
-	54, 46, 38, 247, -1000, 310, -1000, -1000, 27, 310,
-	-1000, 310, 310, 160, -1000, -12, -1000, -26, -16, 48,
-	36, 310, 310, -1000, -1000, 310, -1000, 227, 227, -18,
-	-21, -27, 89, -1000, 227, 227, -1000, 227, 34, 29,
-	-1000, 227, 227, -1000, -1000,
+	79, -1000, 79, -1000, -1000, -1000, -1000, 137, 137, 137,
+	137, -1000, 354, -1000, -1000, 80, 56, 13, 93, 46,
+	93, 428, -1000, -9, 398, 398, 398, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, 398, 105, 100, -1000, 398, 88,
+	93, -1000, 398, 398, 398, 398, 398, 398, 398, 398,
+	398, 398, 398, 398, 398, 398, 398, 398, 398, 398,
+	378, 398, 137, -1000, -1000, -1000, 67, -4, 24, -1000,
+	93, 89, -1000, -1000, 93, 39, 398, -1000, 160, 160,
+	-1000, -1000, -1000, 541, 541, 400, 400, 400, 400, 468,
+	448, 528, 488, 508, 113, 113, -5, -1000, -1000, 8,
+	-1000, -1000, 7, 137, 93, 53, -1000, -1000, -1000, -1000,
+	-1000, 37, -1000, 35, -1000, 398, -1000, 93, 53, -1000,
+	-1000, 53, -1000, 270, -1000, -1000, -1000, 53, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, 137, -27, 137, -19, 74, 71, 69,
+	334, 41, 26, -1000, 73, 398, 126, 93, 398, 398,
+	398, 398, 398, 398, 34, 33, -1000, 398, 398, 244,
+	-1000, 29, -1000, 23, -1000, 21, -22, 398, 19, -1000,
+	-1000, 10, 17, 4, 2, 1, 0, -1, -1000, -1000,
+	65, 64, 398, 398, -1000, -1000, -1000, -1000, 398, -6,
+	-1000, 398, -1000, -1000, -1000, -1000, -1000, -1000, 314, 314,
+	-18, -35, -40, -1000, -44, 120, -1000, 314, 314, -1000,
+	-1000, 314, 62, 60, -1000, 314, 314, -1000, -1000,
 }
 
 var yyPgo = [...]uint8{
-	0, 169, 107, 167, 161, 160, 152, 0, 151, 150,
-	147, 145, 142, 140, 139, 21, 138, 11, 133, 126,
-	55, 51, 124, 2, 108, 95, 106, 30, 17,
+	0, 201, 159, 200, 197, 196, 195, 2, 194, 193,
+	192, 14, 6, 191, 190, 4, 189, 188, 181, 180,
+	5, 178, 160, 150, 187, 151, 7, 149, 12, 147,
+	24, 0,
 }
 
 var yyR1 = [...]int8{
 	0, 1, 1, 6, 6, 2, 2, 2, 5, 5,
-	3, 3, 3, 3, 3, 3, 4, 4, 27, 27,
-	27, 24, 24, 23, 26, 26, 25, 16, 16, 7,
-	7, 7, 7, 7, 7, 7, 7, 8, 8, 9,
-	10, 10, 11, 12, 12, 13, 13, 14, 15, 17,
-	21, 21, 21, 21, 21, 21, 21, 21, 21, 21,
-	21, 21, 21, 21, 20, 20, 20, 19, 19, 19,
-	19, 19, 22, 22, 18, 18, 18, 18, 18, 18,
-	18, 28,
+	5, 3, 3, 3, 3, 3, 3, 4, 4, 30,
+	30, 30, 27, 27, 26, 29, 29, 28, 19, 19,
+	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
+	7, 8, 8, 8, 8, 8, 9, 9, 9, 9,
+	9, 9, 9, 9, 10, 10, 11, 12, 12, 16,
+	16, 17, 17, 18, 18, 13, 13, 14, 15, 20,
+	24, 24, 24, 24, 24, 24, 24, 24, 24, 24,
+	24, 24, 24, 24, 24, 24, 24, 24, 24, 23,
+	23, 23, 23, 22, 22, 22, 22, 22, 25, 25,
+	21, 21, 21, 21, 21, 21, 21, 31,
 }
 
 var yyR2 = [...]int8{
 	0, 1, 0, 1, 2, 1, 1, 1, 3, 5,
-	8, 7, 7, 6, 6, 5, 5, 4, 1, 4,
-	3, 1, 3, 2, 1, 2, 3, 0, 2, 1,
-	1, 1, 1, 1, 1, 1, 1, 4, 6, 4,
-	5, 7, 5, 8, 8, 2, 3, 2, 3, 1,
+	6, 8, 7, 7, 6, 6, 5, 5, 4, 1,
+	4, 3, 1, 3, 2, 1, 2, 3, 0, 2,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 4, 6, 5, 4, 6, 4, 4, 4, 4,
+	4, 4, 3, 3, 5, 7, 5, 8, 8, 3,
+	3, 2, 3, 2, 3, 2, 3, 2, 3, 1,
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 1, 2, 2, 1, 4, 3,
-	4, 3, 1, 3, 1, 1, 1, 1, 1, 1,
-	3, 1,
+	3, 3, 3, 3, 3, 3, 3, 3, 3, 1,
+	2, 2, 2, 1, 4, 3, 4, 3, 1, 3,
+	1, 1, 1, 1, 1, 1, 3, 1,
 }
 
 var yyChk = [...]int16{
-	-1000, -1, -6, -2, -3, -4, -5, 9, 10, -27,
-	-28, 38, 8, -2, -28, -28, -28, 4, 39, 34,
-	36, 40, 33, 39, -27, -24, 35, -23, -28, -26,
-	37, -25, -28, -17, -21, -20, -19, 20, 32, -18,
-	-28, 4, 5, 6, 17, 18, 34, -27, 35, 41,
-	44, -27, -15, 36, -27, 37, -25, -27, 40, 19,
-	20, 21, 22, 23, 24, 25, 26, 27, 28, 29,
-	30, 31, 34, 38, 42, -20, -20, -17, 44, -27,
-	-15, -23, -27, -15, -16, 40, -21, -21, -21, -21,
-	-21, -21, -21, -21, -21, -21, -21, -21, -21, -22,
-	35, -17, -17, -28, 35, -27, -15, -15, -7, 37,
-	-8, -9, -10, -11, -12, -13, -14, -15, 11, -17,
-	12, 14, 15, 16, 35, 41, 39, -15, -28, 33,
-	40, 34, 34, 34, 40, -17, -17, -27, -17, -17,
-	-17, -7, 40, 40, 40, 33, 40, 35, 35, -17,
-	-17, -17, -7, -7, 40, 40, 40, 13, -7, -7,
-	-7, 35, 35, -7, -7,
+	-1000, -1, -6, -2, -3, -4, -5, 9, 10, -30,
+	21, -31, 55, 8, -2, -31, -31, -31, -31, -20,
+	56, -24, -23, -22, 23, 35, 39, -21, -31, 4,
+	5, 6, 17, 18, 51, 51, 53, 57, 42, -30,
+	56, -30, 22, 23, 24, 25, 26, 27, 28, 29,
+	30, 31, 32, 33, 34, 36, 37, 38, 40, 41,
+	51, 55, 59, -23, -23, -23, -20, -27, 52, -26,
+	-31, -29, 54, -28, -31, -20, 42, -30, -24, -24,
+	-24, -24, -24, -24, -24, -24, -24, -24, -24, -24,
+	-24, -24, -24, -24, -24, -24, -25, 52, -20, -20,
+	-31, 52, 52, 58, 61, -30, -15, 53, -30, 54,
+	-28, -30, 57, -20, 52, 58, 56, 61, -30, -15,
+	-26, -30, -15, -19, 57, 57, -20, -30, -15, -15,
+	-7, 54, -8, -9, -10, -11, -12, -13, -14, -15,
+	-16, -17, -18, 11, -31, 21, -20, 12, 14, 15,
+	16, 19, 20, -15, -31, 43, 60, -31, 42, 44,
+	45, 46, 47, 48, 49, 50, 57, 51, 51, 51,
+	57, -20, 57, -31, 57, -31, -30, 42, -20, -11,
+	-12, -30, -20, -20, -20, -20, -20, -20, 57, 57,
+	-20, -20, -7, 57, 57, 57, 57, 57, 42, -20,
+	57, 42, 57, 57, 57, 57, 57, 57, 52, 52,
+	-20, -20, -20, 57, -20, -7, -7, 57, 57, 57,
+	57, 13, -7, -7, -7, 52, 52, -7, -7,
 }
 
 var yyDef = [...]int8{
 	2, -2, 1, 3, 5, 6, 7, 0, 0, 0,
-	18, 0, 81, 4, 0, 0, 0, 0, 0, 0,
-	0, 8, 0, 0, 20, 0, 0, 21, 0, 0,
-	17, 24, 0, 0, 49, 50, 64, 0, 0, 67,
-	74, 75, 76, 77, 78, 79, 0, 19, 0, 0,
-	0, 0, 15, 27, 23, 16, 25, 0, 9, 0,
+	0, 19, 0, 107, 4, 0, 0, 0, 0, 0,
+	0, 69, 70, 89, 0, 0, 0, 93, 100, 101,
+	102, 103, 104, 105, 0, 0, 0, 8, 0, 0,
+	0, 21, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 65, 66, 0, 0, 0,
-	14, 22, 0, 13, 0, 26, 51, 52, 53, 54,
-	55, 56, 57, 58, 59, 60, 61, 62, 63, 0,
-	69, 72, 0, 71, 80, 0, 12, 11, 28, 48,
-	29, 30, 31, 32, 33, 34, 35, 36, 0, 0,
-	0, 0, 0, 0, 68, 0, 70, 10, 0, 0,
-	47, 0, 0, 0, 45, 0, 73, 0, 0, 0,
-	0, 0, 0, 46, 37, 0, 39, 0, 0, 0,
-	0, 0, 40, 42, 0, 0, 38, 0, 0, 0,
-	41, 0, 0, 43, 44,
+	0, 0, 0, 90, 91, 92, 0, 0, 0, 22,
+	0, 0, 18, 25, 0, 0, 0, 20, 71, 72,
+	73, 74, 75, 76, 77, 78, 79, 80, 81, 82,
+	83, 84, 85, 86, 87, 88, 0, 95, 98, 0,
+	97, 106, 0, 0, 0, 0, 16, 28, 24, 17,
+	26, 0, 9, 0, 94, 0, 96, 0, 0, 15,
+	23, 0, 14, 0, 27, 10, 99, 0, 13, 12,
+	29, 68, 30, 31, 32, 33, 34, 35, 36, 37,
+	38, 39, 40, 0, 100, 0, 0, 0, 0, 0,
+	0, 0, 0, 11, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 67, 0, 0, 0,
+	65, 0, 61, 0, 63, 0, 0, 0, 0, 59,
+	60, 0, 0, 0, 0, 0, 0, 0, 52, 53,
+	0, 0, 0, 0, 66, 62, 64, 41, 0, 0,
+	44, 0, 46, 47, 48, 49, 50, 51, 0, 0,
+	0, 0, 0, 43, 0, 54, 56, 0, 0, 42,
+	45, 0, 0, 0, 55, 0, 0, 57, 58,
 }
 
 var yyTok1 = [...]int8{
@@ -343,7 +451,9 @@ var yyTok2 = [...]int8{
 	12, 13, 14, 15, 16, 17, 18, 19, 20, 21,
 	22, 23, 24, 25, 26, 27, 28, 29, 30, 31,
 	32, 33, 34, 35, 36, 37, 38, 39, 40, 41,
-	42, 43, 44, 45, 46,
+	42, 43, 44, 45, 46, 47, 48, 49, 50, 51,
+	52, 53, 54, 55, 56, 57, 58, 59, 60, 61,
+	62, 63,
 }
 
 var yyTok3 = [...]int8{
@@ -751,6 +861,17 @@ yydefault:
 			}
 		}
 	case 10:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.decl = &domain.VarDeclStmt{
+				BaseNode:    domain.BaseNode{Location: getLocationFromToken(yyDollar[2].token)},
+				Name:        yyDollar[2].token.Value,
+				Type_:       yyDollar[3].typ,
+				Initializer: yyDollar[5].expr,
+				Constant:    true,
+			}
+		}
+	case 11:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.decl = &domain.FunctionDecl{
@@ -761,7 +882,7 @@ yydefault:
 				Body:       yyDollar[8].stmt.(*domain.BlockStmt),
 			}
 		}
-	case 11:
+	case 12:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.decl = &domain.FunctionDecl{
@@ -772,7 +893,7 @@ yydefault:
 				Body:       yyDollar[7].stmt.(*domain.BlockStmt),
 			}
 		}
-	case 12:
+	case 13:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.decl = &domain.FunctionDecl{
@@ -783,7 +904,7 @@ yydefault:
 				Body:       yyDollar[7].stmt.(*domain.BlockStmt),
 			}
 		}
-	case 13:
+	case 14:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.decl = &domain.FunctionDecl{
@@ -794,7 +915,7 @@ yydefault:
 				Body:       yyDollar[6].stmt.(*domain.BlockStmt),
 			}
 		}
-	case 14:
+	case 15:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			reg := yylex.(*Parser).typeRegistry
@@ -807,7 +928,7 @@ yydefault:
 				Body:       yyDollar[6].stmt.(*domain.BlockStmt),
 			}
 		}
-	case 15:
+	case 16:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			reg := yylex.(*Parser).typeRegistry
@@ -820,25 +941,29 @@ yydefault:
 				Body:       yyDollar[5].stmt.(*domain.BlockStmt),
 			}
 		}
-	case 16:
+	case 17:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
+			// Register the struct so that later type references resolve to it;
+			// duplicate declarations are reported during semantic analysis
+			yylex.(*Parser).typeRegistry.CreateStructType(yyDollar[2].token.Value, yyDollar[4].fields)
 			yyVAL.decl = &domain.StructDecl{
 				BaseNode: domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
 				Name:     yyDollar[2].token.Value,
 				Fields:   yyDollar[4].fields,
 			}
 		}
-	case 17:
+	case 18:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
+			yylex.(*Parser).typeRegistry.CreateStructType(yyDollar[2].token.Value, nil)
 			yyVAL.decl = &domain.StructDecl{
 				BaseNode: domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
 				Name:     yyDollar[2].token.Value,
 				Fields:   []domain.StructField{},
 			}
 		}
-	case 18:
+	case 19:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			reg := yylex.(*Parser).typeRegistry
@@ -848,16 +973,21 @@ yydefault:
 				yyVAL.typ = &domain.TypeError{Message: fmt.Sprintf("unknown type: %s", yyDollar[1].token.Value)}
 			}
 		}
-	case 19:
+	case 20:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			size, _ := strconv.ParseInt(yyDollar[2].token.Value, 10, 32)
-			yyVAL.typ = &domain.ArrayType{
+			array := &domain.ArrayType{
 				ElementType: yyDollar[4].typ,
-				Size:        int(size),
+				SizeExpr:    yyDollar[2].expr,
 			}
+			if lit, ok := yyDollar[2].expr.(*domain.LiteralExpr); ok {
+				if size, ok := lit.Value.(int64); ok {
+					array.Size, array.SizeExpr = int(size), nil
+				}
+			}
+			yyVAL.typ = array
 		}
-	case 20:
+	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.typ = &domain.ArrayType{
@@ -865,17 +995,17 @@ yydefault:
 				Size:        -1, // -1 indicates dynamic array
 			}
 		}
-	case 21:
+	case 22:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.params = []domain.Parameter{yyDollar[1].param}
 		}
-	case 22:
+	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.params = append(yyDollar[1].params, yyDollar[3].param)
 		}
-	case 23:
+	case 24:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.param = domain.Parameter{
@@ -883,17 +1013,17 @@ yydefault:
 				Type: yyDollar[2].typ,
 			}
 		}
-	case 24:
+	case 25:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.fields = []domain.StructField{yyDollar[1].field}
 		}
-	case 25:
+	case 26:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.fields = append(yyDollar[1].fields, yyDollar[2].field)
 		}
-	case 26:
+	case 27:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.field = domain.StructField{
@@ -901,21 +1031,16 @@ yydefault:
 				Type: yyDollar[2].typ,
 			}
 		}
-	case 27:
+	case 28:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.stmts = []domain.Statement{}
 		}
-	case 28:
+	case 29:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmts = append(yyDollar[1].stmts, yyDollar[2].stmt)
 		}
-	case 29:
-		yyDollar = yyS[yypt-1 : yypt+1]
-		{
-			yyVAL.stmt = yyDollar[1].stmt
-		}
 	case 30:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
@@ -952,6 +1077,26 @@ yydefault:
 			yyVAL.stmt = yyDollar[1].stmt
 		}
 	case 37:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 38:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 39:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 40:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.stmt = yyDollar[1].stmt
+		}
+	case 41:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &domain.VarDeclStmt{
@@ -961,7 +1106,7 @@ yydefault:
 				Initializer: nil,
 			}
 		}
-	case 38:
+	case 42:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &domain.VarDeclStmt{
@@ -971,7 +1116,36 @@ yydefault:
 				Initializer: yyDollar[5].expr,
 			}
 		}
-	case 39:
+	case 43:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.stmt = &domain.VarDeclStmt{
+				BaseNode:    domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+				Name:        yyDollar[2].token.Value,
+				Initializer: yyDollar[4].expr,
+			}
+		}
+	case 44:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = &domain.VarDeclStmt{
+				BaseNode:    domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+				Name:        yyDollar[1].token.Value,
+				Initializer: yyDollar[3].expr,
+			}
+		}
+	case 45:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.stmt = &domain.VarDeclStmt{
+				BaseNode:    domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+				Name:        yyDollar[2].token.Value,
+				Type_:       yyDollar[3].typ,
+				Initializer: yyDollar[5].expr,
+				Constant:    true,
+			}
+		}
+	case 46:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &domain.AssignStmt{
@@ -980,7 +1154,42 @@ yydefault:
 				Value:    yyDollar[3].expr,
 			}
 		}
-	case 40:
+	case 47:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = createCompoundAssignStmt(yyDollar[1].expr, domain.Add, yyDollar[3].expr)
+		}
+	case 48:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = createCompoundAssignStmt(yyDollar[1].expr, domain.Sub, yyDollar[3].expr)
+		}
+	case 49:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = createCompoundAssignStmt(yyDollar[1].expr, domain.Mul, yyDollar[3].expr)
+		}
+	case 50:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = createCompoundAssignStmt(yyDollar[1].expr, domain.Div, yyDollar[3].expr)
+		}
+	case 51:
+		yyDollar = yyS[yypt-4 : yypt+1]
+		{
+			yyVAL.stmt = createCompoundAssignStmt(yyDollar[1].expr, domain.Mod, yyDollar[3].expr)
+		}
+	case 52:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = createCompoundAssignStmt(yyDollar[1].expr, domain.Add, nil)
+		}
+	case 53:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = createCompoundAssignStmt(yyDollar[1].expr, domain.Sub, nil)
+		}
+	case 54:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.stmt = &domain.IfStmt{
@@ -990,7 +1199,7 @@ yydefault:
 				ElseStmt:  nil,
 			}
 		}
-	case 41:
+	case 55:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &domain.IfStmt{
@@ -1000,7 +1209,7 @@ yydefault:
 				ElseStmt:  yyDollar[7].stmt,
 			}
 		}
-	case 42:
+	case 56:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.stmt = &domain.WhileStmt{
@@ -1009,7 +1218,7 @@ yydefault:
 				Body:      yyDollar[5].stmt,
 			}
 		}
-	case 43:
+	case 57:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &domain.ForStmt{
@@ -1020,7 +1229,7 @@ yydefault:
 				Body:      yyDollar[8].stmt,
 			}
 		}
-	case 44:
+	case 58:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &domain.ForStmt{
@@ -1031,7 +1240,49 @@ yydefault:
 				Body:      yyDollar[8].stmt,
 			}
 		}
-	case 45:
+	case 59:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyDollar[3].stmt.(*domain.WhileStmt).Label = yyDollar[1].token.Value
+			yyVAL.stmt = yyDollar[3].stmt
+		}
+	case 60:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyDollar[3].stmt.(*domain.ForStmt).Label = yyDollar[1].token.Value
+			yyVAL.stmt = yyDollar[3].stmt
+		}
+	case 61:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.stmt = &domain.BreakStmt{
+				BaseNode: domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+			}
+		}
+	case 62:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = &domain.BreakStmt{
+				BaseNode: domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+				Label:    yyDollar[2].token.Value,
+			}
+		}
+	case 63:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.stmt = &domain.ContinueStmt{
+				BaseNode: domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+			}
+		}
+	case 64:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = &domain.ContinueStmt{
+				BaseNode: domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+				Label:    yyDollar[2].token.Value,
+			}
+		}
+	case 65:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &domain.ReturnStmt{
@@ -1039,7 +1290,7 @@ yydefault:
 				Value:    nil,
 			}
 		}
-	case 46:
+	case 66:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &domain.ReturnStmt{
@@ -1047,7 +1298,7 @@ yydefault:
 				Value:    yyDollar[2].expr,
 			}
 		}
-	case 47:
+	case 67:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &domain.ExprStmt{
@@ -1055,7 +1306,7 @@ yydefault:
 				Expression: yyDollar[1].expr,
 			}
 		}
-	case 48:
+	case 68:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &domain.BlockStmt{
@@ -1063,87 +1314,112 @@ yydefault:
 				Statements: yyDollar[2].stmts,
 			}
 		}
-	case 49:
+	case 69:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 50:
+	case 70:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 51:
+	case 71:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Add, yyDollar[3].expr)
 		}
-	case 52:
+	case 72:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Sub, yyDollar[3].expr)
 		}
-	case 53:
+	case 73:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Mul, yyDollar[3].expr)
 		}
-	case 54:
+	case 74:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Div, yyDollar[3].expr)
 		}
-	case 55:
+	case 75:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Mod, yyDollar[3].expr)
 		}
-	case 56:
+	case 76:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Eq, yyDollar[3].expr)
 		}
-	case 57:
+	case 77:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Ne, yyDollar[3].expr)
 		}
-	case 58:
+	case 78:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Lt, yyDollar[3].expr)
 		}
-	case 59:
+	case 79:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Le, yyDollar[3].expr)
 		}
-	case 60:
+	case 80:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Gt, yyDollar[3].expr)
 		}
-	case 61:
+	case 81:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Ge, yyDollar[3].expr)
 		}
-	case 62:
+	case 82:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.And, yyDollar[3].expr)
 		}
-	case 63:
+	case 83:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Or, yyDollar[3].expr)
 		}
-	case 64:
+	case 84:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.BitAnd, yyDollar[3].expr)
+		}
+	case 85:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.BitOr, yyDollar[3].expr)
+		}
+	case 86:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.BitXor, yyDollar[3].expr)
+		}
+	case 87:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Shl, yyDollar[3].expr)
+		}
+	case 88:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.expr = createBinaryExpr(yyDollar[1].expr, domain.Shr, yyDollar[3].expr)
+		}
+	case 89:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 65:
+	case 90:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = &domain.UnaryExpr{
@@ -1152,7 +1428,7 @@ yydefault:
 				Operand:  yyDollar[2].expr,
 			}
 		}
-	case 66:
+	case 91:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.expr = &domain.UnaryExpr{
@@ -1161,21 +1437,39 @@ yydefault:
 				Operand:  yyDollar[2].expr,
 			}
 		}
-	case 67:
+	case 92:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.expr = &domain.UnaryExpr{
+				BaseNode: domain.BaseNode{Location: getLocationFromToken(yyDollar[1].token)},
+				Operator: domain.BitNot,
+				Operand:  yyDollar[2].expr,
+			}
+		}
+	case 93:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[1].expr
 		}
-	case 68:
+	case 94:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.expr = &domain.CallExpr{
-				BaseNode: domain.BaseNode{Location: yyDollar[1].expr.GetLocation()},
-				Function: yyDollar[1].expr,
-				Args:     yyDollar[3].exprs,
+			if target := castTarget(yylex.(*Parser).typeRegistry, yyDollar[1].expr, yyDollar[3].exprs); target != nil {
+				// Calling a builtin type name converts its argument
+				yyVAL.expr = &domain.CastExpr{
+					BaseNode: domain.BaseNode{Location: yyDollar[1].expr.GetLocation()},
+					Target:   target,
+					Value:    yyDollar[3].exprs[0],
+				}
+			} else {
+				yyVAL.expr = &domain.CallExpr{
+					BaseNode: domain.BaseNode{Location: yyDollar[1].expr.GetLocation()},
+					Function: yyDollar[1].expr,
+					Args:     yyDollar[3].exprs,
+				}
 			}
 		}
-	case 69:
+	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &domain.CallExpr{
@@ -1184,7 +1478,7 @@ yydefault:
 				Args:     []domain.Expression{},
 			}
 		}
-	case 70:
+	case 96:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.expr = &domain.IndexExpr{
@@ -1193,7 +1487,7 @@ yydefault:
 				Index:    yyDollar[3].expr,
 			}
 		}
-	case 71:
+	case 97:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = &domain.MemberExpr{
@@ -1202,17 +1496,17 @@ yydefault:
 				Member:   yyDollar[3].token.Value,
 			}
 		}
-	case 72:
+	case 98:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exprs = []domain.Expression{yyDollar[1].expr}
 		}
-	case 73:
+	case 99:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exprs = append(yyDollar[1].exprs, yyDollar[3].expr)
 		}
-	case 74:
+	case 100:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = &domain.IdentifierExpr{
@@ -1220,7 +1514,7 @@ yydefault:
 				Name:     yyDollar[1].token.Value,
 			}
 		}
-	case 75:
+	case 101:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			val, _ := strconv.ParseInt(yyDollar[1].token.Value, 10, 64)
@@ -1229,7 +1523,7 @@ yydefault:
 				Value:    val,
 			}
 		}
-	case 76:
+	case 102:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			val, _ := strconv.ParseFloat(yyDollar[1].token.Value, 64)
@@ -1238,7 +1532,7 @@ yydefault:
 				Value:    val,
 			}
 		}
-	case 77:
+	case 103:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = &domain.LiteralExpr{
@@ -1246,7 +1540,7 @@ yydefault:
 				Value:    yyDollar[1].token.Value,
 			}
 		}
-	case 78:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = &domain.LiteralExpr{
@@ -1254,7 +1548,7 @@ yydefault:
 				Value:    true,
 			}
 		}
-	case 79:
+	case 105:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.expr = &domain.LiteralExpr{
@@ -1262,12 +1556,12 @@ yydefault:
 				Value:    false,
 			}
 		}
-	case 80:
+	case 106:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.expr = yyDollar[2].expr
 		}
-	case 81:
+	case 107:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.token = yyDollar[1].token
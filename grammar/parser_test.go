@@ -135,6 +135,49 @@ func TestParserParseVariableDeclaration(t *testing.T) {
 	}
 }
 
+// TestParserParseStructTypeReference tests that declared structs resolve as types
+func TestParserParseStructTypeReference(t *testing.T) {
+	parser := NewRecursiveDescentParser()
+
+	source := `struct Point {
+		x int;
+		y int;
+	}
+
+	func origin() -> Point {
+		var p Point;
+		return p;
+	}`
+
+	lexerInstance := lexer.NewLexer()
+	err := lexerInstance.SetInput("test.sl", strings.NewReader(source))
+	if err != nil {
+		t.Fatalf("SetInput failed: %v", err)
+	}
+	program, err := parser.Parse(lexerInstance)
+	if err != nil {
+		t.Fatalf("Struct program parse failed: %v", err)
+	}
+
+	funcDecl, ok := program.Declarations[1].(*domain.FunctionDecl)
+	if !ok {
+		t.Fatal("Second declaration should be function declaration")
+	}
+
+	structType, ok := funcDecl.ReturnType.(*domain.StructType)
+	if !ok {
+		t.Fatalf("Expected struct return type, got %T", funcDecl.ReturnType)
+	}
+	if structType.Name != "Point" || len(structType.Order) != 2 {
+		t.Errorf("Unexpected struct type: %s %v", structType.Name, structType.Order)
+	}
+
+	varDecl := funcDecl.Body.Statements[0].(*domain.VarDeclStmt)
+	if _, ok := varDecl.Type_.(*domain.StructType); !ok {
+		t.Errorf("Expected struct local type, got %T", varDecl.Type_)
+	}
+}
+
 // TestParserParseExpressions tests parsing various expressions
 func TestParserParseExpressions(t *testing.T) {
 	parser := NewRecursiveDescentParser()
@@ -218,6 +261,17 @@ func TestParserParseControlFlow(t *testing.T) {
 				}
 			}`,
 		},
+		{
+			name: "labeled_loops",
+			source: `func test() -> void {
+				outer: while (true) {
+					for (var i int = 0; i < 3; i = i + 1;) {
+						continue outer;
+					}
+					break outer;
+				}
+			}`,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -241,6 +295,214 @@ func TestParserParseControlFlow(t *testing.T) {
 	}
 }
 
+// TestParserParseBreakContinue tests the AST produced for break, continue and loop labels
+func TestParserParseBreakContinue(t *testing.T) {
+	parser := NewRecursiveDescentParser()
+	source := `func test() -> void {
+		outer: while (true) {
+			continue;
+			break outer;
+		}
+	}`
+
+	lexerInstance := lexer.NewLexer()
+	if err := lexerInstance.SetInput("test.sl", strings.NewReader(source)); err != nil {
+		t.Fatalf("SetInput failed: %v", err)
+	}
+	program, err := parser.Parse(lexerInstance)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := program.Declarations[0].(*domain.FunctionDecl)
+	loop, ok := fn.Body.Statements[0].(*domain.WhileStmt)
+	if !ok {
+		t.Fatalf("Expected WhileStmt, got %T", fn.Body.Statements[0])
+	}
+	if loop.Label != "outer" {
+		t.Errorf("Expected loop label 'outer', got %q", loop.Label)
+	}
+
+	body := loop.Body.(*domain.BlockStmt)
+	if cont, ok := body.Statements[0].(*domain.ContinueStmt); !ok || cont.Label != "" {
+		t.Errorf("Expected unlabeled ContinueStmt, got %#v", body.Statements[0])
+	}
+	if brk, ok := body.Statements[1].(*domain.BreakStmt); !ok || brk.Label != "outer" {
+		t.Errorf("Expected BreakStmt labeled 'outer', got %#v", body.Statements[1])
+	}
+}
+
+// TestParserParseCastExpr tests that calls of builtin type names become casts
+func TestParserParseCastExpr(t *testing.T) {
+	parser := NewRecursiveDescentParser()
+	source := `func test(n int) -> float {
+		var s string = string(n);
+		return float(n) + half(2.0);
+	}`
+
+	lexerInstance := lexer.NewLexer()
+	if err := lexerInstance.SetInput("test.sl", strings.NewReader(source)); err != nil {
+		t.Fatalf("SetInput failed: %v", err)
+	}
+	program, err := parser.Parse(lexerInstance)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := program.Declarations[0].(*domain.FunctionDecl)
+	decl := fn.Body.Statements[0].(*domain.VarDeclStmt)
+	if cast, ok := decl.Initializer.(*domain.CastExpr); !ok || cast.Target.String() != "string" {
+		t.Errorf("Expected cast to string, got %#v", decl.Initializer)
+	}
+
+	sum := fn.Body.Statements[1].(*domain.ReturnStmt).Value.(*domain.BinaryExpr)
+	if cast, ok := sum.Left.(*domain.CastExpr); !ok || cast.Target.String() != "float" {
+		t.Errorf("Expected cast to float, got %#v", sum.Left)
+	}
+	if _, ok := sum.Right.(*domain.CallExpr); !ok {
+		t.Errorf("Expected ordinary call for half(), got %#v", sum.Right)
+	}
+}
+
+// TestParserParseConstDecl tests global and local constants and constant array sizes
+func TestParserParseConstDecl(t *testing.T) {
+	parser := NewRecursiveDescentParser()
+	source := `const N int = 4;
+	func test() -> void {
+		const M int = N * 2;
+		var a [M]int;
+		var b [3]int;
+	}`
+
+	lexerInstance := lexer.NewLexer()
+	if err := lexerInstance.SetInput("test.sl", strings.NewReader(source)); err != nil {
+		t.Fatalf("SetInput failed: %v", err)
+	}
+	program, err := parser.Parse(lexerInstance)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	global, ok := program.Declarations[0].(*domain.VarDeclStmt)
+	if !ok || !global.Constant || global.Name != "N" {
+		t.Fatalf("Expected global constant N, got %#v", program.Declarations[0])
+	}
+
+	fn := program.Declarations[1].(*domain.FunctionDecl)
+	if local := fn.Body.Statements[0].(*domain.VarDeclStmt); !local.Constant {
+		t.Errorf("Expected local constant M, got %#v", local)
+	}
+	sized := fn.Body.Statements[1].(*domain.VarDeclStmt).Type_.(*domain.ArrayType)
+	if ident, ok := sized.SizeExpr.(*domain.IdentifierExpr); !ok || ident.Name != "M" {
+		t.Errorf("Expected array size expression M, got %#v", sized.SizeExpr)
+	}
+	literal := fn.Body.Statements[2].(*domain.VarDeclStmt).Type_.(*domain.ArrayType)
+	if literal.Size != 3 || literal.SizeExpr != nil {
+		t.Errorf("Expected literal array size 3, got %#v", literal)
+	}
+}
+
+// TestParserParseInferredVarDecl tests variable declarations without a type
+func TestParserParseInferredVarDecl(t *testing.T) {
+	parser := NewRecursiveDescentParser()
+	source := `func test() -> void {
+		var n = 1;
+		m := n + 1;
+	}`
+
+	lexerInstance := lexer.NewLexer()
+	if err := lexerInstance.SetInput("test.sl", strings.NewReader(source)); err != nil {
+		t.Fatalf("SetInput failed: %v", err)
+	}
+	program, err := parser.Parse(lexerInstance)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := program.Declarations[0].(*domain.FunctionDecl)
+	for i, name := range []string{"n", "m"} {
+		decl, ok := fn.Body.Statements[i].(*domain.VarDeclStmt)
+		if !ok || decl.Name != name || decl.Type_ != nil || decl.Initializer == nil {
+			t.Errorf("Expected untyped declaration of %s, got %#v", name, fn.Body.Statements[i])
+		}
+	}
+}
+
+// TestParserParseCompoundAssign tests compound assignment, increment and decrement statements
+func TestParserParseCompoundAssign(t *testing.T) {
+	parser := NewRecursiveDescentParser()
+	source := `func test() -> void {
+		a[i] += 2;
+		x -= 1;
+		x *= 3;
+		x /= 4;
+		x %= 5;
+		i++;
+		i--;
+	}`
+
+	lexerInstance := lexer.NewLexer()
+	if err := lexerInstance.SetInput("test.sl", strings.NewReader(source)); err != nil {
+		t.Fatalf("SetInput failed: %v", err)
+	}
+	program, err := parser.Parse(lexerInstance)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	fn := program.Declarations[0].(*domain.FunctionDecl)
+	expected := []domain.BinaryOperator{domain.Add, domain.Sub, domain.Mul, domain.Div, domain.Mod, domain.Add, domain.Sub}
+	for i, op := range expected {
+		stmt, ok := fn.Body.Statements[i].(*domain.CompoundAssignStmt)
+		if !ok || stmt.Operator != op {
+			t.Errorf("Statement %d: expected compound assignment with %s, got %#v", i, op, fn.Body.Statements[i])
+			continue
+		}
+		if increment := i >= 5; increment != (stmt.Value == nil) {
+			t.Errorf("Statement %d: unexpected value %#v", i, stmt.Value)
+		}
+	}
+	if _, ok := fn.Body.Statements[0].(*domain.CompoundAssignStmt).Target.(*domain.IndexExpr); !ok {
+		t.Error("Expected an index expression target")
+	}
+}
+
+// TestParserBitwisePrecedence tests that bitwise and shift operators bind as in C
+func TestParserBitwisePrecedence(t *testing.T) {
+	parser := NewRecursiveDescentParser()
+	source := `func test() -> void {
+		x = a | b ^ ~c & d << 1 + e == f;
+	}`
+
+	lexerInstance := lexer.NewLexer()
+	if err := lexerInstance.SetInput("test.sl", strings.NewReader(source)); err != nil {
+		t.Fatalf("SetInput failed: %v", err)
+	}
+	program, err := parser.Parse(lexerInstance)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// a | (b ^ (~c & ((d << (1 + e)) == f)))
+	value := program.Declarations[0].(*domain.FunctionDecl).Body.Statements[0].(*domain.AssignStmt).Value
+	expected := []domain.BinaryOperator{domain.BitOr, domain.BitXor, domain.BitAnd, domain.Eq, domain.Shl, domain.Add}
+	for i, op := range expected {
+		binary, ok := value.(*domain.BinaryExpr)
+		if !ok || binary.Operator != op {
+			t.Fatalf("Level %d: expected %s, got %#v", i, op, value)
+		}
+		if op == domain.BitAnd {
+			if unary, ok := binary.Left.(*domain.UnaryExpr); !ok || unary.Operator != domain.BitNot {
+				t.Errorf("Expected ~c as the left operand of &, got %#v", binary.Left)
+			}
+		}
+		value = binary.Right
+		if op == domain.Eq {
+			value = binary.Left
+		}
+	}
+}
+
 // TestParserErrorRecovery tests error recovery
 func TestParserErrorRecovery(t *testing.T) {
 	parser := NewRecursiveDescentParser()
@@ -499,6 +761,9 @@ func TestParserLex_TokenMapping(t *testing.T) {
 		{interfaces.TokenReturn, RETURN, "RETURN"},
 		{interfaces.TokenTrue, TRUE, "TRUE"},
 		{interfaces.TokenFalse, FALSE, "FALSE"},
+		{interfaces.TokenBreak, BREAK, "BREAK"},
+		{interfaces.TokenContinue, CONTINUE, "CONTINUE"},
+		{interfaces.TokenConst, CONST, "CONST"},
 		{interfaces.TokenPlus, PLUS, "PLUS"},
 		{interfaces.TokenMinus, MINUS, "MINUS"},
 		{interfaces.TokenStar, STAR, "STAR"},
@@ -513,7 +778,21 @@ func TestParserLex_TokenMapping(t *testing.T) {
 		{interfaces.TokenAnd, AND, "AND"},
 		{interfaces.TokenOr, OR, "OR"},
 		{interfaces.TokenNot, NOT, "NOT"},
+		{interfaces.TokenAmpersand, AMPERSAND, "AMPERSAND"},
+		{interfaces.TokenPipe, PIPE, "PIPE"},
+		{interfaces.TokenCaret, CARET, "CARET"},
+		{interfaces.TokenTilde, TILDE, "TILDE"},
+		{interfaces.TokenShiftLeft, SHIFT_LEFT, "SHIFT_LEFT"},
+		{interfaces.TokenShiftRight, SHIFT_RIGHT, "SHIFT_RIGHT"},
 		{interfaces.TokenAssign, ASSIGN, "ASSIGN"},
+		{interfaces.TokenDefine, DEFINE, "DEFINE"},
+		{interfaces.TokenPlusAssign, PLUS_ASSIGN, "PLUS_ASSIGN"},
+		{interfaces.TokenMinusAssign, MINUS_ASSIGN, "MINUS_ASSIGN"},
+		{interfaces.TokenStarAssign, STAR_ASSIGN, "STAR_ASSIGN"},
+		{interfaces.TokenSlashAssign, SLASH_ASSIGN, "SLASH_ASSIGN"},
+		{interfaces.TokenPercentAssign, PERCENT_ASSIGN, "PERCENT_ASSIGN"},
+		{interfaces.TokenIncrement, INCREMENT, "INCREMENT"},
+		{interfaces.TokenDecrement, DECREMENT, "DECREMENT"},
 		{interfaces.TokenLeftParen, LEFT_PAREN, "LEFT_PAREN"},
 		{interfaces.TokenRightParen, RIGHT_PAREN, "RIGHT_PAREN"},
 		{interfaces.TokenLeftBrace, LEFT_BRACE, "LEFT_BRACE"},
@@ -95,6 +95,12 @@ func (p *Parser) Lex(lval *yySymType) int {
 		return TRUE
 	case interfaces.TokenFalse:
 		return FALSE
+	case interfaces.TokenBreak:
+		return BREAK
+	case interfaces.TokenContinue:
+		return CONTINUE
+	case interfaces.TokenConst:
+		return CONST
 	case interfaces.TokenPlus:
 		return PLUS
 	case interfaces.TokenMinus:
@@ -123,8 +129,36 @@ func (p *Parser) Lex(lval *yySymType) int {
 		return OR
 	case interfaces.TokenNot:
 		return NOT
+	case interfaces.TokenAmpersand:
+		return AMPERSAND
+	case interfaces.TokenPipe:
+		return PIPE
+	case interfaces.TokenCaret:
+		return CARET
+	case interfaces.TokenTilde:
+		return TILDE
+	case interfaces.TokenShiftLeft:
+		return SHIFT_LEFT
+	case interfaces.TokenShiftRight:
+		return SHIFT_RIGHT
 	case interfaces.TokenAssign:
 		return ASSIGN
+	case interfaces.TokenDefine:
+		return DEFINE
+	case interfaces.TokenPlusAssign:
+		return PLUS_ASSIGN
+	case interfaces.TokenMinusAssign:
+		return MINUS_ASSIGN
+	case interfaces.TokenStarAssign:
+		return STAR_ASSIGN
+	case interfaces.TokenSlashAssign:
+		return SLASH_ASSIGN
+	case interfaces.TokenPercentAssign:
+		return PERCENT_ASSIGN
+	case interfaces.TokenIncrement:
+		return INCREMENT
+	case interfaces.TokenDecrement:
+		return DECREMENT
 	case interfaces.TokenLeftParen:
 		return LEFT_PAREN
 	case interfaces.TokenRightParen:
@@ -63,7 +63,7 @@ import (
 %token <token> INT FLOAT STRING BOOL IDENTIFIER
 
 // Keywords
-%token <token> FUNC STRUCT VAR IF ELSE WHILE FOR RETURN TRUE FALSE
+%token <token> FUNC STRUCT VAR IF ELSE WHILE FOR RETURN TRUE FALSE BREAK CONTINUE CONST
 
 // Arithmetic operators
 %token <token> PLUS MINUS STAR SLASH PERCENT
@@ -74,8 +74,12 @@ import (
 // Logical operators
 %token <token> AND OR NOT
 
-// Assignment operator
-%token <token> ASSIGN
+// Bitwise operators
+%token <token> AMPERSAND PIPE CARET TILDE SHIFT_LEFT SHIFT_RIGHT
+
+// Assignment operators
+%token <token> ASSIGN DEFINE
+%token <token> PLUS_ASSIGN MINUS_ASSIGN STAR_ASSIGN SLASH_ASSIGN PERCENT_ASSIGN INCREMENT DECREMENT
 
 // Delimiters
 %token <token> LEFT_PAREN RIGHT_PAREN LEFT_BRACE RIGHT_BRACE LEFT_BRACKET RIGHT_BRACKET
@@ -94,6 +98,7 @@ import (
 
 // Statements
 %type <stmt> statement var_decl_stmt assign_stmt if_stmt while_stmt for_stmt return_stmt expr_stmt block_stmt
+%type <stmt> labeled_stmt break_stmt continue_stmt
 %type <stmts> statement_list
 
 // Expressions
@@ -121,11 +126,15 @@ import (
 // Expression operators (lowest to highest precedence)
 %left OR                                    // Logical OR
 %left AND                                   // Logical AND
+%left PIPE                                  // Bitwise OR
+%left CARET                                 // Bitwise XOR
+%left AMPERSAND                             // Bitwise AND
 %left EQUAL NOT_EQUAL                      // Equality operators
 %left LESS LESS_EQUAL GREATER GREATER_EQUAL // Relational operators
+%left SHIFT_LEFT SHIFT_RIGHT                // Shift operators
 %left PLUS MINUS                           // Additive operators
 %left STAR SLASH PERCENT                   // Multiplicative operators
-%right UNARY_MINUS NOT                     // Unary operators (highest precedence)
+%right UNARY_MINUS NOT TILDE               // Unary operators (highest precedence)
 
 %%
 
@@ -189,6 +198,16 @@ global_var_decl:
 			Initializer: $4,
 		}
 	}
+	// Global constant: const name type = value;
+	| CONST identifier type ASSIGN expression SEMICOLON {
+		$$ = &domain.VarDeclStmt{
+			BaseNode:    domain.BaseNode{Location: getLocationFromToken($2)},
+			Name:        $2.Value,
+			Type_:       $3,
+			Initializer: $5,
+			Constant:    true,
+		}
+	}
 
 // =============================================================================
 // FUNCTION DECLARATIONS
@@ -268,6 +287,9 @@ function_decl:
 // Struct declaration with optional field list
 struct_decl:
 	STRUCT identifier LEFT_BRACE struct_field_list RIGHT_BRACE {
+		// Register the struct so that later type references resolve to it;
+		// duplicate declarations are reported during semantic analysis
+		yylex.(*Parser).typeRegistry.CreateStructType($2.Value, $4)
 		$$ = &domain.StructDecl{
 			BaseNode: domain.BaseNode{Location: getLocationFromToken($1)},
 			Name:     $2.Value,
@@ -275,6 +297,7 @@ struct_decl:
 		}
 	}
 	| STRUCT identifier LEFT_BRACE RIGHT_BRACE {
+		yylex.(*Parser).typeRegistry.CreateStructType($2.Value, nil)
 		$$ = &domain.StructDecl{
 			BaseNode: domain.BaseNode{Location: getLocationFromToken($1)},
 			Name:     $2.Value,
@@ -296,13 +319,19 @@ type:
 			$$ = &domain.TypeError{Message: fmt.Sprintf("unknown type: %s", $1.Value)}
 		}
 	}
-	// Fixed-size array: [size]type
-	| LEFT_BRACKET INT RIGHT_BRACKET type {
-		size, _ := strconv.ParseInt($2.Value, 10, 32)
-		$$ = &domain.ArrayType{
+	// Fixed-size array: [size]type, where the size is an integer literal or a
+	// constant expression evaluated by the semantic analyzer
+	| LEFT_BRACKET expression RIGHT_BRACKET type {
+		array := &domain.ArrayType{
 			ElementType: $4,
-			Size:        int(size),
+			SizeExpr:    $2,
+		}
+		if lit, ok := $2.(*domain.LiteralExpr); ok {
+			if size, ok := lit.Value.(int64); ok {
+				array.Size, array.SizeExpr = int(size), nil
+			}
 		}
+		$$ = array
 	}
 	// Dynamic array: []type
 	| LEFT_BRACKET RIGHT_BRACKET type {
@@ -371,6 +400,9 @@ statement:
 	| return_stmt { $$ = $1 }
 	| expr_stmt   { $$ = $1 }
 	| block_stmt  { $$ = $1 }
+	| labeled_stmt  { $$ = $1 }
+	| break_stmt    { $$ = $1 }
+	| continue_stmt { $$ = $1 }
 
 // Local variable declaration
 var_decl_stmt:
@@ -390,6 +422,32 @@ var_decl_stmt:
 			Initializer: $5,
 		}
 	}
+	// Type inferred from the initializer by the semantic analyzer: var name = value;
+	| VAR identifier ASSIGN expression SEMICOLON {
+		$$ = &domain.VarDeclStmt{
+			BaseNode:    domain.BaseNode{Location: getLocationFromToken($1)},
+			Name:        $2.Value,
+			Initializer: $4,
+		}
+	}
+	// Short declaration, equivalent to var name = value;
+	| identifier DEFINE expression SEMICOLON {
+		$$ = &domain.VarDeclStmt{
+			BaseNode:    domain.BaseNode{Location: getLocationFromToken($1)},
+			Name:        $1.Value,
+			Initializer: $3,
+		}
+	}
+	// Local constant: const name type = value;
+	| CONST identifier type ASSIGN expression SEMICOLON {
+		$$ = &domain.VarDeclStmt{
+			BaseNode:    domain.BaseNode{Location: getLocationFromToken($1)},
+			Name:        $2.Value,
+			Type_:       $3,
+			Initializer: $5,
+			Constant:    true,
+		}
+	}
 
 // Assignment statement
 assign_stmt:
@@ -400,6 +458,29 @@ assign_stmt:
 			Value:    $3,
 		}
 	}
+	// Compound assignment: target op= value;
+	| expression PLUS_ASSIGN expression SEMICOLON {
+		$$ = createCompoundAssignStmt($1, domain.Add, $3)
+	}
+	| expression MINUS_ASSIGN expression SEMICOLON {
+		$$ = createCompoundAssignStmt($1, domain.Sub, $3)
+	}
+	| expression STAR_ASSIGN expression SEMICOLON {
+		$$ = createCompoundAssignStmt($1, domain.Mul, $3)
+	}
+	| expression SLASH_ASSIGN expression SEMICOLON {
+		$$ = createCompoundAssignStmt($1, domain.Div, $3)
+	}
+	| expression PERCENT_ASSIGN expression SEMICOLON {
+		$$ = createCompoundAssignStmt($1, domain.Mod, $3)
+	}
+	// Increment and decrement: target++; target--;
+	| expression INCREMENT SEMICOLON {
+		$$ = createCompoundAssignStmt($1, domain.Add, nil)
+	}
+	| expression DECREMENT SEMICOLON {
+		$$ = createCompoundAssignStmt($1, domain.Sub, nil)
+	}
 
 // If statement with optional else clause
 if_stmt:
@@ -452,6 +533,45 @@ for_stmt:
 		}
 	}
 
+// Labeled loop: the label can be targeted by break and continue
+labeled_stmt:
+	identifier COLON while_stmt {
+		$3.(*domain.WhileStmt).Label = $1.Value
+		$$ = $3
+	}
+	| identifier COLON for_stmt {
+		$3.(*domain.ForStmt).Label = $1.Value
+		$$ = $3
+	}
+
+// Break statement with optional loop label
+break_stmt:
+	BREAK SEMICOLON {
+		$$ = &domain.BreakStmt{
+			BaseNode: domain.BaseNode{Location: getLocationFromToken($1)},
+		}
+	}
+	| BREAK identifier SEMICOLON {
+		$$ = &domain.BreakStmt{
+			BaseNode: domain.BaseNode{Location: getLocationFromToken($1)},
+			Label:    $2.Value,
+		}
+	}
+
+// Continue statement with optional loop label
+continue_stmt:
+	CONTINUE SEMICOLON {
+		$$ = &domain.ContinueStmt{
+			BaseNode: domain.BaseNode{Location: getLocationFromToken($1)},
+		}
+	}
+	| CONTINUE identifier SEMICOLON {
+		$$ = &domain.ContinueStmt{
+			BaseNode: domain.BaseNode{Location: getLocationFromToken($1)},
+			Label:    $2.Value,
+		}
+	}
+
 // Return statement with optional value
 return_stmt:
 	RETURN SEMICOLON {
@@ -542,6 +662,23 @@ binary_expr:
 		$$ = createBinaryExpr($1, domain.Or, $3)
 	}
 
+	// Bitwise operators
+	| binary_expr AMPERSAND binary_expr {
+		$$ = createBinaryExpr($1, domain.BitAnd, $3)
+	}
+	| binary_expr PIPE binary_expr {
+		$$ = createBinaryExpr($1, domain.BitOr, $3)
+	}
+	| binary_expr CARET binary_expr {
+		$$ = createBinaryExpr($1, domain.BitXor, $3)
+	}
+	| binary_expr SHIFT_LEFT binary_expr {
+		$$ = createBinaryExpr($1, domain.Shl, $3)
+	}
+	| binary_expr SHIFT_RIGHT binary_expr {
+		$$ = createBinaryExpr($1, domain.Shr, $3)
+	}
+
 // Unary expressions
 unary_expr:
 	call_expr { $$ = $1 }
@@ -559,6 +696,13 @@ unary_expr:
 			Operand:  $2,
 		}
 	}
+	| TILDE unary_expr {
+		$$ = &domain.UnaryExpr{
+			BaseNode: domain.BaseNode{Location: getLocationFromToken($1)},
+			Operator: domain.BitNot,
+			Operand:  $2,
+		}
+	}
 
 // Call expressions and postfix operators
 call_expr:
@@ -566,10 +710,19 @@ call_expr:
 	
 	// Function call with arguments
 	| call_expr LEFT_PAREN argument_list RIGHT_PAREN {
-		$$ = &domain.CallExpr{
-			BaseNode: domain.BaseNode{Location: $1.GetLocation()},
-			Function: $1,
-			Args:     $3,
+		if target := castTarget(yylex.(*Parser).typeRegistry, $1, $3); target != nil {
+			// Calling a builtin type name converts its argument
+			$$ = &domain.CastExpr{
+				BaseNode: domain.BaseNode{Location: $1.GetLocation()},
+				Target:   target,
+				Value:    $3[0],
+			}
+		} else {
+			$$ = &domain.CallExpr{
+				BaseNode: domain.BaseNode{Location: $1.GetLocation()},
+				Function: $1,
+				Args:     $3,
+			}
 		}
 	}
 	// Function call without arguments
@@ -677,6 +830,34 @@ func createBinaryExpr(left domain.Expression, op domain.BinaryOperator, right do
 	}
 }
 
+// createCompoundAssignStmt creates a compound assignment node; a nil value
+// increments or decrements the target
+func createCompoundAssignStmt(target domain.Expression, op domain.BinaryOperator, value domain.Expression) *domain.CompoundAssignStmt {
+	return &domain.CompoundAssignStmt{
+		BaseNode: domain.BaseNode{Location: target.GetLocation()},
+		Target:   target,
+		Operator: op,
+		Value:    value,
+	}
+}
+
+// castTarget returns the builtin type named by a single-argument call such as
+// float(x), or nil if the call is an ordinary function call
+func castTarget(reg domain.TypeRegistry, fn domain.Expression, args []domain.Expression) domain.Type {
+	ident, ok := fn.(*domain.IdentifierExpr)
+	if !ok || len(args) != 1 {
+		return nil
+	}
+	t, exists := reg.GetType(ident.Name)
+	if !exists {
+		return nil
+	}
+	if basic, ok := t.(*domain.BasicType); !ok || basic.Kind == domain.VoidType {
+		return nil
+	}
+	return t
+}
+
 // getLocationFromToken extracts source location from a token
 func getLocationFromToken(token interfaces.Token) domain.SourceRange {
 	pos := token.Location
@@ -9,6 +9,7 @@ import (
 	"github.com/sokoide/llvm5/grammar"
 	"github.com/sokoide/llvm5/internal/domain"
 	"github.com/sokoide/llvm5/internal/infrastructure"
+	"github.com/sokoide/llvm5/internal/infrastructure/llvmir"
 	"github.com/sokoide/llvm5/internal/interfaces"
 	"github.com/sokoide/llvm5/lexer"
 	"github.com/sokoide/llvm5/semantic"
@@ -58,6 +59,7 @@ func DefaultCompilerConfig() CompilerConfig {
 			TargetTriple:      "",
 			OutputPath:        "",
 			WarningsAsErrors:  false,
+			BoundsChecks:      domain.DefaultBoundsChecks(0),
 		},
 		ErrorOutput: os.Stderr,
 		Verbose:     false,
@@ -144,8 +146,10 @@ func (factory *CompilerFactory) CreateCodeGenerator() interfaces.CodeGenerator {
 	if factory.config.UseMockComponents {
 		return NewMockCodeGenerator()
 	}
-	// Return the real LLVM IR generator
-	return infrastructure.NewRealLLVMIRGenerator()
+	// Return the real LLVM IR generator, building modules with the factory's backend
+	generator := infrastructure.NewRealLLVMIRGenerator()
+	generator.SetLLVMBackend(factory.CreateLLVMBackend())
+	return generator
 }
 
 // CreateErrorReporter creates an error reporter
@@ -196,9 +200,10 @@ func (factory *CompilerFactory) CreateMemoryManager() interfaces.MemoryManager {
 
 // CreateLLVMBackend creates an LLVM backend
 func (factory *CompilerFactory) CreateLLVMBackend() interfaces.LLVMBackend {
-	// For now, always return the mock backend
-	// In a real implementation, this would create the actual LLVM backend
-	return infrastructure.NewMockLLVMBackend()
+	if factory.config.UseMockComponents {
+		return infrastructure.NewMockLLVMBackend()
+	}
+	return llvmir.NewBackend()
 }
 
 // Mock implementations for development and testing
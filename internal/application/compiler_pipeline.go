@@ -31,6 +31,7 @@ func NewDefaultCompilerPipeline() *DefaultCompilerPipeline {
 			TargetTriple:      "",
 			OutputPath:        "",
 			WarningsAsErrors:  false,
+			BoundsChecks:      domain.DefaultBoundsChecks(0),
 		},
 	}
 }
@@ -86,6 +87,8 @@ func (cp *DefaultCompilerPipeline) Compile(filename string, input io.Reader, out
 		OptimizationLevel: cp.options.OptimizationLevel,
 		DebugInfo:         cp.options.DebugInfo,
 		TargetTriple:      cp.options.TargetTriple,
+		BoundsChecks:      cp.options.BoundsChecks,
+		DivisionChecks:    cp.options.DivisionChecks,
 	})
 
 	if err := cp.codeGenerator.Generate(ast); err != nil {
@@ -313,6 +316,8 @@ func (mcp *MultiFileCompilerPipeline) CompileFiles(files map[string]io.Reader, o
 		OptimizationLevel: mcp.options.OptimizationLevel,
 		DebugInfo:         mcp.options.DebugInfo,
 		TargetTriple:      mcp.options.TargetTriple,
+		BoundsChecks:      mcp.options.BoundsChecks,
+		DivisionChecks:    mcp.options.DivisionChecks,
 	})
 
 	for _, filename := range mcp.linkOrder {
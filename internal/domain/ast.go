@@ -17,16 +17,20 @@ type Visitor interface {
 	VisitCallExpr(expr *CallExpr) error
 	VisitIndexExpr(expr *IndexExpr) error
 	VisitMemberExpr(expr *MemberExpr) error
+	VisitCastExpr(expr *CastExpr) error
 
 	// Statements
 	VisitExprStmt(stmt *ExprStmt) error
 	VisitVarDeclStmt(stmt *VarDeclStmt) error
 	VisitAssignStmt(stmt *AssignStmt) error
+	VisitCompoundAssignStmt(stmt *CompoundAssignStmt) error
 	VisitIfStmt(stmt *IfStmt) error
 	VisitWhileStmt(stmt *WhileStmt) error
 	VisitForStmt(stmt *ForStmt) error
 	VisitReturnStmt(stmt *ReturnStmt) error
 	VisitBlockStmt(stmt *BlockStmt) error
+	VisitBreakStmt(stmt *BreakStmt) error
+	VisitContinueStmt(stmt *ContinueStmt) error
 
 	// Declarations
 	VisitFunctionDecl(decl *FunctionDecl) error
@@ -138,6 +142,18 @@ func (e *MemberExpr) Accept(visitor Visitor) error { return visitor.VisitMemberE
 func (e *MemberExpr) GetType() Type                { return e.Type_ }
 func (e *MemberExpr) SetType(t Type)               { e.Type_ = t }
 
+// CastExpr is an explicit conversion such as float(x) or string(n)
+type CastExpr struct {
+	BaseNode
+	Target Type
+	Value  Expression
+	Type_  Type
+}
+
+func (e *CastExpr) Accept(visitor Visitor) error { return visitor.VisitCastExpr(e) }
+func (e *CastExpr) GetType() Type                { return e.Type_ }
+func (e *CastExpr) SetType(t Type)               { e.Type_ = t }
+
 // Statement nodes
 type ExprStmt struct {
 	BaseNode
@@ -151,6 +167,7 @@ type VarDeclStmt struct {
 	Name        string
 	Type_       Type
 	Initializer Expression
+	Constant    bool // Declared with const; the initializer is evaluated at compile time
 }
 
 func (s *VarDeclStmt) Accept(visitor Visitor) error { return visitor.VisitVarDeclStmt(s) }
@@ -164,6 +181,18 @@ type AssignStmt struct {
 
 func (s *AssignStmt) Accept(visitor Visitor) error { return visitor.VisitAssignStmt(s) }
 
+// CompoundAssignStmt applies an arithmetic operator to its target and value
+// and stores the result back, as in x += v. The increment and decrement
+// statements x++ and x-- have no value and add or subtract one.
+type CompoundAssignStmt struct {
+	BaseNode
+	Target   Expression
+	Operator BinaryOperator // Add, Sub, Mul, Div or Mod
+	Value    Expression     // nil for ++ and --
+}
+
+func (s *CompoundAssignStmt) Accept(visitor Visitor) error { return visitor.VisitCompoundAssignStmt(s) }
+
 type IfStmt struct {
 	BaseNode
 	Condition Expression
@@ -175,6 +204,7 @@ func (s *IfStmt) Accept(visitor Visitor) error { return visitor.VisitIfStmt(s) }
 
 type WhileStmt struct {
 	BaseNode
+	Label     string // optional
 	Condition Expression
 	Body      Statement
 }
@@ -183,6 +213,7 @@ func (s *WhileStmt) Accept(visitor Visitor) error { return visitor.VisitWhileStm
 
 type ForStmt struct {
 	BaseNode
+	Label     string     // optional
 	Init      Statement  // optional
 	Condition Expression // optional
 	Update    Statement  // optional
@@ -205,6 +236,20 @@ type BlockStmt struct {
 
 func (s *BlockStmt) Accept(visitor Visitor) error { return visitor.VisitBlockStmt(s) }
 
+type BreakStmt struct {
+	BaseNode
+	Label string // optional, names the enclosing loop to exit
+}
+
+func (s *BreakStmt) Accept(visitor Visitor) error { return visitor.VisitBreakStmt(s) }
+
+type ContinueStmt struct {
+	BaseNode
+	Label string // optional, names the enclosing loop to continue
+}
+
+func (s *ContinueStmt) Accept(visitor Visitor) error { return visitor.VisitContinueStmt(s) }
+
 // Declaration nodes
 type Parameter struct {
 	Name string
@@ -265,6 +310,13 @@ const (
 	// Logical
 	And
 	Or
+
+	// Bitwise, on integers
+	BitAnd
+	BitOr
+	BitXor
+	Shl
+	Shr // Arithmetic for signed operands, logical for unsigned ones
 )
 
 func (op BinaryOperator) String() string {
@@ -295,6 +347,16 @@ func (op BinaryOperator) String() string {
 		return "&&"
 	case Or:
 		return "||"
+	case BitAnd:
+		return "&"
+	case BitOr:
+		return "|"
+	case BitXor:
+		return "^"
+	case Shl:
+		return "<<"
+	case Shr:
+		return ">>"
 	default:
 		return "unknown"
 	}
@@ -303,8 +365,9 @@ func (op BinaryOperator) String() string {
 type UnaryOperator int
 
 const (
-	Neg UnaryOperator = iota // -
-	Not                      // !
+	Neg    UnaryOperator = iota // -
+	Not                         // !
+	BitNot                      // ~
 )
 
 func (op UnaryOperator) String() string {
@@ -313,6 +376,8 @@ func (op UnaryOperator) String() string {
 		return "-"
 	case Not:
 		return "!"
+	case BitNot:
+		return "~"
 	default:
 		return "unknown"
 	}
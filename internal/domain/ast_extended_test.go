@@ -15,10 +15,13 @@ func (mv *MockVisitor) VisitStructDecl(node *StructDecl) error   { mv.visitedNod
 func (mv *MockVisitor) VisitBlockStmt(node *BlockStmt) error     { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitVarDeclStmt(node *VarDeclStmt) error { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitAssignStmt(node *AssignStmt) error   { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
+func (mv *MockVisitor) VisitCompoundAssignStmt(node *CompoundAssignStmt) error { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitIfStmt(node *IfStmt) error           { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitWhileStmt(node *WhileStmt) error     { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitForStmt(node *ForStmt) error         { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitReturnStmt(node *ReturnStmt) error   { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
+func (mv *MockVisitor) VisitBreakStmt(node *BreakStmt) error     { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
+func (mv *MockVisitor) VisitContinueStmt(node *ContinueStmt) error { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitExprStmt(node *ExprStmt) error       { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitBinaryExpr(node *BinaryExpr) error   { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitUnaryExpr(node *UnaryExpr) error     { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
@@ -27,6 +30,7 @@ func (mv *MockVisitor) VisitIdentifierExpr(node *IdentifierExpr) error { mv.visi
 func (mv *MockVisitor) VisitLiteralExpr(node *LiteralExpr) error { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitIndexExpr(node *IndexExpr) error     { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 func (mv *MockVisitor) VisitMemberExpr(node *MemberExpr) error   { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
+func (mv *MockVisitor) VisitCastExpr(node *CastExpr) error       { mv.visitedNodes = append(mv.visitedNodes, node); return nil }
 
 // TestIndexExprComplete tests IndexExpr with all methods
 func TestIndexExprComplete(t *testing.T) {
@@ -345,6 +349,10 @@ func TestAllStatementAccept(t *testing.T) {
 			"AssignStmt",
 			&AssignStmt{Target: &IdentifierExpr{Name: "x"}, Value: &LiteralExpr{Value: 1}},
 		},
+		{
+			"CompoundAssignStmt",
+			&CompoundAssignStmt{Target: &IdentifierExpr{Name: "x"}, Operator: Add, Value: &LiteralExpr{Value: 1}},
+		},
 		{
 			"IfStmt",
 			&IfStmt{
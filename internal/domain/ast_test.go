@@ -346,6 +346,11 @@ func TestBinaryOperatorString(t *testing.T) {
 		{Ge, ">="},
 		{And, "&&"},
 		{Or, "||"},
+		{BitAnd, "&"},
+		{BitOr, "|"},
+		{BitXor, "^"},
+		{Shl, "<<"},
+		{Shr, ">>"},
 	}
 
 	for _, tc := range testCases {
@@ -365,6 +370,7 @@ func TestUnaryOperatorString(t *testing.T) {
 	}{
 		{Neg, "-"},
 		{Not, "!"},
+		{BitNot, "~"},
 	}
 
 	for _, tc := range testCases {
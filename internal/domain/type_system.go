@@ -3,6 +3,7 @@ package domain
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -27,8 +28,33 @@ const (
 	BoolType
 	StringType
 	VoidType
+
+	// Sized integer types
+	Int8Type
+	Int16Type
+	Int32Type
+	Int64Type
+	UInt8Type
+	UInt16Type
+	UInt32Type
+	UInt64Type
+
+	// Single-precision floating point; float is double precision
+	Float32Type
 )
 
+// sizedIntegerNames maps the sized integer kinds to their source names
+var sizedIntegerNames = map[BasicTypeKind]string{
+	Int8Type:   "i8",
+	Int16Type:  "i16",
+	Int32Type:  "i32",
+	Int64Type:  "i64",
+	UInt8Type:  "u8",
+	UInt16Type: "u16",
+	UInt32Type: "u32",
+	UInt64Type: "u64",
+}
+
 func (bt *BasicType) String() string {
 	switch bt.Kind {
 	case IntType:
@@ -41,7 +67,12 @@ func (bt *BasicType) String() string {
 		return "string"
 	case VoidType:
 		return "void"
+	case Float32Type:
+		return "f32"
 	default:
+		if name, ok := sizedIntegerNames[bt.Kind]; ok {
+			return name
+		}
 		return "unknown"
 	}
 }
@@ -69,15 +100,48 @@ func (bt *BasicType) GetSize() int {
 		return 8 // pointer to string data
 	case VoidType:
 		return 0
+	case Int8Type, UInt8Type:
+		return 1
+	case Int16Type, UInt16Type:
+		return 2
+	case Int32Type, UInt32Type, Float32Type:
+		return 4
+	case Int64Type, UInt64Type:
+		return 8
 	default:
 		return 0
 	}
 }
 
+// IsInteger reports whether the type is int or one of the sized integer types
+func (bt *BasicType) IsInteger() bool {
+	if bt.Kind == IntType {
+		return true
+	}
+	_, ok := sizedIntegerNames[bt.Kind]
+	return ok
+}
+
+// IsUnsigned reports whether the type is an unsigned integer type
+func (bt *BasicType) IsUnsigned() bool {
+	switch bt.Kind {
+	case UInt8Type, UInt16Type, UInt32Type, UInt64Type:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFloat reports whether the type is float or f32
+func (bt *BasicType) IsFloat() bool {
+	return bt.Kind == FloatType || bt.Kind == Float32Type
+}
+
 // ArrayType represents array types
 type ArrayType struct {
 	ElementType Type
-	Size        int // -1 for dynamic arrays
+	Size        int        // -1 for dynamic arrays
+	SizeExpr    Expression // Constant size expression, replaced by Size during semantic analysis
 }
 
 func (at *ArrayType) String() string {
@@ -265,6 +329,14 @@ func NewDefaultTypeRegistry() *DefaultTypeRegistry {
 	reg.types["bool"] = reg.builtins[BoolType]
 	reg.types["string"] = reg.builtins[StringType]
 	reg.types["void"] = reg.builtins[VoidType]
+	reg.builtins[Float32Type] = &BasicType{Kind: Float32Type}
+	reg.types["f32"] = reg.builtins[Float32Type]
+
+	// Sized integer types
+	for kind, name := range sizedIntegerNames {
+		reg.builtins[kind] = &BasicType{Kind: kind}
+		reg.types[name] = reg.builtins[kind]
+	}
 
 	return reg
 }
@@ -317,21 +389,68 @@ func (reg *DefaultTypeRegistry) GetBuiltinType(kind BasicTypeKind) Type {
 // Type checking utilities
 func IsNumericType(t Type) bool {
 	if basic, ok := t.(*BasicType); ok {
-		return basic.Kind == IntType || basic.Kind == FloatType
+		return basic.IsInteger() || basic.IsFloat()
 	}
 	return false
 }
 
+// IsIntegerType reports whether t is int or a sized integer type
+func IsIntegerType(t Type) bool {
+	basic, ok := t.(*BasicType)
+	return ok && basic.IsInteger()
+}
+
+// IsUnsignedType reports whether t is an unsigned integer type
+func IsUnsignedType(t Type) bool {
+	basic, ok := t.(*BasicType)
+	return ok && basic.IsUnsigned()
+}
+
+// IntegerFits reports whether value is representable in the integer type t
+func IntegerFits(value int64, t Type) bool {
+	if !IsIntegerType(t) {
+		return false
+	}
+	bits := uint(t.GetSize() * 8)
+	if IsUnsignedType(t) {
+		return value >= 0 && (bits == 64 || value < int64(1)<<bits)
+	}
+	if bits == 64 {
+		return true
+	}
+	return value >= -(int64(1)<<(bits-1)) && value < int64(1)<<(bits-1)
+}
+
+// IsFloatType reports whether t is float or f32
+func IsFloatType(t Type) bool {
+	basic, ok := t.(*BasicType)
+	return ok && basic.IsFloat()
+}
+
+// FloatFits reports whether value is within the finite range of the float type t
+func FloatFits(value float64, t Type) bool {
+	if !IsFloatType(t) {
+		return false
+	}
+	if t.(*BasicType).Kind == Float32Type {
+		return math.Abs(value) <= math.MaxFloat32
+	}
+	return true
+}
+
 func IsComparableType(t Type) bool {
 	if basic, ok := t.(*BasicType); ok {
-		return basic.Kind == IntType || basic.Kind == FloatType || basic.Kind == BoolType || basic.Kind == StringType
+		return IsNumericType(basic) || basic.Kind == BoolType || basic.Kind == StringType
 	}
 	return false
 }
 
 func CanApplyBinaryOperator(op BinaryOperator, left, right Type) bool {
 	switch op {
-	case Add, Sub, Mul, Div, Mod:
+	case Add:
+		// + also concatenates strings
+		return (IsNumericType(left) || left.String() == "string") && left.Equals(right)
+	case Sub, Mul, Div, Mod:
 		return IsNumericType(left) && left.Equals(right)
 	case Eq, Ne:
 		return IsComparableType(left) && left.Equals(right)
@@ -339,11 +458,41 @@ func CanApplyBinaryOperator(op BinaryOperator, left, right Type) bool {
 		return (IsNumericType(left) || left.String() == "string") && left.Equals(right)
 	case And, Or:
 		return left.String() == "bool" && right.String() == "bool"
+	case BitAnd, BitOr, BitXor:
+		return IsIntegerType(left) && left.Equals(right)
+	case Shl, Shr:
+		// The shift count may have any integer type
+		return IsIntegerType(left) && IsIntegerType(right)
 	default:
 		return false
 	}
 }
 
+// CanCast reports whether an explicit conversion from one type to another is
+// allowed. Numeric and bool values convert to each other and to string;
+// any type converts to itself.
+func CanCast(from, to Type) bool {
+	if from.Equals(to) {
+		return true
+	}
+	fromBasic, ok := from.(*BasicType)
+	if !ok {
+		return false
+	}
+	toBasic, ok := to.(*BasicType)
+	if !ok {
+		return false
+	}
+
+	scalar := func(bt *BasicType) bool {
+		return bt.IsInteger() || bt.IsFloat() || bt.Kind == BoolType
+	}
+	if !scalar(fromBasic) {
+		return false
+	}
+	return scalar(toBasic) || toBasic.Kind == StringType
+}
+
 // Helper functions for creating basic types
 func NewIntType() Type {
 	return &BasicType{Kind: IntType}
@@ -371,6 +520,8 @@ func CanApplyUnaryOperator(op UnaryOperator, operand Type) bool {
 		return IsNumericType(operand)
 	case Not:
 		return operand.String() == "bool"
+	case BitNot:
+		return IsIntegerType(operand)
 	default:
 		return false
 	}
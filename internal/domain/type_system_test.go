@@ -497,6 +497,152 @@ func TestIsNumericType(t *testing.T) {
 	}
 }
 
+// TestSizedIntegerTypes tests the sized integer kinds
+func TestSizedIntegerTypes(t *testing.T) {
+	registry := NewDefaultTypeRegistry()
+
+	tests := []struct {
+		name     string
+		size     int
+		unsigned bool
+	}{
+		{"i8", 1, false},
+		{"i16", 2, false},
+		{"i32", 4, false},
+		{"i64", 8, false},
+		{"u8", 1, true},
+		{"u16", 2, true},
+		{"u32", 4, true},
+		{"u64", 8, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, found := registry.GetType(tt.name)
+			if !found {
+				t.Fatalf("Default registry should have %s type", tt.name)
+			}
+			if got := typ.String(); got != tt.name {
+				t.Errorf("String() = %v, want %v", got, tt.name)
+			}
+			if got := typ.GetSize(); got != tt.size {
+				t.Errorf("GetSize() = %v, want %v", got, tt.size)
+			}
+			if !IsIntegerType(typ) || !IsNumericType(typ) {
+				t.Errorf("%s should be an integer type", tt.name)
+			}
+			if got := IsUnsignedType(typ); got != tt.unsigned {
+				t.Errorf("IsUnsignedType() = %v, want %v", got, tt.unsigned)
+			}
+		})
+	}
+
+	if got := (&BasicType{Kind: IntType}).GetSize(); got != 8 {
+		t.Errorf("int size = %v, want 8", got)
+	}
+	if IsIntegerType(&BasicType{Kind: FloatType}) {
+		t.Error("Float should not be an integer type")
+	}
+}
+
+// TestIntegerFits tests constant range checking for integer types
+func TestIntegerFits(t *testing.T) {
+	tests := []struct {
+		value    int64
+		kind     BasicTypeKind
+		expected bool
+	}{
+		{127, Int8Type, true},
+		{128, Int8Type, false},
+		{-128, Int8Type, true},
+		{-129, Int8Type, false},
+		{255, UInt8Type, true},
+		{256, UInt8Type, false},
+		{-1, UInt8Type, false},
+		{-1, UInt64Type, false},
+		{65535, UInt16Type, true},
+		{1 << 31, Int32Type, false},
+		{1 << 40, IntType, true},
+		{1, FloatType, false},
+	}
+
+	for _, tt := range tests {
+		typ := &BasicType{Kind: tt.kind}
+		if got := IntegerFits(tt.value, typ); got != tt.expected {
+			t.Errorf("IntegerFits(%d, %s) = %v, want %v", tt.value, typ, got, tt.expected)
+		}
+	}
+}
+
+// TestFloatTypes tests the double-precision float and single-precision f32 types
+func TestFloatTypes(t *testing.T) {
+	registry := NewDefaultTypeRegistry()
+
+	f32Type, found := registry.GetType("f32")
+	if !found {
+		t.Fatal("Default registry should have f32 type")
+	}
+	if f32Type.String() != "f32" || f32Type.GetSize() != 4 {
+		t.Errorf("Expected f32 of size 4, got %s of size %d", f32Type, f32Type.GetSize())
+	}
+	floatType := registry.GetBuiltinType(FloatType)
+	if floatType.GetSize() != 8 {
+		t.Errorf("float size = %v, want 8", floatType.GetSize())
+	}
+
+	for _, typ := range []Type{floatType, f32Type} {
+		if !IsFloatType(typ) || !IsNumericType(typ) || IsIntegerType(typ) {
+			t.Errorf("%s should be a numeric float type", typ)
+		}
+	}
+	if f32Type.Equals(floatType) {
+		t.Error("f32 and float should be distinct types")
+	}
+
+	if !FloatFits(3.4e38, f32Type) || FloatFits(1e39, f32Type) || FloatFits(-1e39, f32Type) {
+		t.Error("FloatFits should check the f32 range")
+	}
+	if !FloatFits(1e300, floatType) || FloatFits(1.0, NewIntType()) {
+		t.Error("FloatFits should accept any double and reject non-float types")
+	}
+}
+
+// TestCanCast tests which explicit conversions are allowed
+func TestCanCast(t *testing.T) {
+	intType := NewIntType()
+	floatType := NewFloatType()
+	boolType := NewBoolType()
+	stringType := NewStringType()
+	u8Type := &BasicType{Kind: UInt8Type}
+	f32Type := &BasicType{Kind: Float32Type}
+	structType := &StructType{Name: "Point"}
+
+	tests := []struct {
+		from, to Type
+		expected bool
+	}{
+		{intType, floatType, true},
+		{floatType, intType, true},
+		{intType, u8Type, true},
+		{floatType, f32Type, true},
+		{boolType, intType, true},
+		{intType, boolType, true},
+		{intType, stringType, true},
+		{boolType, stringType, true},
+		{stringType, stringType, true},
+		{stringType, intType, false},
+		{intType, NewVoidType(), false},
+		{structType, stringType, false},
+		{intType, structType, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanCast(tt.from, tt.to); got != tt.expected {
+			t.Errorf("CanCast(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.expected)
+		}
+	}
+}
+
 // TestIsComparableType tests comparable type checking
 func TestIsComparableType(t *testing.T) {
 	intType := &BasicType{Kind: IntType}
@@ -554,6 +700,15 @@ func TestCanApplyBinaryOperator(t *testing.T) {
 		t.Error("Should be able to compare strings")
 	}
 
+	// String concatenation
+	if !CanApplyBinaryOperator(Add, stringType, stringType) {
+		t.Error("Should be able to concatenate strings")
+	}
+
+	if CanApplyBinaryOperator(Sub, stringType, stringType) {
+		t.Error("Should not be able to subtract strings")
+	}
+
 	// Invalid combinations
 	if CanApplyBinaryOperator(Add, intType, stringType) {
 		t.Error("Should not be able to add int + string")
@@ -562,6 +717,29 @@ func TestCanApplyBinaryOperator(t *testing.T) {
 	if CanApplyBinaryOperator(Mul, boolType, boolType) {
 		t.Error("Should not be able to multiply bool * bool")
 	}
+
+	// Bitwise operators on integer types
+	u8Type := &BasicType{Kind: UInt8Type}
+	if !CanApplyBinaryOperator(BitXor, u8Type, u8Type) {
+		t.Error("Should be able to apply ^ to u8 types")
+	}
+
+	if CanApplyBinaryOperator(BitAnd, intType, u8Type) {
+		t.Error("Should not be able to apply & to int and u8")
+	}
+
+	if CanApplyBinaryOperator(BitOr, boolType, boolType) {
+		t.Error("Should not be able to apply | to bool types")
+	}
+
+	// Shift counts may have another integer type
+	if !CanApplyBinaryOperator(Shl, intType, u8Type) {
+		t.Error("Should be able to shift int by u8")
+	}
+
+	if CanApplyBinaryOperator(Shr, floatType, intType) {
+		t.Error("Should not be able to shift float")
+	}
 }
 
 // TestCanApplyUnaryOperator tests unary operator compatibility
@@ -597,4 +775,13 @@ func TestCanApplyUnaryOperator(t *testing.T) {
 	if CanApplyUnaryOperator(Neg, stringType) {
 		t.Error("Should not be able to negate string")
 	}
+
+	// Bitwise not on integer types
+	if !CanApplyUnaryOperator(BitNot, intType) {
+		t.Error("Should be able to apply ~ to int")
+	}
+
+	if CanApplyUnaryOperator(BitNot, boolType) {
+		t.Error("Should not be able to apply ~ to bool")
+	}
 }
@@ -101,4 +101,13 @@ type CompilationOptions struct {
 	TargetTriple      string
 	OutputPath        string
 	WarningsAsErrors  bool
+	BoundsChecks      bool // Check array indices against the array length at runtime
+	DivisionChecks    bool // Check integer divisors against zero at runtime
+}
+
+// DefaultBoundsChecks reports whether array bounds checks are enabled by default
+// at the given optimization level. They are on for development builds (-O0, -O1)
+// and must be requested explicitly for optimized builds.
+func DefaultBoundsChecks(optimizationLevel int) bool {
+	return optimizationLevel < 2
 }
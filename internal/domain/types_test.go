@@ -61,6 +61,24 @@ func TestCompilationOptions(t *testing.T) {
 	}
 }
 
+func TestDefaultBoundsChecks(t *testing.T) {
+	testCases := []struct {
+		level    int
+		expected bool
+	}{
+		{0, true},
+		{1, true},
+		{2, false},
+		{3, false},
+	}
+
+	for _, tc := range testCases {
+		if got := DefaultBoundsChecks(tc.level); got != tc.expected {
+			t.Errorf("DefaultBoundsChecks(%d) = %v, expected %v", tc.level, got, tc.expected)
+		}
+	}
+}
+
 func TestIntType(t *testing.T) {
 	intType := NewIntType()
 
@@ -203,6 +203,149 @@ func (module *MockLLVMModule) GetFunction(name string) (interfaces.LLVMFunction,
 	return function, exists
 }
 
+// AddFunction adds a function of the given LLVM function type
+func (module *MockLLVMModule) AddFunction(name string, funcType interfaces.LLVMType) interfaces.LLVMFunction {
+	function := &MockLLVMFunction{
+		name:     name,
+		funcType: &domain.FunctionType{ReturnType: &domain.BasicType{Kind: domain.VoidType}},
+		module:   module,
+		blocks:   make(map[string]*MockLLVMBasicBlock),
+	}
+	if ft, ok := funcType.(*MockLLVMType); ok {
+		function.funcType.ReturnType = ft.domainType
+		for i := range ft.params {
+			function.parameters = append(function.parameters, &MockLLVMValue{
+				name:     fmt.Sprintf("param%d", i),
+				typ:      ft.params[i],
+				function: function,
+			})
+		}
+	}
+
+	module.functions[name] = function
+	return function
+}
+
+// AddGlobal adds a global variable
+func (module *MockLLVMModule) AddGlobal(name string, t interfaces.LLVMType, init interfaces.LLVMValue) interfaces.LLVMValue {
+	global := &MockLLVMValue{name: name, typ: t}
+	module.globals[name] = global
+	return global
+}
+
+// AddStringConstant adds a string constant
+func (module *MockLLVMModule) AddStringConstant(name, value string) interfaces.LLVMValue {
+	return module.AddGlobal(name, module.PointerType(nil), nil)
+}
+
+// CreateBuilder creates a mock builder
+func (module *MockLLVMModule) CreateBuilder() interfaces.LLVMBuilder {
+	return NewMockLLVMBuilder()
+}
+
+// CreateDIBuilder creates a mock debug information builder
+func (module *MockLLVMModule) CreateDIBuilder() interfaces.LLVMDIBuilder {
+	return &MockLLVMDIBuilder{}
+}
+
+// mockIntKinds maps integer widths to the basic type the mock uses for them
+var mockIntKinds = map[int]domain.BasicTypeKind{
+	1:  domain.BoolType,
+	8:  domain.Int8Type,
+	16: domain.Int16Type,
+	32: domain.Int32Type,
+	64: domain.Int64Type,
+}
+
+func (module *MockLLVMModule) IntType(bits int) interfaces.LLVMType {
+	return &MockLLVMType{name: fmt.Sprintf("i%d", bits), domainType: &domain.BasicType{Kind: mockIntKinds[bits]}}
+}
+
+func (module *MockLLVMModule) FloatType() interfaces.LLVMType {
+	return &MockLLVMType{name: "float", domainType: &domain.BasicType{Kind: domain.Float32Type}}
+}
+
+func (module *MockLLVMModule) DoubleType() interfaces.LLVMType {
+	return &MockLLVMType{name: "double", domainType: &domain.BasicType{Kind: domain.FloatType}}
+}
+
+func (module *MockLLVMModule) VoidType() interfaces.LLVMType {
+	return &MockLLVMType{name: "void", domainType: &domain.BasicType{Kind: domain.VoidType}}
+}
+
+func (module *MockLLVMModule) PointerType(elem interfaces.LLVMType) interfaces.LLVMType {
+	return &MockLLVMType{name: "ptr", domainType: &domain.BasicType{Kind: domain.StringType}}
+}
+
+func (module *MockLLVMModule) ArrayType(elem interfaces.LLVMType, length int) interfaces.LLVMType {
+	return &MockLLVMType{name: fmt.Sprintf("[%d x %s]", length, elem.(*MockLLVMType).name)}
+}
+
+func (module *MockLLVMModule) StructType(fields []interfaces.LLVMType) interfaces.LLVMType {
+	return &MockLLVMType{name: "struct", isStruct: true}
+}
+
+func (module *MockLLVMModule) NamedStructType(name string) interfaces.LLVMType {
+	if structType, exists := module.structs[name]; exists {
+		return structType
+	}
+	structType := &MockLLVMType{name: name, isStruct: true}
+	module.structs[name] = structType
+	return structType
+}
+
+func (module *MockLLVMModule) SetStructBody(structType interfaces.LLVMType, fields []interfaces.LLVMType) {
+}
+
+func (module *MockLLVMModule) FunctionType(result interfaces.LLVMType, params []interfaces.LLVMType, variadic bool) interfaces.LLVMType {
+	return &MockLLVMType{name: "func", domainType: result.(*MockLLVMType).domainType, params: params}
+}
+
+func (module *MockLLVMModule) ConstInt(t interfaces.LLVMType, value int64) interfaces.LLVMValue {
+	return &MockLLVMValue{name: fmt.Sprintf("%d", value), typ: t}
+}
+
+func (module *MockLLVMModule) ConstFloat(t interfaces.LLVMType, value float64) interfaces.LLVMValue {
+	return &MockLLVMValue{name: fmt.Sprintf("%g", value), typ: t}
+}
+
+func (module *MockLLVMModule) ConstNull(t interfaces.LLVMType) interfaces.LLVMValue {
+	return &MockLLVMValue{name: "null", typ: t}
+}
+
+func (module *MockLLVMModule) ConstZero(t interfaces.LLVMType) interfaces.LLVMValue {
+	return &MockLLVMValue{name: "zeroinitializer", typ: t}
+}
+
+func (module *MockLLVMModule) ConstAggregate(t interfaces.LLVMType, elems []interfaces.LLVMValue) interfaces.LLVMValue {
+	return &MockLLVMValue{name: "aggregate", typ: t}
+}
+
+func (module *MockLLVMModule) ConstGEP(t interfaces.LLVMType, ptr interfaces.LLVMValue, indices []interfaces.LLVMValue) interfaces.LLVMValue {
+	return &MockLLVMValue{name: ptr.GetName(), typ: module.PointerType(nil)}
+}
+
+func (module *MockLLVMModule) ConstSizeOf(t interfaces.LLVMType) interfaces.LLVMValue {
+	return &MockLLVMValue{name: "sizeof", typ: module.IntType(64)}
+}
+
+// SizeOf returns the size of the source type behind a mock type, or a
+// pointer's size when there is none
+func (module *MockLLVMModule) SizeOf(t interfaces.LLVMType) int {
+	if mockType, ok := t.(*MockLLVMType); ok && mockType.domainType != nil {
+		return mockType.domainType.GetSize()
+	}
+	return 8
+}
+
+// AlignOf returns the mock size of a type, capped at a pointer's size
+func (module *MockLLVMModule) AlignOf(t interfaces.LLVMType) int {
+	if size := module.SizeOf(t); size > 0 && size < 8 {
+		return size
+	}
+	return 8
+}
+
 // Verify verifies the module
 func (module *MockLLVMModule) Verify() error {
 	// Mock verification - check for basic consistency
@@ -386,12 +529,13 @@ type MockLLVMType struct {
 	name       string
 	domainType domain.Type
 	isStruct   bool
+	params     []interfaces.LLVMType
 }
 
 // IsInteger checks if the type is an integer
 func (typ *MockLLVMType) IsInteger() bool {
 	if basic, ok := typ.domainType.(*domain.BasicType); ok {
-		return basic.Kind == domain.IntType
+		return basic.IsInteger()
 	}
 	return false
 }
@@ -399,7 +543,7 @@ func (typ *MockLLVMType) IsInteger() bool {
 // IsFloat checks if the type is a float
 func (typ *MockLLVMType) IsFloat() bool {
 	if basic, ok := typ.domainType.(*domain.BasicType); ok {
-		return basic.Kind == domain.FloatType
+		return basic.IsFloat()
 	}
 	return false
 }
@@ -422,6 +566,7 @@ func (typ *MockLLVMType) IsStruct() bool {
 type MockLLVMBuilder struct {
 	currentBlock interfaces.LLVMBasicBlock
 	instructions []MockInstruction
+	location     interfaces.LLVMMetadata
 }
 
 type MockInstruction struct {
@@ -662,11 +807,159 @@ func (builder *MockLLVMBuilder) CreateGEP(ptr interfaces.LLVMValue, indices []in
 	}
 }
 
+// record appends an instruction and returns its result value
+func (builder *MockLLVMBuilder) record(opcode, result string, typ interfaces.LLVMType, operands ...interfaces.LLVMValue) interfaces.LLVMValue {
+	names := make([]string, len(operands))
+	for i, operand := range operands {
+		names[i] = operand.GetName()
+	}
+	builder.instructions = append(builder.instructions, MockInstruction{
+		opcode:   opcode,
+		operands: names,
+		result:   result,
+	})
+
+	return &MockLLVMValue{name: result, typ: typ}
+}
+
+func (builder *MockLLVMBuilder) CreateTypedLoad(t interfaces.LLVMType, ptr interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return builder.record("load", name, t, ptr)
+}
+
+func (builder *MockLLVMBuilder) CreateBinOp(op interfaces.BinaryOpcode, lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return builder.record(fmt.Sprintf("binop %d", int(op)), name, lhs.GetType(), lhs, rhs)
+}
+
+func (builder *MockLLVMBuilder) CreateNeg(value interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return builder.record("neg", name, value.GetType(), value)
+}
+
+func (builder *MockLLVMBuilder) CreateFNeg(value interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return builder.record("fneg", name, value.GetType(), value)
+}
+
+func (builder *MockLLVMBuilder) CreateNot(value interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return builder.record("not", name, value.GetType(), value)
+}
+
+func (builder *MockLLVMBuilder) CreateCast(op interfaces.CastOpcode, value interfaces.LLVMValue, t interfaces.LLVMType, name string) interfaces.LLVMValue {
+	return builder.record(fmt.Sprintf("cast %d", int(op)), name, t, value)
+}
+
+func (builder *MockLLVMBuilder) CreateUnreachable() interfaces.LLVMValue {
+	builder.record("unreachable", "", nil)
+
+	// Mark current block as terminated
+	if mockBlock, ok := builder.currentBlock.(*MockLLVMBasicBlock); ok {
+		mockBlock.terminated = true
+	}
+
+	return nil
+}
+
+func (builder *MockLLVMBuilder) CreatePhi(t interfaces.LLVMType, values []interfaces.LLVMValue, blocks []interfaces.LLVMBasicBlock, name string) interfaces.LLVMValue {
+	return builder.record("phi", name, t, values...)
+}
+
+func (builder *MockLLVMBuilder) CreateInBoundsGEP(t interfaces.LLVMType, ptr interfaces.LLVMValue, indices []interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return builder.record("getelementptr", name, ptr.GetType(), append([]interfaces.LLVMValue{ptr}, indices...)...)
+}
+
+func (builder *MockLLVMBuilder) CreateExtractValue(aggregate interfaces.LLVMValue, index int, name string) interfaces.LLVMValue {
+	return builder.record(fmt.Sprintf("extractvalue %d", index), name, aggregate.GetType(), aggregate)
+}
+
+func (builder *MockLLVMBuilder) GetInsertBlock() interfaces.LLVMBasicBlock {
+	return builder.currentBlock
+}
+
+func (builder *MockLLVMBuilder) SetCurrentDebugLocation(location interfaces.LLVMMetadata) {
+	builder.location = location
+}
+
 func (builder *MockLLVMBuilder) Dispose() {
 	builder.instructions = builder.instructions[:0]
 	builder.currentBlock = nil
 }
 
+// MockLLVMMetadata is a debug information node recorded by MockLLVMDIBuilder
+type MockLLVMMetadata struct {
+	kind string
+	name string
+}
+
+// MockLLVMDIBuilder implements LLVMDIBuilder, recording the variables it declares
+type MockLLVMDIBuilder struct {
+	declared  []string
+	finalized bool
+}
+
+func (di *MockLLVMDIBuilder) CreateCompileUnit(filename, directory, producer string, optimized bool) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DICompileUnit", name: filename}
+}
+
+func (di *MockLLVMDIBuilder) CreateFile(filename, directory string) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DIFile", name: filename}
+}
+
+func (di *MockLLVMDIBuilder) CreateBasicType(name string, sizeBits int, encoding interfaces.DWARFEncoding) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DIBasicType", name: name}
+}
+
+func (di *MockLLVMDIBuilder) CreatePointerType(pointee interfaces.LLVMMetadata, sizeBits int) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DIDerivedType"}
+}
+
+func (di *MockLLVMDIBuilder) CreateArrayType(element interfaces.LLVMMetadata, count, sizeBits, alignBits int) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DICompositeType"}
+}
+
+func (di *MockLLVMDIBuilder) CreateStructType(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line, sizeBits, alignBits int) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DICompositeType", name: name}
+}
+
+func (di *MockLLVMDIBuilder) CreateMemberType(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line, sizeBits, alignBits, offsetBits int, memberType interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DIDerivedType", name: name}
+}
+
+func (di *MockLLVMDIBuilder) SetStructMembers(structType interfaces.LLVMMetadata, members []interfaces.LLVMMetadata) {
+}
+
+func (di *MockLLVMDIBuilder) CreateSubroutineType(types []interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DISubroutineType"}
+}
+
+func (di *MockLLVMDIBuilder) CreateFunction(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line int, funcType interfaces.LLVMMetadata, optimized bool) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DISubprogram", name: name}
+}
+
+func (di *MockLLVMDIBuilder) SetSubprogram(fn interfaces.LLVMFunction, subprogram interfaces.LLVMMetadata) {
+}
+
+func (di *MockLLVMDIBuilder) CreateLexicalBlock(scope, file interfaces.LLVMMetadata, line, column int) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DILexicalBlock"}
+}
+
+func (di *MockLLVMDIBuilder) CreateAutoVariable(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line int, varType interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DILocalVariable", name: name}
+}
+
+func (di *MockLLVMDIBuilder) CreateParameterVariable(scope interfaces.LLVMMetadata, name string, argNo int, file interfaces.LLVMMetadata, line int, varType interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DILocalVariable", name: name}
+}
+
+func (di *MockLLVMDIBuilder) CreateDebugLocation(line, column int, scope interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return &MockLLVMMetadata{kind: "DILocation", name: fmt.Sprintf("%d:%d", line, column)}
+}
+
+func (di *MockLLVMDIBuilder) InsertDeclareAtEnd(storage interfaces.LLVMValue, variable, location interfaces.LLVMMetadata, block interfaces.LLVMBasicBlock) {
+	di.declared = append(di.declared, variable.(*MockLLVMMetadata).name)
+}
+
+func (di *MockLLVMDIBuilder) Finalize() {
+	di.finalized = true
+}
+
 // RealLLVMIRGenerator implements interfaces.CodeGenerator using the existing codegen.Generator
 type RealLLVMIRGenerator struct {
 	generator     *codegen.Generator
@@ -711,10 +1004,16 @@ func (cg *RealLLVMIRGenerator) SetOutput(output io.Writer) {
 // SetOptions sets code generation options
 func (cg *RealLLVMIRGenerator) SetOptions(options interfaces.CodeGenOptions) {
 	cg.options = options
+	cg.generator.SetBoundsChecks(options.BoundsChecks)
+	cg.generator.SetDivisionChecks(options.DivisionChecks)
+	cg.generator.SetTargetTriple(options.TargetTriple)
+	cg.generator.SetDebugInfo(options.DebugInfo)
+	cg.generator.SetOptimizationLevel(options.OptimizationLevel)
+}
 
-	// Set the target triple in the generator if supported
-	// The codegen.Generator currently uses a fixed target triple
-	// but we could extend it to be configurable in the future
+// SetLLVMBackend sets the LLVM backend the code generator builds modules with
+func (cg *RealLLVMIRGenerator) SetLLVMBackend(backend interfaces.LLVMBackend) {
+	cg.generator.SetLLVMBackend(backend)
 }
 
 // SetErrorReporter sets the error reporter
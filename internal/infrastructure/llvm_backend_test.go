@@ -457,6 +457,24 @@ func TestRealLLVMIRGeneratorMethods(t *testing.T) {
 	}
 }
 
+// TestRealLLVMIRGeneratorBackend tests that the generator builds modules with the backend it is given
+func TestRealLLVMIRGeneratorBackend(t *testing.T) {
+	generator := NewRealLLVMIRGenerator()
+	generator.SetLLVMBackend(NewMockLLVMBackend())
+
+	output := &strings.Builder{}
+	generator.SetOutput(output)
+
+	program := &domain.Program{Declarations: []domain.Declaration{}}
+	if err := generator.Generate(program); err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	if !strings.Contains(output.String(), "; Module: ") {
+		t.Errorf("Expected the mock backend's module in output, got: %s", output.String())
+	}
+}
+
 // TestMockInstructionInterface tests the MockInstruction interface implementation
 func TestMockInstructionInterface(t *testing.T) {
 	instruction := &MockInstruction{}
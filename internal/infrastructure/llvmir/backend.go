@@ -0,0 +1,72 @@
+package llvmir
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// Backend creates in-memory modules. It produces textual IR only; turning
+// the IR into assembly or object code is left to llc.
+type Backend struct {
+	initialized  bool
+	targetTriple string
+	modules      []*Module
+}
+
+var _ interfaces.LLVMBackend = (*Backend)(nil)
+
+// NewBackend creates a new pure-Go IR backend
+func NewBackend() *Backend {
+	return &Backend{}
+}
+
+// Initialize sets the target triple of the modules the backend creates
+func (backend *Backend) Initialize(targetTriple string) error {
+	if backend.initialized {
+		return fmt.Errorf("backend already initialized")
+	}
+	backend.targetTriple = targetTriple
+	backend.initialized = true
+	return nil
+}
+
+// CreateModule creates an empty module for the backend's target
+func (backend *Backend) CreateModule(name string) (interfaces.LLVMModule, error) {
+	if !backend.initialized {
+		return nil, fmt.Errorf("backend not initialized")
+	}
+	module := NewModule(name, backend.targetTriple)
+	backend.modules = append(backend.modules, module)
+	return module, nil
+}
+
+// Optimize runs the passes of an optimization level over the module
+func (backend *Backend) Optimize(module interfaces.LLVMModule, level int) error {
+	m, ok := module.(*Module)
+	if !ok {
+		return fmt.Errorf("invalid module type")
+	}
+	NewPassManager(level).Run(m)
+	return m.Verify()
+}
+
+// EmitObject is not supported without LLVM; compile the printed IR with llc
+func (backend *Backend) EmitObject(module interfaces.LLVMModule, output io.Writer) error {
+	return fmt.Errorf("object emission requires llc; print the module as IR instead")
+}
+
+// EmitAssembly is not supported without LLVM; compile the printed IR with llc
+func (backend *Backend) EmitAssembly(module interfaces.LLVMModule, output io.Writer) error {
+	return fmt.Errorf("assembly emission requires llc; print the module as IR instead")
+}
+
+// Dispose releases all modules created by the backend
+func (backend *Backend) Dispose() {
+	for _, module := range backend.modules {
+		module.Dispose()
+	}
+	backend.modules = nil
+	backend.initialized = false
+}
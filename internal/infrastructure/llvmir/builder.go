@@ -0,0 +1,306 @@
+package llvmir
+
+import (
+	"fmt"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// Builder appends instructions to the end of a basic block
+type Builder struct {
+	block *BasicBlock
+	loc   *MDNode // Debug location attached to new instructions
+}
+
+var _ interfaces.LLVMBuilder = (*Builder)(nil)
+
+var binaryOpcodes = map[interfaces.BinaryOpcode]Opcode{
+	interfaces.BinaryAdd:  OpAdd,
+	interfaces.BinarySub:  OpSub,
+	interfaces.BinaryMul:  OpMul,
+	interfaces.BinarySDiv: OpSDiv,
+	interfaces.BinaryUDiv: OpUDiv,
+	interfaces.BinaryFAdd: OpFAdd,
+	interfaces.BinaryFSub: OpFSub,
+	interfaces.BinaryFMul: OpFMul,
+	interfaces.BinaryFDiv: OpFDiv,
+	interfaces.BinaryXor:  OpXor,
+	interfaces.BinarySRem: OpSRem,
+	interfaces.BinaryURem: OpURem,
+	interfaces.BinaryFRem: OpFRem,
+	interfaces.BinaryAnd:  OpAnd,
+	interfaces.BinaryOr:   OpOr,
+	interfaces.BinaryShl:  OpShl,
+	interfaces.BinaryLShr: OpLShr,
+	interfaces.BinaryAShr: OpAShr,
+}
+
+var castOpcodes = map[interfaces.CastOpcode]Opcode{
+	interfaces.CastTrunc:   OpTrunc,
+	interfaces.CastZExt:    OpZExt,
+	interfaces.CastSExt:    OpSExt,
+	interfaces.CastFPTrunc: OpFPTrunc,
+	interfaces.CastFPExt:   OpFPExt,
+	interfaces.CastFPToUI:  OpFPToUI,
+	interfaces.CastFPToSI:  OpFPToSI,
+	interfaces.CastUIToFP:  OpUIToFP,
+	interfaces.CastSIToFP:  OpSIToFP,
+}
+
+var intPredicates = map[interfaces.IntPredicate]string{
+	interfaces.IntEQ:  "eq",
+	interfaces.IntNE:  "ne",
+	interfaces.IntSLT: "slt",
+	interfaces.IntSLE: "sle",
+	interfaces.IntSGT: "sgt",
+	interfaces.IntSGE: "sge",
+	interfaces.IntULT: "ult",
+	interfaces.IntULE: "ule",
+	interfaces.IntUGT: "ugt",
+	interfaces.IntUGE: "uge",
+}
+
+var floatPredicates = map[interfaces.FloatPredicate]string{
+	interfaces.FloatOEQ: "oeq",
+	interfaces.FloatONE: "one",
+	interfaces.FloatOLT: "olt",
+	interfaces.FloatOLE: "ole",
+	interfaces.FloatOGT: "ogt",
+	interfaces.FloatOGE: "oge",
+	interfaces.FloatUNE: "une",
+}
+
+// NewBuilder creates a builder that is not yet positioned in a block
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// PositionAtEnd positions the builder at the end of a block. A block is
+// appended to its function the first time the builder is positioned at it.
+func (b *Builder) PositionAtEnd(block interfaces.LLVMBasicBlock) {
+	bb, ok := block.(*BasicBlock)
+	if !ok {
+		panic(fmt.Sprintf("llvmir: foreign block %T", block))
+	}
+	if !bb.placed {
+		bb.Parent.Blocks = append(bb.Parent.Blocks, bb)
+		bb.placed = true
+	}
+	b.block = bb
+}
+
+// GetInsertBlock gets the block the builder appends to
+func (b *Builder) GetInsertBlock() interfaces.LLVMBasicBlock {
+	return b.block
+}
+
+// SetCurrentDebugLocation sets the location attached to new instructions
+func (b *Builder) SetCurrentDebugLocation(location interfaces.LLVMMetadata) {
+	b.loc = asNode(location)
+}
+
+// insert appends an instruction to the current block
+func (b *Builder) insert(inst *Instruction) *Instruction {
+	if b.block == nil {
+		panic("llvmir: builder is not positioned in a block")
+	}
+	if inst.Typ == nil {
+		inst.Typ = Void
+	}
+	inst.Parent = b.block
+	if inst.DebugLoc == nil {
+		inst.DebugLoc = b.loc
+	}
+	b.block.Instructions = append(b.block.Instructions, inst)
+	return inst
+}
+
+func (b *Builder) CreateAlloca(t interfaces.LLVMType, name string) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpAlloca, Name: name, Typ: Ptr, Elem: asType(t)})
+}
+
+func (b *Builder) CreateStore(value, ptr interfaces.LLVMValue) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpStore, Operands: []Value{asValue(value), asValue(ptr)}})
+}
+
+// CreateLoad loads from an alloca or global, whose allocated type is known
+func (b *Builder) CreateLoad(ptr interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	var t Type
+	switch p := ptr.(type) {
+	case *Instruction:
+		if p.Op == OpAlloca {
+			t = p.Elem
+		}
+	case *Global:
+		t = p.ValueType
+	}
+	if t == nil {
+		panic("llvmir: CreateLoad needs an alloca or global; use CreateTypedLoad")
+	}
+	return b.CreateTypedLoad(t, ptr, name)
+}
+
+func (b *Builder) CreateTypedLoad(t interfaces.LLVMType, ptr interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpLoad, Name: name, Typ: asType(t), Operands: []Value{asValue(ptr)}})
+}
+
+func (b *Builder) CreateAdd(lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return b.CreateBinOp(interfaces.BinaryAdd, lhs, rhs, name)
+}
+
+func (b *Builder) CreateSub(lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return b.CreateBinOp(interfaces.BinarySub, lhs, rhs, name)
+}
+
+func (b *Builder) CreateMul(lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return b.CreateBinOp(interfaces.BinaryMul, lhs, rhs, name)
+}
+
+func (b *Builder) CreateSDiv(lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return b.CreateBinOp(interfaces.BinarySDiv, lhs, rhs, name)
+}
+
+func (b *Builder) CreateBinOp(op interfaces.BinaryOpcode, lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	left := asValue(lhs)
+	return b.insert(&Instruction{Op: binaryOpcodes[op], Name: name, Typ: left.Type(), Operands: []Value{left, asValue(rhs)}})
+}
+
+// CreateNeg negates an integer by subtracting it from zero
+func (b *Builder) CreateNeg(value interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	operand := asValue(value)
+	zero := &ConstInt{Typ: operand.Type().(*IntType)}
+	return b.insert(&Instruction{Op: OpSub, Name: name, Typ: operand.Type(), Operands: []Value{zero, operand}})
+}
+
+func (b *Builder) CreateFNeg(value interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	operand := asValue(value)
+	return b.insert(&Instruction{Op: OpFNeg, Name: name, Typ: operand.Type(), Operands: []Value{operand}})
+}
+
+// CreateNot complements an integer by xor with all ones, which is true for i1
+func (b *Builder) CreateNot(value interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	operand := asValue(value)
+	return b.insert(&Instruction{Op: OpXor, Name: name, Typ: operand.Type(), Operands: []Value{operand, &ConstInt{Typ: operand.Type().(*IntType), Value: -1}}})
+}
+
+// CreateCast converts value to type t. Integer constants are resized
+// directly instead of emitting an instruction.
+func (b *Builder) CreateCast(op interfaces.CastOpcode, value interfaces.LLVMValue, t interfaces.LLVMType, name string) interfaces.LLVMValue {
+	if c, ok := value.(*ConstInt); ok {
+		if to, ok := t.(*IntType); ok {
+			return &ConstInt{Typ: to, Value: resizeConstant(c, to, op)}
+		}
+	}
+	return b.insert(&Instruction{Op: castOpcodes[op], Name: name, Typ: asType(t), Operands: []Value{asValue(value)}})
+}
+
+// resizeConstant truncates or extends an integer constant to the width of to
+func resizeConstant(c *ConstInt, to *IntType, op interfaces.CastOpcode) int64 {
+	value := uint64(c.Value)
+	if c.Typ.Bits < 64 && op == interfaces.CastZExt {
+		value &= 1<<uint(c.Typ.Bits) - 1
+	}
+	if to.Bits < 64 {
+		shift := uint(64 - to.Bits)
+		return int64(value<<shift) >> shift
+	}
+	return int64(value)
+}
+
+func (b *Builder) CreateICmp(pred interfaces.IntPredicate, lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpICmp, Name: name, Typ: I1, Pred: intPredicates[pred], Operands: []Value{asValue(lhs), asValue(rhs)}})
+}
+
+func (b *Builder) CreateFCmp(pred interfaces.FloatPredicate, lhs, rhs interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpFCmp, Name: name, Typ: I1, Pred: floatPredicates[pred], Operands: []Value{asValue(lhs), asValue(rhs)}})
+}
+
+func (b *Builder) CreateBr(dest interfaces.LLVMBasicBlock) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpBr, Blocks: []*BasicBlock{asBlock(dest)}})
+}
+
+func (b *Builder) CreateCondBr(cond interfaces.LLVMValue, then, else_ interfaces.LLVMBasicBlock) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpBr, Operands: []Value{asValue(cond)}, Blocks: []*BasicBlock{asBlock(then), asBlock(else_)}})
+}
+
+func (b *Builder) CreateRet(value interfaces.LLVMValue) interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpRet, Operands: []Value{asValue(value)}})
+}
+
+func (b *Builder) CreateRetVoid() interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpRet})
+}
+
+func (b *Builder) CreateUnreachable() interfaces.LLVMValue {
+	return b.insert(&Instruction{Op: OpUnreachable})
+}
+
+func (b *Builder) CreatePhi(t interfaces.LLVMType, values []interfaces.LLVMValue, blocks []interfaces.LLVMBasicBlock, name string) interfaces.LLVMValue {
+	incoming := make([]*BasicBlock, len(blocks))
+	for i, block := range blocks {
+		incoming[i] = asBlock(block)
+	}
+	return b.insert(&Instruction{Op: OpPhi, Name: name, Typ: asType(t), Operands: asValues(values), Blocks: incoming})
+}
+
+func (b *Builder) CreateCall(fn interfaces.LLVMFunction, args []interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	callee, ok := fn.(*Function)
+	if !ok {
+		panic(fmt.Sprintf("llvmir: foreign function %T", fn))
+	}
+	inst := &Instruction{Op: OpCall, Typ: callee.Sig.Result, Operands: asValues(args), Callee: callee}
+	if _, void := callee.Sig.Result.(*VoidType); !void {
+		inst.Name = name
+	}
+	return b.insert(inst)
+}
+
+// CreateGEP indexes into an alloca or global, whose allocated type is known
+func (b *Builder) CreateGEP(ptr interfaces.LLVMValue, indices []interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	var t Type
+	switch p := ptr.(type) {
+	case *Instruction:
+		if p.Op == OpAlloca {
+			t = p.Elem
+		}
+	case *Global:
+		t = p.ValueType
+	}
+	if t == nil {
+		panic("llvmir: CreateGEP needs an alloca or global; use CreateInBoundsGEP")
+	}
+	return b.CreateInBoundsGEP(t, ptr, indices, name)
+}
+
+func (b *Builder) CreateInBoundsGEP(t interfaces.LLVMType, ptr interfaces.LLVMValue, indices []interfaces.LLVMValue, name string) interfaces.LLVMValue {
+	operands := append([]Value{asValue(ptr)}, asValues(indices)...)
+	return b.insert(&Instruction{Op: OpGEP, Name: name, Typ: Ptr, Elem: asType(t), Operands: operands})
+}
+
+func (b *Builder) CreateExtractValue(aggregate interfaces.LLVMValue, index int, name string) interfaces.LLVMValue {
+	agg := asValue(aggregate)
+	var t Type
+	switch typ := agg.Type().(type) {
+	case *StructType:
+		t = typ.Fields[index]
+	case *ArrayType:
+		t = typ.Elem
+	default:
+		panic(fmt.Sprintf("llvmir: extractvalue from non-aggregate %s", typ))
+	}
+	return b.insert(&Instruction{Op: OpExtractValue, Name: name, Typ: t, Operands: []Value{agg}, Index: index})
+}
+
+// Dispose detaches the builder from its block
+func (b *Builder) Dispose() {
+	b.block = nil
+}
+
+// asBlock converts an interface block created by this package
+func asBlock(block interfaces.LLVMBasicBlock) *BasicBlock {
+	bb, ok := block.(*BasicBlock)
+	if !ok {
+		panic(fmt.Sprintf("llvmir: foreign block %T", block))
+	}
+	return bb
+}
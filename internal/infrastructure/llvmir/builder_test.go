@@ -0,0 +1,165 @@
+package llvmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// newTestBuilder returns a module and a builder positioned in the entry block of a void function
+func newTestBuilder() (*Module, interfaces.LLVMFunction, interfaces.LLVMBuilder) {
+	m := NewModule("test", "")
+	fn := m.AddFunction("test", m.FunctionType(Void, nil, false))
+	b := m.CreateBuilder()
+	b.PositionAtEnd(fn.CreateBasicBlock("entry"))
+	return m, fn, b
+}
+
+func TestBuilderInstructions(t *testing.T) {
+	m, fn, b := newTestBuilder()
+
+	slot := b.CreateAlloca(I64, "x")
+	b.CreateStore(m.ConstInt(I64, 5), slot)
+	x := b.CreateLoad(slot, "temp_0")
+	sum := b.CreateBinOp(interfaces.BinaryAdd, x, m.ConstInt(I64, 1), "temp_1")
+	cond := b.CreateICmp(interfaces.IntSGT, sum, m.ConstInt(I64, 0), "temp_2")
+	wide := b.CreateCast(interfaces.CastSIToFP, sum, Double, "temp_3")
+	b.CreateFCmp(interfaces.FloatOLT, wide, m.ConstFloat(Double, 0.5), "temp_4")
+	point := &StructType{Fields: []Type{I64, I64}}
+	field := b.CreateInBoundsGEP(point, slot, []interfaces.LLVMValue{m.ConstInt(I32, 0), m.ConstInt(I32, 1)}, "temp_5")
+	b.CreateTypedLoad(I64, field, "temp_6")
+
+	then := fn.CreateBasicBlock("then")
+	done := fn.CreateBasicBlock("done")
+	b.CreateCondBr(cond, then, done)
+	b.PositionAtEnd(then)
+	b.CreateBr(done)
+	b.PositionAtEnd(done)
+	b.CreatePhi(I1, []interfaces.LLVMValue{m.ConstInt(I1, 1), cond}, []interfaces.LLVMBasicBlock{then, fn.(*Function).Blocks[0]}, "temp_7")
+	b.CreateRetVoid()
+
+	output := printModule(m)
+	expected := []string{
+		"%x = alloca i64, align 8",
+		"store i64 5, ptr %x, align 8",
+		"%temp_0 = load i64, ptr %x, align 8",
+		"%temp_1 = add i64 %temp_0, 1",
+		"%temp_2 = icmp sgt i64 %temp_1, 0",
+		"%temp_3 = sitofp i64 %temp_1 to double",
+		"%temp_4 = fcmp olt double %temp_3, 0x3FE0000000000000",
+		"%temp_5 = getelementptr inbounds { i64, i64 }, ptr %x, i32 0, i32 1",
+		"br i1 %temp_2, label %then, label %done",
+		"%temp_7 = phi i1 [ true, %then ], [ %temp_2, %entry ]",
+		"ret void",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestBuilderFoldsConstantCasts(t *testing.T) {
+	m, _, b := newTestBuilder()
+
+	tests := []struct {
+		op       interfaces.CastOpcode
+		value    interfaces.LLVMValue
+		to       Type
+		expected string
+	}{
+		{interfaces.CastSExt, m.ConstInt(I8, -1), I64, "-1"},
+		{interfaces.CastZExt, m.ConstInt(I8, -1), I64, "255"},
+		{interfaces.CastTrunc, m.ConstInt(I64, 300), I8, "44"},
+		{interfaces.CastZExt, m.ConstInt(I1, 1), I32, "1"},
+	}
+
+	for _, test := range tests {
+		result, ok := b.CreateCast(test.op, test.value, test.to, "temp").(*ConstInt)
+		if !ok {
+			t.Fatalf("Expected cast of a constant to fold")
+		}
+		if result.Ident() != test.expected {
+			t.Errorf("Cast of %s to %s = %s, expected %s", test.value.(Value).Ident(), test.to, result.Ident(), test.expected)
+		}
+	}
+	if len(b.GetInsertBlock().(*BasicBlock).Instructions) != 0 {
+		t.Error("Folded casts should not emit instructions")
+	}
+}
+
+func TestBuilderUnary(t *testing.T) {
+	m, _, b := newTestBuilder()
+
+	slot := b.CreateAlloca(I64, "x")
+	x := b.CreateLoad(slot, "temp_0")
+	b.CreateNeg(x, "temp_1")
+	b.CreateFNeg(b.CreateCast(interfaces.CastSIToFP, x, Double, "temp_2"), "temp_3")
+	b.CreateNot(b.CreateICmp(interfaces.IntSGT, x, m.ConstInt(I64, 0), "temp_4"), "temp_5")
+	b.CreateNot(x, "temp_6")
+	b.CreateRetVoid()
+
+	output := printModule(m)
+	expected := []string{
+		"%temp_1 = sub i64 0, %temp_0",
+		"%temp_3 = fneg double %temp_2",
+		"%temp_5 = xor i1 %temp_4, true",
+		"%temp_6 = xor i64 %temp_0, -1",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+func TestBuilderCalls(t *testing.T) {
+	m, _, b := newTestBuilder()
+
+	str := m.PointerType(I8)
+	printf := m.AddFunction("printf", m.FunctionType(I32, []interfaces.LLVMType{str}, true))
+	printInt := m.AddFunction("sl_print_int", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+	format := m.AddStringConstant(".str.0", "%lld\n")
+	formatPtr := m.ConstGEP(&ArrayType{Len: 6, Elem: I8}, format, []interfaces.LLVMValue{m.ConstInt(I64, 0), m.ConstInt(I64, 0)})
+
+	b.CreateCall(printf, []interfaces.LLVMValue{formatPtr, m.ConstInt(I64, 7)}, "temp_0")
+	b.CreateCall(printInt, []interfaces.LLVMValue{m.ConstInt(I64, 7)}, "ignored")
+	b.CreateRetVoid()
+
+	output := printModule(m)
+	expected := []string{
+		"%temp_0 = call i32 (ptr, ...) @printf(ptr getelementptr inbounds ([6 x i8], ptr @.str.0, i64 0, i64 0), i64 7)",
+		"  call void @sl_print_int(i64 7)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if strings.Contains(output, "%ignored") {
+		t.Errorf("Void calls should not be named, got: %s", output)
+	}
+}
+
+func TestBuilderExtractValue(t *testing.T) {
+	m, _, b := newTestBuilder()
+
+	point := m.NamedStructType("struct.Point")
+	m.SetStructBody(point, []interfaces.LLVMType{I64, Double})
+	getPoint := m.AddFunction("get_point", m.FunctionType(point, nil, false))
+
+	value := b.CreateCall(getPoint, nil, "temp_0")
+	y := b.CreateExtractValue(value, 1, "temp_1")
+	if y.GetType() != Double {
+		t.Errorf("Expected the field type double, got %s", y.GetType())
+	}
+	b.CreateRetVoid()
+
+	if output := printModule(m); !strings.Contains(output, "%temp_1 = extractvalue %struct.Point %temp_0, 1") {
+		t.Errorf("Expected extractvalue of the second field, got: %s", output)
+	}
+}
@@ -0,0 +1,263 @@
+package llvmir
+
+import (
+	"fmt"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// DWARFVersion is the DWARF version requested through the module flags
+const DWARFVersion = 4
+
+// DIBuilder builds DWARF debug information as metadata nodes of a module
+type DIBuilder struct {
+	module    *Module
+	unit      *MDNode
+	declare   *Function
+	files     map[string]*MDNode
+	locations map[string]*MDNode
+}
+
+var _ interfaces.LLVMDIBuilder = (*DIBuilder)(nil)
+
+var dwarfEncodings = map[interfaces.DWARFEncoding]string{
+	interfaces.DWARFBoolean:    "DW_ATE_boolean",
+	interfaces.DWARFFloat:      "DW_ATE_float",
+	interfaces.DWARFSigned:     "DW_ATE_signed",
+	interfaces.DWARFSignedChar: "DW_ATE_signed_char",
+	interfaces.DWARFUnsigned:   "DW_ATE_unsigned",
+}
+
+// CreateDIBuilder creates a debug information builder for the module
+func (m *Module) CreateDIBuilder() interfaces.LLVMDIBuilder {
+	return &DIBuilder{module: m, files: make(map[string]*MDNode), locations: make(map[string]*MDNode)}
+}
+
+// node adds a debug information node to the module
+func (d *DIBuilder) node(kind string, distinct bool, fields ...MDField) *MDNode {
+	return d.module.addMetadata(&MDNode{Kind: kind, Distinct: distinct, Fields: fields})
+}
+
+// CreateCompileUnit creates the compile unit. Only one is created per module.
+func (d *DIBuilder) CreateCompileUnit(filename, directory, producer string, optimized bool) interfaces.LLVMMetadata {
+	if d.unit != nil {
+		return d.unit
+	}
+	d.unit = d.node("DICompileUnit", true,
+		MDField{"language", MDRaw("DW_LANG_C99")},
+		MDField{"file", d.CreateFile(filename, directory)},
+		MDField{"producer", producer},
+		MDField{"isOptimized", optimized},
+		MDField{"runtimeVersion", 0},
+		MDField{"emissionKind", MDRaw("FullDebug")},
+	)
+	return d.unit
+}
+
+// CreateFile creates a file descriptor, reusing one for the same path
+func (d *DIBuilder) CreateFile(filename, directory string) interfaces.LLVMMetadata {
+	key := directory + "/" + filename
+	if file, ok := d.files[key]; ok {
+		return file
+	}
+	file := d.node("DIFile", false,
+		MDField{"filename", filename},
+		MDField{"directory", directory},
+	)
+	d.files[key] = file
+	return file
+}
+
+func (d *DIBuilder) CreateBasicType(name string, sizeBits int, encoding interfaces.DWARFEncoding) interfaces.LLVMMetadata {
+	ate, ok := dwarfEncodings[encoding]
+	if !ok {
+		ate = fmt.Sprintf("%d", encoding)
+	}
+	return d.node("DIBasicType", false,
+		MDField{"name", name},
+		MDField{"size", sizeBits},
+		MDField{"encoding", MDRaw(ate)},
+	)
+}
+
+func (d *DIBuilder) CreatePointerType(pointee interfaces.LLVMMetadata, sizeBits int) interfaces.LLVMMetadata {
+	return d.node("DIDerivedType", false,
+		MDField{"tag", MDRaw("DW_TAG_pointer_type")},
+		MDField{"baseType", asNode(pointee)},
+		MDField{"size", sizeBits},
+	)
+}
+
+func (d *DIBuilder) CreateArrayType(element interfaces.LLVMMetadata, count, sizeBits, alignBits int) interfaces.LLVMMetadata {
+	subrange := d.node("DISubrange", false, MDField{"count", count})
+	return d.node("DICompositeType", false,
+		MDField{"tag", MDRaw("DW_TAG_array_type")},
+		MDField{"baseType", asNode(element)},
+		MDField{"size", sizeBits},
+		MDField{"align", alignBits},
+		MDField{"elements", d.module.addTuple(subrange)},
+	)
+}
+
+func (d *DIBuilder) CreateStructType(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line, sizeBits, alignBits int) interfaces.LLVMMetadata {
+	return d.node("DICompositeType", true,
+		MDField{"tag", MDRaw("DW_TAG_structure_type")},
+		MDField{"name", name},
+		MDField{"scope", asNode(scope)},
+		MDField{"file", asNode(file)},
+		MDField{"line", line},
+		MDField{"size", sizeBits},
+		MDField{"align", alignBits},
+		MDField{"elements", d.module.addTuple()},
+	)
+}
+
+func (d *DIBuilder) CreateMemberType(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line, sizeBits, alignBits, offsetBits int, memberType interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return d.node("DIDerivedType", false,
+		MDField{"tag", MDRaw("DW_TAG_member")},
+		MDField{"name", name},
+		MDField{"scope", asNode(scope)},
+		MDField{"file", asNode(file)},
+		MDField{"line", line},
+		MDField{"baseType", asNode(memberType)},
+		MDField{"size", sizeBits},
+		MDField{"align", alignBits},
+		MDField{"offset", offsetBits},
+	)
+}
+
+// SetStructMembers replaces the element tuple of a struct type, which lets
+// members refer back to a struct created before them
+func (d *DIBuilder) SetStructMembers(structType interfaces.LLVMMetadata, members []interfaces.LLVMMetadata) {
+	elements, ok := asNode(structType).Field("elements").(*MDNode)
+	if !ok {
+		panic("llvmir: struct type has no elements")
+	}
+	elements.Elements = make([]interface{}, len(members))
+	for i, member := range members {
+		elements.Elements[i] = asNode(member)
+	}
+}
+
+func (d *DIBuilder) CreateSubroutineType(types []interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	elements := make([]interface{}, len(types))
+	for i, typ := range types {
+		elements[i] = asNode(typ)
+	}
+	return d.node("DISubroutineType", false, MDField{"types", d.module.addTuple(elements...)})
+}
+
+func (d *DIBuilder) CreateFunction(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line int, funcType interfaces.LLVMMetadata, optimized bool) interfaces.LLVMMetadata {
+	spFlags := "DISPFlagDefinition"
+	if optimized {
+		spFlags += " | DISPFlagOptimized"
+	}
+	return d.node("DISubprogram", true,
+		MDField{"name", name},
+		MDField{"scope", asNode(scope)},
+		MDField{"file", asNode(file)},
+		MDField{"line", line},
+		MDField{"type", asNode(funcType)},
+		MDField{"scopeLine", line},
+		MDField{"flags", MDRaw("DIFlagPrototyped")},
+		MDField{"spFlags", MDRaw(spFlags)},
+		MDField{"unit", d.unit},
+	)
+}
+
+func (d *DIBuilder) SetSubprogram(fn interfaces.LLVMFunction, subprogram interfaces.LLVMMetadata) {
+	fn.(*Function).Subprogram = asNode(subprogram)
+}
+
+func (d *DIBuilder) CreateLexicalBlock(scope, file interfaces.LLVMMetadata, line, column int) interfaces.LLVMMetadata {
+	return d.node("DILexicalBlock", true,
+		MDField{"scope", asNode(scope)},
+		MDField{"file", asNode(file)},
+		MDField{"line", line},
+		MDField{"column", column},
+	)
+}
+
+func (d *DIBuilder) CreateAutoVariable(scope interfaces.LLVMMetadata, name string, file interfaces.LLVMMetadata, line int, varType interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return d.node("DILocalVariable", false,
+		MDField{"name", name},
+		MDField{"scope", asNode(scope)},
+		MDField{"file", asNode(file)},
+		MDField{"line", line},
+		MDField{"type", asNode(varType)},
+	)
+}
+
+func (d *DIBuilder) CreateParameterVariable(scope interfaces.LLVMMetadata, name string, argNo int, file interfaces.LLVMMetadata, line int, varType interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	return d.node("DILocalVariable", false,
+		MDField{"name", name},
+		MDField{"arg", argNo},
+		MDField{"scope", asNode(scope)},
+		MDField{"file", asNode(file)},
+		MDField{"line", line},
+		MDField{"type", asNode(varType)},
+	)
+}
+
+// CreateDebugLocation creates a location, reusing an identical one
+func (d *DIBuilder) CreateDebugLocation(line, column int, scope interfaces.LLVMMetadata) interfaces.LLVMMetadata {
+	node := asNode(scope)
+	key := fmt.Sprintf("%d:%d:%d", line, column, node.ID)
+	if location, ok := d.locations[key]; ok {
+		return location
+	}
+	location := d.node("DILocation", false,
+		MDField{"line", line},
+		MDField{"column", column},
+		MDField{"scope", node},
+	)
+	d.locations[key] = location
+	return location
+}
+
+// InsertDeclareAtEnd appends a call to llvm.dbg.declare, declaring the
+// intrinsic on first use
+func (d *DIBuilder) InsertDeclareAtEnd(storage interfaces.LLVMValue, variable, location interfaces.LLVMMetadata, block interfaces.LLVMBasicBlock) {
+	if d.declare == nil {
+		d.declare = d.module.AddFunction("llvm.dbg.declare", &FunctionType{Result: Void, Params: []Type{MD, MD, MD}}).(*Function)
+	}
+	bb := block.(*BasicBlock)
+	call := &Instruction{
+		Op:  OpCall,
+		Typ: Void,
+		Operands: []Value{
+			&MetadataValue{Value: asValue(storage)},
+			&MetadataValue{Node: asNode(variable)},
+			&MetadataValue{Raw: "!DIExpression()"},
+		},
+		Callee:   d.declare,
+		DebugLoc: asNode(location),
+		Parent:   bb,
+	}
+	bb.Instructions = append(bb.Instructions, call)
+}
+
+// Finalize lists the compile unit and adds the module flags LLVM requires
+// of modules with debug information
+func (d *DIBuilder) Finalize() {
+	if d.unit == nil {
+		return
+	}
+	d.module.addNamedMetadata("llvm.dbg.cu", d.unit)
+	d.module.addNamedMetadata("llvm.module.flags",
+		d.module.addTuple(MDRaw("i32 7"), MDRaw(`!"Dwarf Version"`), MDRaw(fmt.Sprintf("i32 %d", DWARFVersion))),
+		d.module.addTuple(MDRaw("i32 2"), MDRaw(`!"Debug Info Version"`), MDRaw("i32 3")),
+	)
+}
+
+// asNode converts metadata created by this package; nil stays nil
+func asNode(md interfaces.LLVMMetadata) *MDNode {
+	if md == nil {
+		return nil
+	}
+	node, ok := md.(*MDNode)
+	if !ok {
+		panic(fmt.Sprintf("llvmir: foreign metadata %T", md))
+	}
+	return node
+}
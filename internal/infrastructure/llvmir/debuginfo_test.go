@@ -0,0 +1,100 @@
+package llvmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+func TestDIBuilder(t *testing.T) {
+	m, fn, b := newTestBuilder()
+	di := m.CreateDIBuilder()
+
+	unit := di.CreateCompileUnit("test.sl", "/src", "StaticLang", false)
+	file := di.CreateFile("test.sl", "/src")
+	if di.CreateCompileUnit("test.sl", "/src", "StaticLang", false) != unit {
+		t.Error("Expected a single compile unit per module")
+	}
+
+	i64 := di.CreateBasicType("int", 64, interfaces.DWARFSigned)
+	point := di.CreateStructType(file, "Point", file, 1, 128, 64)
+	di.SetStructMembers(point, []interfaces.LLVMMetadata{
+		di.CreateMemberType(point, "x", file, 2, 64, 64, 0, i64),
+		di.CreateMemberType(point, "y", file, 3, 64, 64, 64, i64),
+	})
+	subprogram := di.CreateFunction(file, "test", file, 5, di.CreateSubroutineType([]interfaces.LLVMMetadata{nil}), false)
+	di.SetSubprogram(fn, subprogram)
+
+	location := di.CreateDebugLocation(6, 5, subprogram)
+	if di.CreateDebugLocation(6, 5, subprogram) != location {
+		t.Error("Expected identical locations to be shared")
+	}
+	b.SetCurrentDebugLocation(location)
+	slot := b.CreateAlloca(I64, "x")
+	di.InsertDeclareAtEnd(slot, di.CreateAutoVariable(subprogram, "x", file, 6, i64), location, b.GetInsertBlock())
+	b.SetCurrentDebugLocation(nil)
+	b.CreateRetVoid()
+	di.Finalize()
+
+	output := printModule(m)
+	expected := []string{
+		"declare void @llvm.dbg.declare(metadata, metadata, metadata)",
+		"define void @test() !dbg !",
+		"%x = alloca i64, align 8, !dbg !",
+		"call void @llvm.dbg.declare(metadata ptr %x, metadata !",
+		"metadata !DIExpression()), !dbg !",
+		"  ret void\n",
+		"!llvm.dbg.cu = !{!1}",
+		"!llvm.module.flags = !{",
+		`!0 = !DIFile(filename: "test.sl", directory: "/src")`,
+		`!1 = distinct !DICompileUnit(language: DW_LANG_C99, file: !0, producer: "StaticLang", isOptimized: false, runtimeVersion: 0, emissionKind: FullDebug)`,
+		`!2 = !DIBasicType(name: "int", size: 64, encoding: DW_ATE_signed)`,
+		`distinct !DICompositeType(tag: DW_TAG_structure_type, name: "Point", scope: !0, file: !0, line: 1, size: 128, align: 64, elements: !3)`,
+		`!3 = !{!5, !6}`,
+		`!DIDerivedType(tag: DW_TAG_member, name: "y", scope: !4, file: !0, line: 3, baseType: !2, size: 64, align: 64, offset: 64)`,
+		"!DISubroutineType(types: !7)",
+		"!7 = !{null}",
+		`distinct !DISubprogram(name: "test", scope: !0, file: !0, line: 5, type: !8, scopeLine: 5, flags: DIFlagPrototyped, spFlags: DISPFlagDefinition, unit: !1)`,
+		"!DILocation(line: 6, column: 5, scope: !9)",
+		`!DILocalVariable(name: "x", scope: !9, file: !0, line: 6, type: !2)`,
+		`!{i32 7, !"Dwarf Version", i32 4}`,
+		`!{i32 2, !"Debug Info Version", i32 3}`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestDIBuilderTypes(t *testing.T) {
+	m := NewModule("test", "")
+	di := m.CreateDIBuilder()
+
+	char := di.CreateBasicType("char", 8, interfaces.DWARFSignedChar)
+	flag := di.CreateBasicType("bool", 8, interfaces.DWARFBoolean)
+	di.CreatePointerType(char, 64)
+	di.CreateArrayType(flag, 4, 32, 8)
+
+	output := printModule(m)
+	expected := []string{
+		`!0 = !DIBasicType(name: "char", size: 8, encoding: DW_ATE_signed_char)`,
+		`!1 = !DIBasicType(name: "bool", size: 8, encoding: DW_ATE_boolean)`,
+		"!2 = !DIDerivedType(tag: DW_TAG_pointer_type, baseType: !0, size: 64)",
+		"!3 = !DISubrange(count: 4)",
+		"!4 = !{!3}",
+		"!5 = !DICompositeType(tag: DW_TAG_array_type, baseType: !1, size: 32, align: 8, elements: !4)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if strings.Contains(output, "!llvm.dbg.cu") {
+		t.Errorf("Expected no compile unit list without a compile unit, got: %s", output)
+	}
+}
@@ -0,0 +1,221 @@
+package llvmir
+
+import "fmt"
+
+// inliner replaces calls to small functions with a copy of their body
+type inliner struct {
+	threshold int // Largest callee inlined, in instructions
+	count     int // Inlined calls so far, used to keep copied names unique
+}
+
+func newInliner(threshold int) *inliner {
+	return &inliner{threshold: threshold}
+}
+
+// run inlines the first eligible call of fn. The pass manager repeats it, so
+// calls exposed by inlining are considered in later iterations.
+func (in *inliner) run(fn *Function) bool {
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			if inst.Op == OpCall && in.canInline(fn, inst.Callee) {
+				in.inline(fn, inst)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// canInline reports whether callee is small, defined, returns and is not
+// recursive through caller
+func (in *inliner) canInline(caller, callee *Function) bool {
+	if len(callee.Blocks) == 0 || callee == caller || callee.Name == "main" || callee.Sig.Variadic {
+		return false
+	}
+
+	size := 0
+	returns := false
+	for _, block := range callee.Blocks {
+		for _, inst := range block.Instructions {
+			if inst.Op == OpCall && (inst.Callee == callee || inst.Callee == caller) {
+				return false
+			}
+			if inst.Op == OpRet {
+				returns = true
+			}
+			if !isDebugIntrinsic(inst) {
+				size++
+			}
+		}
+	}
+	return returns && size <= in.threshold
+}
+
+// inline replaces a call with a copy of the callee. The block holding the
+// call is split after it; the copied returns branch to the second half, where
+// a phi collects the returned value if there are several returns.
+func (in *inliner) inline(fn *Function, call *Instruction) {
+	in.count++
+	suffix := fmt.Sprintf(".i%d", in.count)
+	callee := call.Callee
+	block := call.Parent
+
+	// Split the block after the call
+	index := 0
+	for i, inst := range block.Instructions {
+		if inst == call {
+			index = i
+			break
+		}
+	}
+	cont := &BasicBlock{Name: "inline.cont" + suffix, Parent: fn, placed: true}
+	cont.Instructions = append(cont.Instructions, block.Instructions[index+1:]...)
+	for _, inst := range cont.Instructions {
+		inst.Parent = cont
+	}
+	block.Instructions = block.Instructions[:index:index]
+	for _, succ := range successors(cont) {
+		replaceIncoming(succ, block, cont)
+	}
+
+	// Copy the callee, mapping its parameters to the call arguments
+	values := make(map[Value]Value)
+	for i, param := range callee.Params {
+		values[param] = call.Operands[i]
+	}
+	blocks := make(map[*BasicBlock]*BasicBlock)
+	var copies []*BasicBlock
+	for _, b := range callee.Blocks {
+		copied := &BasicBlock{Name: b.Name + suffix, Parent: fn, placed: true}
+		blocks[b] = copied
+		copies = append(copies, copied)
+	}
+	locations := make(map[*MDNode]*MDNode)
+	for _, b := range callee.Blocks {
+		copied := blocks[b]
+		for _, inst := range b.Instructions {
+			// Debug intrinsics need a location, which a call without one cannot give them
+			if isDebugIntrinsic(inst) && call.DebugLoc == nil {
+				continue
+			}
+			clone := *inst
+			if clone.Name != "" {
+				clone.Name += suffix
+			}
+			clone.Parent = copied
+			clone.DebugLoc = in.inlinedLocation(fn.Module, inst.DebugLoc, call.DebugLoc, locations)
+			copied.Instructions = append(copied.Instructions, &clone)
+			values[inst] = &clone
+		}
+	}
+
+	mapValue := func(v Value) Value {
+		if md, ok := v.(*MetadataValue); ok && md.Value != nil {
+			if mapped, ok := values[md.Value]; ok {
+				return &MetadataValue{Value: mapped}
+			}
+			return md
+		}
+		if mapped, ok := values[v]; ok {
+			return mapped
+		}
+		return v
+	}
+	var returned []Value
+	var returnBlocks []*BasicBlock
+	for _, copied := range copies {
+		for _, inst := range copied.Instructions {
+			operands := make([]Value, len(inst.Operands))
+			for i, op := range inst.Operands {
+				operands[i] = mapValue(op)
+			}
+			inst.Operands = operands
+			targets := make([]*BasicBlock, len(inst.Blocks))
+			for i, target := range inst.Blocks {
+				targets[i] = blocks[target]
+			}
+			inst.Blocks = targets
+
+			// Returns continue after the call
+			if inst.Op == OpRet {
+				if len(inst.Operands) > 0 {
+					returned = append(returned, inst.Operands[0])
+					returnBlocks = append(returnBlocks, copied)
+				}
+				inst.Op = OpBr
+				inst.Operands = nil
+				inst.Blocks = []*BasicBlock{cont}
+			}
+		}
+	}
+
+	// Stack slots of the callee are allocated once in the entry block
+	entry := fn.Blocks[0]
+	var allocas []*Instruction
+	body := copies[0].Instructions[:0]
+	for _, inst := range copies[0].Instructions {
+		if inst.Op == OpAlloca {
+			inst.Parent = entry
+			allocas = append(allocas, inst)
+		} else {
+			body = append(body, inst)
+		}
+	}
+	copies[0].Instructions = body
+	if entry == block {
+		block.Instructions = append(allocas, block.Instructions...)
+	} else {
+		entry.Instructions = append(allocas, entry.Instructions...)
+	}
+
+	block.Instructions = append(block.Instructions, &Instruction{
+		Op:       OpBr,
+		Typ:      Void,
+		Blocks:   []*BasicBlock{copies[0]},
+		DebugLoc: call.DebugLoc,
+		Parent:   block,
+	})
+
+	// Lay the copied blocks out between the two halves of the split block
+	var laidOut []*BasicBlock
+	for _, b := range fn.Blocks {
+		laidOut = append(laidOut, b)
+		if b == block {
+			laidOut = append(laidOut, copies...)
+			laidOut = append(laidOut, cont)
+		}
+	}
+	fn.Blocks = laidOut
+
+	// The call's result is the returned value
+	switch {
+	case len(returned) == 1:
+		replaceUses(fn, call, returned[0])
+	case len(returned) > 1:
+		phi := &Instruction{Op: OpPhi, Name: call.Name, Typ: call.Typ, Operands: returned, Blocks: returnBlocks, Parent: cont}
+		cont.Instructions = append([]*Instruction{phi}, cont.Instructions...)
+		replaceUses(fn, call, phi)
+	}
+}
+
+// inlinedLocation returns the location of a copied instruction, marked as
+// inlined at the call. Locations already inlined into the callee are chained
+// to the call.
+func (in *inliner) inlinedLocation(m *Module, location, callLocation *MDNode, cache map[*MDNode]*MDNode) *MDNode {
+	if location == nil || callLocation == nil {
+		return callLocation
+	}
+	if inlined, ok := cache[location]; ok {
+		return inlined
+	}
+
+	inlinedAt := callLocation
+	if outer, ok := location.Field("inlinedAt").(*MDNode); ok && outer != nil {
+		inlinedAt = in.inlinedLocation(m, outer, callLocation, cache)
+	}
+	fields := append([]MDField(nil), location.Fields...)
+	inlined := m.addMetadata(&MDNode{Kind: location.Kind, Fields: fields})
+	inlined.SetField("inlinedAt", inlinedAt)
+	cache[location] = inlined
+	return inlined
+}
@@ -0,0 +1,176 @@
+package llvmir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// Opcode is the mnemonic of an instruction
+type Opcode string
+
+// Instruction opcodes
+const (
+	OpAlloca       Opcode = "alloca"
+	OpLoad         Opcode = "load"
+	OpStore        Opcode = "store"
+	OpGEP          Opcode = "getelementptr"
+	OpAdd          Opcode = "add"
+	OpSub          Opcode = "sub"
+	OpMul          Opcode = "mul"
+	OpSDiv         Opcode = "sdiv"
+	OpUDiv         Opcode = "udiv"
+	OpSRem         Opcode = "srem"
+	OpURem         Opcode = "urem"
+	OpFAdd         Opcode = "fadd"
+	OpFSub         Opcode = "fsub"
+	OpFMul         Opcode = "fmul"
+	OpFDiv         Opcode = "fdiv"
+	OpFRem         Opcode = "frem"
+	OpXor          Opcode = "xor"
+	OpAnd          Opcode = "and"
+	OpOr           Opcode = "or"
+	OpShl          Opcode = "shl"
+	OpLShr         Opcode = "lshr"
+	OpAShr         Opcode = "ashr"
+	OpFNeg         Opcode = "fneg"
+	OpICmp         Opcode = "icmp"
+	OpFCmp         Opcode = "fcmp"
+	OpTrunc        Opcode = "trunc"
+	OpZExt         Opcode = "zext"
+	OpSExt         Opcode = "sext"
+	OpFPTrunc      Opcode = "fptrunc"
+	OpFPExt        Opcode = "fpext"
+	OpFPToUI       Opcode = "fptoui"
+	OpFPToSI       Opcode = "fptosi"
+	OpUIToFP       Opcode = "uitofp"
+	OpSIToFP       Opcode = "sitofp"
+	OpPhi          Opcode = "phi"
+	OpCall         Opcode = "call"
+	OpExtractValue Opcode = "extractvalue"
+	OpBr           Opcode = "br"
+	OpRet          Opcode = "ret"
+	OpUnreachable  Opcode = "unreachable"
+)
+
+// Instruction is a single IR instruction. Operands hold the value operands in
+// the order they are printed; the remaining fields are used by the opcodes
+// noted on them.
+type Instruction struct {
+	Op       Opcode
+	Name     string
+	Typ      Type // Result type, Void for instructions without a result
+	Operands []Value
+	Pred     string        // icmp, fcmp: comparison predicate
+	Elem     Type          // alloca: allocated type; getelementptr: source element type
+	Blocks   []*BasicBlock // br: targets; phi: incoming blocks
+	Index    int           // extractvalue: member index
+	Callee   *Function     // call: called function
+	DebugLoc *MDNode       // Source location, if any
+	Parent   *BasicBlock
+}
+
+func (inst *Instruction) GetType() interfaces.LLVMType { return inst.Typ }
+func (inst *Instruction) SetName(name string)          { inst.Name = name }
+func (inst *Instruction) GetName() string              { return inst.Name }
+func (inst *Instruction) Type() Type                   { return inst.Typ }
+func (inst *Instruction) Ident() string                { return "%" + inst.Name }
+
+// IsTerminator reports whether the instruction ends a basic block
+func (inst *Instruction) IsTerminator() bool {
+	switch inst.Op {
+	case OpBr, OpRet, OpUnreachable:
+		return true
+	}
+	return false
+}
+
+// String returns the instruction as written in textual IR
+func (inst *Instruction) String() string {
+	body := inst.body()
+	if inst.DebugLoc != nil {
+		body += ", !dbg " + inst.DebugLoc.Ref()
+	}
+	if _, void := inst.Typ.(*VoidType); void || inst.Typ == nil {
+		return body
+	}
+	return fmt.Sprintf("%%%s = %s", inst.Name, body)
+}
+
+// layout returns the layout of the target the instruction's module is built
+// for, or the default layout of an instruction outside a module
+func (inst *Instruction) layout() Layout {
+	if inst.Parent != nil && inst.Parent.Parent != nil && inst.Parent.Parent.Module != nil {
+		return inst.Parent.Parent.Module.Layout
+	}
+	return ParseLayout("")
+}
+
+func (inst *Instruction) body() string {
+	ops := inst.Operands
+	switch inst.Op {
+	case OpAlloca:
+		return fmt.Sprintf("alloca %s, align %d", inst.Elem, inst.layout().Align(inst.Elem))
+	case OpLoad:
+		return fmt.Sprintf("load %s, ptr %s, align %d", inst.Typ, ops[0].Ident(), inst.layout().Align(inst.Typ))
+	case OpStore:
+		return fmt.Sprintf("store %s, ptr %s, align %d", operand(ops[0]), ops[1].Ident(), inst.layout().Align(ops[0].Type()))
+	case OpGEP:
+		indices := make([]string, len(ops)-1)
+		for i, index := range ops[1:] {
+			indices[i] = operand(index)
+		}
+		return fmt.Sprintf("getelementptr inbounds %s, ptr %s, %s", inst.Elem, ops[0].Ident(), strings.Join(indices, ", "))
+	case OpICmp, OpFCmp:
+		return fmt.Sprintf("%s %s %s, %s", inst.Op, inst.Pred, operand(ops[0]), ops[1].Ident())
+	case OpFNeg:
+		return fmt.Sprintf("fneg %s", operand(ops[0]))
+	case OpTrunc, OpZExt, OpSExt, OpFPTrunc, OpFPExt, OpFPToUI, OpFPToSI, OpUIToFP, OpSIToFP:
+		return fmt.Sprintf("%s %s to %s", inst.Op, operand(ops[0]), inst.Typ)
+	case OpPhi:
+		incoming := make([]string, len(ops))
+		for i, value := range ops {
+			incoming[i] = fmt.Sprintf("[ %s, %%%s ]", value.Ident(), inst.Blocks[i].Name)
+		}
+		return fmt.Sprintf("phi %s %s", inst.Typ, strings.Join(incoming, ", "))
+	case OpCall:
+		return inst.callBody()
+	case OpExtractValue:
+		return fmt.Sprintf("extractvalue %s, %d", operand(ops[0]), inst.Index)
+	case OpBr:
+		if len(ops) == 0 {
+			return fmt.Sprintf("br label %%%s", inst.Blocks[0].Name)
+		}
+		return fmt.Sprintf("br %s, label %%%s, label %%%s", operand(ops[0]), inst.Blocks[0].Name, inst.Blocks[1].Name)
+	case OpRet:
+		if len(ops) == 0 {
+			return "ret void"
+		}
+		return "ret " + operand(ops[0])
+	case OpUnreachable:
+		return "unreachable"
+	default:
+		// Binary operators
+		return fmt.Sprintf("%s %s, %s", inst.Op, operand(ops[0]), ops[1].Ident())
+	}
+}
+
+// callBody prints a call. Arguments for declared parameters are printed with
+// the parameter type, variadic arguments with their own type.
+func (inst *Instruction) callBody() string {
+	sig := inst.Callee.Sig
+	args := make([]string, len(inst.Operands))
+	for i, arg := range inst.Operands {
+		if i < len(sig.Params) {
+			args[i] = sig.Params[i].String() + " " + arg.Ident()
+		} else {
+			args[i] = operand(arg)
+		}
+	}
+	callee := sig.Result.String()
+	if sig.Variadic {
+		callee = sig.String()
+	}
+	return fmt.Sprintf("call %s @%s(%s)", callee, inst.Callee.Name, strings.Join(args, ", "))
+}
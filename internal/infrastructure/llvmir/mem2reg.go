@@ -0,0 +1,514 @@
+package llvmir
+
+import "fmt"
+
+// promoteMemoryToRegisters rewrites the scalar local variables of a function,
+// whose stack slots are only loaded from and stored to, into SSA values.
+// Phis are placed on the iterated dominance frontier of the blocks storing a
+// variable, where the variable is live. Variables described by
+// llvm.dbg.declare are described by llvm.dbg.value at each new value instead.
+func promoteMemoryToRegisters(fn *Function) bool {
+	slots := promotableSlots(fn)
+	if len(slots) == 0 {
+		return false
+	}
+
+	// Unreachable blocks have no dominator; they are dropped first
+	removeUnreachableBlocks(fn)
+	dom := newDominatorTree(fn)
+
+	p := &promotion{
+		fn:           fn,
+		slots:        make(map[*Instruction]bool),
+		declares:     make(map[*Instruction]*Instruction),
+		phis:         make(map[*Instruction]*Instruction),
+		replacements: make(map[Value]Value),
+		dead:         make(map[*Instruction]bool),
+		names:        usedNames(fn),
+	}
+	for _, slot := range slots {
+		p.slots[slot] = true
+		p.dead[slot] = true
+	}
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			if slot := p.declaredSlot(inst); slot != nil {
+				p.declares[slot] = inst
+				p.dead[inst] = true
+			}
+		}
+	}
+
+	for _, slot := range slots {
+		p.placePhis(slot, dom)
+	}
+	p.rename(fn.Blocks[0], dom, make(map[*Instruction]Value))
+	p.simplifyPhis()
+	p.finish()
+	return true
+}
+
+// promotion holds the state of promoting the slots of one function
+type promotion struct {
+	fn           *Function
+	slots        map[*Instruction]bool
+	declares     map[*Instruction]*Instruction // Slot to its llvm.dbg.declare
+	phis         map[*Instruction]*Instruction // Placed phi to its slot
+	replacements map[Value]Value               // Removed load or phi to its value
+	dead         map[*Instruction]bool
+	names        map[string]bool
+	dbgValue     *Function
+}
+
+// promotableSlots returns the allocas of scalar variables that are only
+// loaded from and stored to with their own type, in function order
+func promotableSlots(fn *Function) []*Instruction {
+	candidates := localSlots(fn)
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			var slot *Instruction
+			var typ Type
+			switch inst.Op {
+			case OpLoad:
+				slot, _ = inst.Operands[0].(*Instruction)
+				typ = inst.Typ
+			case OpStore:
+				slot, _ = inst.Operands[1].(*Instruction)
+				typ = inst.Operands[0].Type()
+			default:
+				continue
+			}
+			if slot != nil && candidates[slot] && !Equal(slot.Elem, typ) {
+				delete(candidates, slot)
+			}
+		}
+	}
+
+	var slots []*Instruction
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			if !candidates[inst] {
+				continue
+			}
+			switch inst.Elem.(type) {
+			case *IntType, *FloatType, *PointerType:
+				slots = append(slots, inst)
+			}
+		}
+	}
+	return slots
+}
+
+// localSlots returns the allocas that are only loaded from, stored to and
+// described by debug intrinsics
+func localSlots(fn *Function) map[*Instruction]bool {
+	slots := make(map[*Instruction]bool)
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			if inst.Op == OpAlloca {
+				slots[inst] = true
+			}
+		}
+	}
+
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			for i, op := range inst.Operands {
+				slot, ok := op.(*Instruction)
+				if !ok || !slots[slot] {
+					continue
+				}
+				direct := (inst.Op == OpLoad && i == 0) || (inst.Op == OpStore && i == 1)
+				if !direct {
+					delete(slots, slot)
+				}
+			}
+		}
+	}
+	return slots
+}
+
+// declaredSlot returns the promoted slot an llvm.dbg.declare describes
+func (p *promotion) declaredSlot(inst *Instruction) *Instruction {
+	if !isDebugIntrinsic(inst) || inst.Callee.Name != "llvm.dbg.declare" {
+		return nil
+	}
+	md, ok := inst.Operands[0].(*MetadataValue)
+	if !ok {
+		return nil
+	}
+	if slot, ok := md.Value.(*Instruction); ok && p.slots[slot] {
+		return slot
+	}
+	return nil
+}
+
+// placePhis inserts empty phis for a slot at the blocks of the iterated
+// dominance frontier of its stores where the slot is live on entry
+func (p *promotion) placePhis(slot *Instruction, dom *dominatorTree) {
+	defines := make(map[*BasicBlock]bool)
+	for _, block := range p.fn.Blocks {
+		for _, inst := range block.Instructions {
+			if inst.Op == OpStore && inst.Operands[1] == Value(slot) {
+				defines[block] = true
+			}
+		}
+	}
+	live := p.liveIn(slot, defines, dom)
+
+	placed := make(map[*BasicBlock]bool)
+	var work []*BasicBlock
+	for _, block := range p.fn.Blocks {
+		if defines[block] {
+			work = append(work, block)
+		}
+	}
+	for len(work) > 0 {
+		block := work[len(work)-1]
+		work = work[:len(work)-1]
+		for _, frontier := range dom.frontier[block] {
+			if placed[frontier] || !live[frontier] {
+				continue
+			}
+			placed[frontier] = true
+			phi := &Instruction{Op: OpPhi, Name: p.uniqueName(slot.Name), Typ: slot.Elem, Parent: frontier}
+			frontier.Instructions = append([]*Instruction{phi}, frontier.Instructions...)
+			p.phis[phi] = slot
+			if !defines[frontier] {
+				work = append(work, frontier)
+			}
+		}
+	}
+}
+
+// liveIn returns the blocks a slot is read in before it is stored to,
+// directly or through their successors
+func (p *promotion) liveIn(slot *Instruction, defines map[*BasicBlock]bool, dom *dominatorTree) map[*BasicBlock]bool {
+	live := make(map[*BasicBlock]bool)
+	var work []*BasicBlock
+	for _, block := range p.fn.Blocks {
+		for _, inst := range block.Instructions {
+			if inst.Op == OpStore && inst.Operands[1] == Value(slot) {
+				break
+			}
+			if inst.Op == OpLoad && inst.Operands[0] == Value(slot) {
+				live[block] = true
+				work = append(work, block)
+				break
+			}
+		}
+	}
+	for len(work) > 0 {
+		block := work[len(work)-1]
+		work = work[:len(work)-1]
+		for _, pred := range dom.preds[block] {
+			if !live[pred] && !defines[pred] {
+				live[pred] = true
+				work = append(work, pred)
+			}
+		}
+	}
+	return live
+}
+
+// rename walks the dominator tree, replacing loads with the value stored last
+// and filling in the phis of successors. values holds the current value of
+// each slot on entry to block.
+func (p *promotion) rename(block *BasicBlock, dom *dominatorTree, values map[*Instruction]Value) {
+	var body []*Instruction
+	for _, inst := range block.Instructions {
+		body = append(body, inst)
+		if slot, ok := p.phis[inst]; ok {
+			values[slot] = inst
+			continue
+		}
+		switch inst.Op {
+		case OpLoad:
+			if slot, ok := inst.Operands[0].(*Instruction); ok && p.slots[slot] {
+				p.replacements[inst] = p.current(values, slot)
+				p.dead[inst] = true
+			}
+		case OpStore:
+			if slot, ok := inst.Operands[1].(*Instruction); ok && p.slots[slot] {
+				values[slot] = inst.Operands[0]
+				p.dead[inst] = true
+				if declare := p.declares[slot]; declare != nil {
+					body = append(body, p.debugValue(declare, inst.Operands[0], block))
+				}
+			}
+		}
+	}
+
+	// Variables merged by phis are described after the last phi
+	var merged []*Instruction
+	for _, inst := range phis(block) {
+		if declare := p.declares[p.phis[inst]]; declare != nil {
+			merged = append(merged, p.debugValue(declare, inst, block))
+		}
+	}
+	if len(merged) > 0 {
+		count := len(phis(block))
+		body = append(body[:count:count], append(merged, body[count:]...)...)
+	}
+	block.Instructions = body
+
+	for _, succ := range successors(block) {
+		for _, phi := range phis(succ) {
+			if slot, ok := p.phis[phi]; ok {
+				phi.Operands = append(phi.Operands, p.current(values, slot))
+				phi.Blocks = append(phi.Blocks, block)
+			}
+		}
+	}
+
+	for _, child := range dom.children[block] {
+		inherited := make(map[*Instruction]Value, len(values))
+		for slot, value := range values {
+			inherited[slot] = value
+		}
+		p.rename(child, dom, inherited)
+	}
+}
+
+// current returns the value of a slot, undef before its first store
+func (p *promotion) current(values map[*Instruction]Value, slot *Instruction) Value {
+	if value, ok := values[slot]; ok {
+		return value
+	}
+	return &Undef{Typ: slot.Elem}
+}
+
+// debugValue returns a call to llvm.dbg.value describing the variable of a
+// declare as value, declaring the intrinsic on first use
+func (p *promotion) debugValue(declare *Instruction, value Value, block *BasicBlock) *Instruction {
+	if p.dbgValue == nil {
+		if fn, ok := p.fn.Module.functions["llvm.dbg.value"]; ok {
+			p.dbgValue = fn
+		} else {
+			p.dbgValue = p.fn.Module.AddFunction("llvm.dbg.value", &FunctionType{Result: Void, Params: []Type{MD, MD, MD}}).(*Function)
+		}
+	}
+	return &Instruction{
+		Op:       OpCall,
+		Typ:      Void,
+		Operands: []Value{&MetadataValue{Value: value}, declare.Operands[1], declare.Operands[2]},
+		Callee:   p.dbgValue,
+		DebugLoc: declare.DebugLoc,
+		Parent:   block,
+	}
+}
+
+// resolve follows replacements to the value that remains in the function
+func (p *promotion) resolve(value Value) Value {
+	for {
+		replacement, ok := p.replacements[value]
+		if !ok {
+			return value
+		}
+		value = replacement
+	}
+}
+
+// simplifyPhis replaces phis that merge a single value, ignoring themselves,
+// with that value
+func (p *promotion) simplifyPhis() {
+	for changed := true; changed; {
+		changed = false
+		for phi := range p.phis {
+			if p.dead[phi] {
+				continue
+			}
+			var unique Value
+			trivial := true
+			for _, op := range phi.Operands {
+				op = p.resolve(op)
+				if op == Value(phi) || (unique != nil && sameValue(op, unique)) {
+					continue
+				}
+				if unique != nil {
+					trivial = false
+					break
+				}
+				unique = op
+			}
+			if trivial && unique != nil {
+				p.replacements[phi] = unique
+				p.dead[phi] = true
+				changed = true
+			}
+		}
+	}
+}
+
+// finish rewrites operands to the promoted values, removes the slots, their
+// loads, stores and declares, and drops phis nothing reads
+func (p *promotion) finish() {
+	for _, block := range p.fn.Blocks {
+		for _, inst := range block.Instructions {
+			for i, op := range inst.Operands {
+				if md, ok := op.(*MetadataValue); ok && md.Value != nil {
+					if value := p.resolve(md.Value); value != md.Value {
+						inst.Operands[i] = &MetadataValue{Value: value}
+					}
+				} else {
+					inst.Operands[i] = p.resolve(op)
+				}
+			}
+		}
+	}
+	removeInstructions(p.fn, p.dead)
+
+	for {
+		uses := countUses(p.fn)
+		unused := make(map[*Instruction]bool)
+		for phi := range p.phis {
+			if !p.dead[phi] && uses[phi] == 0 {
+				unused[phi] = true
+				p.dead[phi] = true
+			}
+		}
+		if len(unused) == 0 {
+			return
+		}
+		removeInstructions(p.fn, unused)
+	}
+}
+
+// uniqueName returns a name based on base that no value of the function uses
+func (p *promotion) uniqueName(base string) string {
+	for i := 0; ; i++ {
+		name := fmt.Sprintf("%s.%d", base, i)
+		if !p.names[name] {
+			p.names[name] = true
+			return name
+		}
+	}
+}
+
+// usedNames returns the names of the parameters and instructions of a function
+func usedNames(fn *Function) map[string]bool {
+	names := make(map[string]bool)
+	for _, param := range fn.Params {
+		names[param.Name] = true
+	}
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			if inst.Name != "" {
+				names[inst.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// dominatorTree holds the immediate dominators and dominance frontiers of
+// the blocks of a function, all of which must be reachable
+type dominatorTree struct {
+	preds    map[*BasicBlock][]*BasicBlock
+	idom     map[*BasicBlock]*BasicBlock
+	children map[*BasicBlock][]*BasicBlock
+	frontier map[*BasicBlock][]*BasicBlock
+}
+
+// newDominatorTree computes dominators with the iterative algorithm of
+// Cooper, Harvey and Kennedy over the reverse postorder of the blocks
+func newDominatorTree(fn *Function) *dominatorTree {
+	dom := &dominatorTree{
+		preds:    make(map[*BasicBlock][]*BasicBlock),
+		idom:     make(map[*BasicBlock]*BasicBlock),
+		children: make(map[*BasicBlock][]*BasicBlock),
+		frontier: make(map[*BasicBlock][]*BasicBlock),
+	}
+	for _, block := range fn.Blocks {
+		for _, succ := range successors(block) {
+			dom.preds[succ] = append(dom.preds[succ], block)
+		}
+	}
+
+	// Number the blocks in reverse postorder
+	var postorder []*BasicBlock
+	visited := make(map[*BasicBlock]bool)
+	var visit func(block *BasicBlock)
+	visit = func(block *BasicBlock) {
+		visited[block] = true
+		for _, succ := range successors(block) {
+			if !visited[succ] {
+				visit(succ)
+			}
+		}
+		postorder = append(postorder, block)
+	}
+	entry := fn.Blocks[0]
+	visit(entry)
+	order := make(map[*BasicBlock]int, len(postorder))
+	for i, block := range postorder {
+		order[block] = len(postorder) - 1 - i
+	}
+
+	intersect := func(a, b *BasicBlock) *BasicBlock {
+		for a != b {
+			for order[a] > order[b] {
+				a = dom.idom[a]
+			}
+			for order[b] > order[a] {
+				b = dom.idom[b]
+			}
+		}
+		return a
+	}
+	dom.idom[entry] = entry
+	for changed := true; changed; {
+		changed = false
+		for i := len(postorder) - 2; i >= 0; i-- {
+			block := postorder[i]
+			var idom *BasicBlock
+			for _, pred := range dom.preds[block] {
+				if _, ok := dom.idom[pred]; !ok {
+					continue
+				}
+				if idom == nil {
+					idom = pred
+				} else {
+					idom = intersect(pred, idom)
+				}
+			}
+			if dom.idom[block] != idom {
+				dom.idom[block] = idom
+				changed = true
+			}
+		}
+	}
+
+	for _, block := range fn.Blocks {
+		if block != entry {
+			dom.children[dom.idom[block]] = append(dom.children[dom.idom[block]], block)
+		}
+	}
+
+	// A join point is on the frontier of each block between its predecessors
+	// and its immediate dominator
+	for _, block := range fn.Blocks {
+		preds := dom.preds[block]
+		if len(preds) < 2 {
+			continue
+		}
+		for _, pred := range preds {
+			for runner := pred; runner != dom.idom[block]; runner = dom.idom[runner] {
+				if !containsBlock(dom.frontier[runner], block) {
+					dom.frontier[runner] = append(dom.frontier[runner], block)
+				}
+			}
+		}
+	}
+	return dom
+}
+
+// containsBlock reports whether blocks holds block
+func containsBlock(blocks []*BasicBlock, block *BasicBlock) bool {
+	for _, b := range blocks {
+		if b == block {
+			return true
+		}
+	}
+	return false
+}
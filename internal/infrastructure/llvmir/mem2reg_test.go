@@ -0,0 +1,168 @@
+package llvmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+func TestPromoteLoopVariable(t *testing.T) {
+	m, fn, b := newTestBuilder()
+
+	slot := b.CreateAlloca(I64, "i")
+	b.CreateStore(m.ConstInt(I64, 0), slot)
+	cond := fn.CreateBasicBlock("cond")
+	body := fn.CreateBasicBlock("body")
+	end := fn.CreateBasicBlock("end")
+	b.CreateBr(cond)
+	b.PositionAtEnd(cond)
+	i := b.CreateLoad(slot, "temp_0")
+	b.CreateCondBr(b.CreateICmp(interfaces.IntSLT, i, m.ConstInt(I64, 3), "temp_1"), body, end)
+	b.PositionAtEnd(body)
+	b.CreateStore(b.CreateAdd(b.CreateLoad(slot, "temp_2"), m.ConstInt(I64, 1), "temp_3"), slot)
+	b.CreateBr(cond)
+	b.PositionAtEnd(end)
+	b.CreateRetVoid()
+
+	if !promoteMemoryToRegisters(m.Functions[0]) {
+		t.Fatal("Expected the loop variable to be promoted")
+	}
+
+	output := printModule(m)
+	expected := []string{
+		"%i.0 = phi i64 [ 0, %entry ], [ %temp_3, %body ]",
+		"%temp_1 = icmp slt i64 %i.0, 3",
+		"%temp_3 = add i64 %i.0, 1",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	for _, unexpected := range []string{"alloca", "load", "store"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Expected %q to be removed, got: %s", unexpected, output)
+		}
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestPromoteBranches(t *testing.T) {
+	m := NewModule("test", "")
+	fn := m.AddFunction("pick", m.FunctionType(I64, []interfaces.LLVMType{I1}, false))
+	b := m.CreateBuilder()
+	b.PositionAtEnd(fn.CreateBasicBlock("entry"))
+
+	// x is assigned on one side only; y is read before it is stored
+	x := b.CreateAlloca(I64, "x")
+	y := b.CreateAlloca(I64, "y")
+	b.CreateStore(m.ConstInt(I64, 1), x)
+	then := fn.CreateBasicBlock("then")
+	end := fn.CreateBasicBlock("end")
+	b.CreateCondBr(fn.GetParameter(0), then, end)
+	b.PositionAtEnd(then)
+	b.CreateStore(b.CreateLoad(y, "temp_0"), x)
+	b.CreateBr(end)
+	b.PositionAtEnd(end)
+	b.CreateRet(b.CreateLoad(x, "temp_1"))
+
+	promoteMemoryToRegisters(fn.(*Function))
+
+	output := printModule(m)
+	if !strings.Contains(output, "%x.0 = phi i64 [ 1, %entry ], [ undef, %then ]") {
+		t.Errorf("Expected a phi merging x, got: %s", output)
+	}
+	if !strings.Contains(output, "ret i64 %x.0") {
+		t.Errorf("Expected the phi to be returned, got: %s", output)
+	}
+	if strings.Contains(output, "%y.") {
+		t.Errorf("Expected no phi for y, got: %s", output)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestPromoteSkipsAddressTaken(t *testing.T) {
+	m, _, b := newTestBuilder()
+
+	point := &StructType{Fields: []Type{I64, I64}}
+	aggregate := b.CreateAlloca(point, "p")
+	b.CreateStore(m.ConstInt(I64, 1), b.CreateInBoundsGEP(point, aggregate, []interfaces.LLVMValue{m.ConstInt(I32, 0), m.ConstInt(I32, 0)}, "temp_0"))
+	escaped := b.CreateAlloca(I64, "n")
+	b.CreateStore(m.ConstInt(I64, 2), escaped)
+	opaque := m.AddFunction("opaque", m.FunctionType(Void, []interfaces.LLVMType{Ptr}, false))
+	b.CreateCall(opaque, []interfaces.LLVMValue{escaped}, "")
+	b.CreateRetVoid()
+
+	if promoteMemoryToRegisters(m.Functions[0]) {
+		t.Errorf("Expected nothing to be promoted, got: %s", printModule(m))
+	}
+}
+
+func TestPromoteDebugValues(t *testing.T) {
+	m, fn, b := newTestBuilder()
+	di := m.CreateDIBuilder()
+	di.CreateCompileUnit("test.sl", "/src", "StaticLang", false)
+	file := di.CreateFile("test.sl", "/src")
+	subprogram := di.CreateFunction(file, "test", file, 1, di.CreateSubroutineType([]interfaces.LLVMMetadata{nil}), false)
+	di.SetSubprogram(fn, subprogram)
+	location := di.CreateDebugLocation(2, 5, subprogram)
+	b.SetCurrentDebugLocation(location)
+
+	slot := b.CreateAlloca(I64, "x")
+	di.InsertDeclareAtEnd(slot, di.CreateAutoVariable(subprogram, "x", file, 2, di.CreateBasicType("int", 64, interfaces.DWARFSigned)), location, b.GetInsertBlock())
+	b.CreateStore(m.ConstInt(I64, 7), slot)
+	b.CreateRetVoid()
+	di.Finalize()
+
+	promoteMemoryToRegisters(fn.(*Function))
+
+	output := printModule(m)
+	if !strings.Contains(output, "call void @llvm.dbg.value(metadata i64 7, metadata !") {
+		t.Errorf("Expected the stored value to be described, got: %s", output)
+	}
+	if strings.Contains(output, "call void @llvm.dbg.declare") {
+		t.Errorf("Expected the declaration to be removed, got: %s", output)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestDominatorTree(t *testing.T) {
+	m, fn, b := newTestBuilder()
+
+	// entry -> left, right -> join
+	left := fn.CreateBasicBlock("left")
+	right := fn.CreateBasicBlock("right")
+	join := fn.CreateBasicBlock("join")
+	b.CreateCondBr(m.ConstInt(I1, 1), left, right)
+	b.PositionAtEnd(left)
+	b.CreateBr(join)
+	b.PositionAtEnd(right)
+	b.CreateBr(join)
+	b.PositionAtEnd(join)
+	b.CreateRetVoid()
+
+	function := fn.(*Function)
+	entry := function.Blocks[0]
+	dom := newDominatorTree(function)
+	for _, block := range []interfaces.LLVMBasicBlock{left, right, join} {
+		if dom.idom[block.(*BasicBlock)] != entry {
+			t.Errorf("Expected entry to immediately dominate %s", block.(*BasicBlock).Name)
+		}
+	}
+	for _, block := range []interfaces.LLVMBasicBlock{left, right} {
+		frontier := dom.frontier[block.(*BasicBlock)]
+		if len(frontier) != 1 || frontier[0] != join.(*BasicBlock) {
+			t.Errorf("Expected the frontier of %s to be join, got %v", block.(*BasicBlock).Name, frontier)
+		}
+	}
+	if len(dom.frontier[entry]) != 0 {
+		t.Errorf("Expected an empty frontier for entry, got %v", dom.frontier[entry])
+	}
+}
@@ -0,0 +1,172 @@
+package llvmir
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// MDNode is a numbered metadata node. Debug information nodes print as
+// !DIKind(field: value, ...), tuples as !{element, ...}.
+type MDNode struct {
+	ID       int
+	Kind     string // Debug information node kind, empty for a tuple
+	Distinct bool
+	Fields   []MDField     // Fields of a debug information node, in print order
+	Elements []interface{} // Tuple elements: *MDNode, nil or MDRaw
+}
+
+// MDField is a named field of a debug information node. Values are printed
+// by type: strings quoted, *MDNode as a reference, MDRaw verbatim.
+type MDField struct {
+	Name  string
+	Value interface{}
+}
+
+// MDRaw is metadata text printed verbatim, such as DWARF constants and
+// typed tuple elements
+type MDRaw string
+
+// NamedMetadata is a module-level named metadata list such as !llvm.dbg.cu
+type NamedMetadata struct {
+	Name  string
+	Nodes []*MDNode
+}
+
+// Ref returns how the node is referenced
+func (n *MDNode) Ref() string {
+	return fmt.Sprintf("!%d", n.ID)
+}
+
+// Field returns the value of a field, or nil if the node has no such field
+func (n *MDNode) Field(name string) interface{} {
+	for _, field := range n.Fields {
+		if field.Name == name {
+			return field.Value
+		}
+	}
+	return nil
+}
+
+// SetField replaces the value of a field, appending it if missing
+func (n *MDNode) SetField(name string, value interface{}) {
+	for i, field := range n.Fields {
+		if field.Name == name {
+			n.Fields[i].Value = value
+			return
+		}
+	}
+	n.Fields = append(n.Fields, MDField{Name: name, Value: value})
+}
+
+// String returns the node definition without its number
+func (n *MDNode) String() string {
+	prefix := ""
+	if n.Distinct {
+		prefix = "distinct "
+	}
+	if n.Kind == "" {
+		elements := make([]string, len(n.Elements))
+		for i, element := range n.Elements {
+			elements[i] = mdString(element)
+		}
+		return prefix + "!{" + strings.Join(elements, ", ") + "}"
+	}
+
+	fields := make([]string, len(n.Fields))
+	for i, field := range n.Fields {
+		fields[i] = field.Name + ": " + mdString(field.Value)
+	}
+	return fmt.Sprintf("%s!%s(%s)", prefix, n.Kind, strings.Join(fields, ", "))
+}
+
+// mdString prints a metadata field value or tuple element
+func mdString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case *MDNode:
+		if v == nil {
+			return "null"
+		}
+		return v.Ref()
+	case MDRaw:
+		return string(v)
+	case string:
+		return fmt.Sprintf("\"%s\"", escapeString(v))
+	case bool:
+		return fmt.Sprint(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// addMetadata numbers a node and adds it to the module
+func (m *Module) addMetadata(node *MDNode) *MDNode {
+	node.ID = len(m.Metadata)
+	m.Metadata = append(m.Metadata, node)
+	return node
+}
+
+// addTuple adds a metadata tuple
+func (m *Module) addTuple(elements ...interface{}) *MDNode {
+	return m.addMetadata(&MDNode{Elements: elements})
+}
+
+// addNamedMetadata appends nodes to a named metadata list
+func (m *Module) addNamedMetadata(name string, nodes ...*MDNode) {
+	for _, named := range m.NamedMetadata {
+		if named.Name == name {
+			named.Nodes = append(named.Nodes, nodes...)
+			return
+		}
+	}
+	m.NamedMetadata = append(m.NamedMetadata, &NamedMetadata{Name: name, Nodes: nodes})
+}
+
+// printMetadata writes the named and numbered metadata of the module
+func (m *Module) printMetadata(output io.Writer) {
+	if len(m.Metadata) == 0 {
+		return
+	}
+
+	fmt.Fprintln(output)
+	for _, named := range m.NamedMetadata {
+		refs := make([]string, len(named.Nodes))
+		for i, node := range named.Nodes {
+			refs[i] = node.Ref()
+		}
+		fmt.Fprintf(output, "!%s = !{%s}\n", named.Name, strings.Join(refs, ", "))
+	}
+	fmt.Fprintln(output)
+	for _, node := range m.Metadata {
+		fmt.Fprintf(output, "%s = %s\n", node.Ref(), node)
+	}
+}
+
+// MetadataValue passes metadata as a call argument, as the llvm.dbg
+// intrinsics take it
+type MetadataValue struct {
+	Value Value   // Wrapped IR value, printed with its type
+	Node  *MDNode // Referenced node
+	Raw   MDRaw   // Inline metadata such as !DIExpression()
+}
+
+func (v *MetadataValue) GetType() interfaces.LLVMType { return MD }
+func (v *MetadataValue) SetName(string)               {}
+func (v *MetadataValue) GetName() string              { return v.Ident() }
+func (v *MetadataValue) Type() Type                   { return MD }
+
+// Ident prints the argument without the leading metadata type
+func (v *MetadataValue) Ident() string {
+	switch {
+	case v.Value != nil:
+		return operand(v.Value)
+	case v.Node != nil:
+		return v.Node.Ref()
+	default:
+		return string(v.Raw)
+	}
+}
@@ -0,0 +1,411 @@
+package llvmir
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sokoide/llvm5/internal/domain"
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// Module is an LLVM module holding types, globals and functions in the
+// order they were added
+type Module struct {
+	Name          string
+	TargetTriple  string
+	DataLayout    string
+	Layout        Layout // How the target lays out values, read from DataLayout
+	Structs       []*StructType
+	Globals       []*Global
+	Functions     []*Function
+	Metadata      []*MDNode
+	NamedMetadata []*NamedMetadata
+	structs       map[string]*StructType
+	functions     map[string]*Function
+}
+
+// Function is a function declaration, or a definition once it has blocks
+type Function struct {
+	Name       string
+	Sig        *FunctionType
+	Params     []*Param
+	Blocks     []*BasicBlock
+	Module     *Module
+	Subprogram *MDNode // Debug information of a definition, if any
+}
+
+// BasicBlock is a labeled sequence of instructions ending in a terminator
+type BasicBlock struct {
+	Name         string
+	Instructions []*Instruction
+	Parent       *Function
+	placed       bool
+}
+
+var (
+	_ interfaces.LLVMModule     = (*Module)(nil)
+	_ interfaces.LLVMFunction   = (*Function)(nil)
+	_ interfaces.LLVMBasicBlock = (*BasicBlock)(nil)
+	_ Value                     = (*Function)(nil)
+)
+
+// NewModule creates an empty module for the given target triple, laid out
+// as LLVM lays out that target
+func NewModule(name, targetTriple string) *Module {
+	dataLayout := DataLayout(targetTriple)
+	return &Module{
+		Name:         name,
+		TargetTriple: targetTriple,
+		DataLayout:   dataLayout,
+		Layout:       ParseLayout(dataLayout),
+		structs:      make(map[string]*StructType),
+		functions:    make(map[string]*Function),
+	}
+}
+
+// CreateFunction creates a function from a source-level function type
+func (m *Module) CreateFunction(name string, funcType domain.Type) (interfaces.LLVMFunction, error) {
+	if _, exists := m.functions[name]; exists {
+		return nil, fmt.Errorf("function %s already exists", name)
+	}
+	ft, ok := funcType.(*domain.FunctionType)
+	if !ok {
+		return nil, fmt.Errorf("invalid function type")
+	}
+	return m.AddFunction(name, m.lowerType(ft)), nil
+}
+
+// CreateGlobalVariable creates a zero-initialized global of a source-level type
+func (m *Module) CreateGlobalVariable(name string, varType domain.Type) (interfaces.LLVMValue, error) {
+	for _, global := range m.Globals {
+		if global.Name == name {
+			return nil, fmt.Errorf("global variable %s already exists", name)
+		}
+	}
+	return m.AddGlobal(name, m.lowerType(varType), nil), nil
+}
+
+// CreateStruct creates a named struct type from a source-level struct
+func (m *Module) CreateStruct(name string, structType *domain.StructType) (interfaces.LLVMType, error) {
+	if st, exists := m.structs[name]; exists && !st.Opaque {
+		return nil, fmt.Errorf("struct %s already exists", name)
+	}
+	st := m.NamedStructType(name).(*StructType)
+	st.Fields = make([]Type, len(structType.Order))
+	for i, fieldName := range structType.Order {
+		st.Fields[i] = m.lowerType(structType.Fields[fieldName])
+	}
+	st.Opaque = false
+	return st, nil
+}
+
+// lowerType maps a source-level type to its LLVM representation
+func (m *Module) lowerType(t domain.Type) Type {
+	switch typ := t.(type) {
+	case *domain.StructType:
+		return m.NamedStructType("struct." + typ.Name).(Type)
+	case *domain.ArrayType:
+		if typ.Size == -1 {
+			return Ptr
+		}
+		return &ArrayType{Len: typ.Size, Elem: m.lowerType(typ.ElementType)}
+	case *domain.FunctionType:
+		params := make([]Type, len(typ.ParameterTypes))
+		for i, param := range typ.ParameterTypes {
+			params[i] = m.lowerType(param)
+		}
+		return &FunctionType{Result: m.lowerType(typ.ReturnType), Params: params}
+	case *domain.BasicType:
+		switch {
+		case typ.IsInteger():
+			return &IntType{Bits: typ.GetSize() * 8}
+		case typ.Kind == domain.Float32Type:
+			return Float
+		case typ.IsFloat():
+			return Double
+		case typ.Kind == domain.BoolType:
+			return I1
+		case typ.Kind == domain.StringType:
+			return &PointerType{Elem: I8}
+		case typ.Kind == domain.VoidType:
+			return Void
+		}
+	}
+	return I32
+}
+
+// GetFunction gets a function by name
+func (m *Module) GetFunction(name string) (interfaces.LLVMFunction, bool) {
+	fn, ok := m.functions[name]
+	return fn, ok
+}
+
+// AddFunction adds a function of the given signature
+func (m *Module) AddFunction(name string, funcType interfaces.LLVMType) interfaces.LLVMFunction {
+	sig, ok := funcType.(*FunctionType)
+	if !ok {
+		panic(fmt.Sprintf("llvmir: %s is not a function type", funcType))
+	}
+	fn := &Function{Name: name, Sig: sig, Module: m}
+	for i, param := range sig.Params {
+		fn.Params = append(fn.Params, &Param{Name: fmt.Sprintf("%d", i), Typ: param})
+	}
+	m.Functions = append(m.Functions, fn)
+	m.functions[name] = fn
+	return fn
+}
+
+// AddGlobal adds a global variable
+func (m *Module) AddGlobal(name string, t interfaces.LLVMType, init interfaces.LLVMValue) interfaces.LLVMValue {
+	global := &Global{Name: name, ValueType: asType(t)}
+	if init != nil {
+		global.Init = asValue(init)
+	}
+	m.Globals = append(m.Globals, global)
+	return global
+}
+
+// AddStringConstant adds a private constant holding a NUL-terminated string
+func (m *Module) AddStringConstant(name, value string) interfaces.LLVMValue {
+	init := &ConstString{Value: value}
+	global := &Global{
+		Name:        name,
+		ValueType:   init.Type(),
+		Init:        init,
+		Constant:    true,
+		Private:     true,
+		UnnamedAddr: true,
+	}
+	m.Globals = append(m.Globals, global)
+	return global
+}
+
+// CreateBuilder creates an instruction builder for the module
+func (m *Module) CreateBuilder() interfaces.LLVMBuilder {
+	return NewBuilder()
+}
+
+func (m *Module) IntType(bits int) interfaces.LLVMType { return &IntType{Bits: bits} }
+func (m *Module) FloatType() interfaces.LLVMType       { return Float }
+func (m *Module) DoubleType() interfaces.LLVMType      { return Double }
+func (m *Module) VoidType() interfaces.LLVMType        { return Void }
+
+func (m *Module) PointerType(elem interfaces.LLVMType) interfaces.LLVMType {
+	if elem == nil {
+		return Ptr
+	}
+	return &PointerType{Elem: asType(elem)}
+}
+
+func (m *Module) SizeOf(t interfaces.LLVMType) int  { return m.Layout.Size(asType(t)) }
+func (m *Module) AlignOf(t interfaces.LLVMType) int { return m.Layout.Align(asType(t)) }
+
+func (m *Module) ArrayType(elem interfaces.LLVMType, length int) interfaces.LLVMType {
+	return &ArrayType{Len: length, Elem: asType(elem)}
+}
+
+func (m *Module) StructType(fields []interfaces.LLVMType) interfaces.LLVMType {
+	return &StructType{Fields: asTypes(fields)}
+}
+
+// NamedStructType returns the named struct, creating an opaque one on first use
+func (m *Module) NamedStructType(name string) interfaces.LLVMType {
+	if st, ok := m.structs[name]; ok {
+		return st
+	}
+	st := &StructType{Name: name, Opaque: true}
+	m.structs[name] = st
+	m.Structs = append(m.Structs, st)
+	return st
+}
+
+// SetStructBody sets the fields of a named struct
+func (m *Module) SetStructBody(structType interfaces.LLVMType, fields []interfaces.LLVMType) {
+	st := asType(structType).(*StructType)
+	st.Fields = asTypes(fields)
+	st.Opaque = false
+}
+
+func (m *Module) FunctionType(result interfaces.LLVMType, params []interfaces.LLVMType, variadic bool) interfaces.LLVMType {
+	return &FunctionType{Result: asType(result), Params: asTypes(params), Variadic: variadic}
+}
+
+func (m *Module) ConstInt(t interfaces.LLVMType, value int64) interfaces.LLVMValue {
+	return &ConstInt{Typ: asType(t).(*IntType), Value: value}
+}
+
+func (m *Module) ConstFloat(t interfaces.LLVMType, value float64) interfaces.LLVMValue {
+	return &ConstFloat{Typ: asType(t).(*FloatType), Value: value}
+}
+
+func (m *Module) ConstNull(t interfaces.LLVMType) interfaces.LLVMValue {
+	return &ConstNull{Typ: asType(t).(*PointerType)}
+}
+
+func (m *Module) ConstZero(t interfaces.LLVMType) interfaces.LLVMValue {
+	return &ConstZero{Typ: asType(t)}
+}
+
+func (m *Module) ConstAggregate(t interfaces.LLVMType, elems []interfaces.LLVMValue) interfaces.LLVMValue {
+	return &ConstAggregate{Typ: asType(t), Elems: asValues(elems)}
+}
+
+func (m *Module) ConstGEP(t interfaces.LLVMType, ptr interfaces.LLVMValue, indices []interfaces.LLVMValue) interfaces.LLVMValue {
+	return &ConstGEP{Elem: asType(t), Base: asValue(ptr), Indices: asValues(indices)}
+}
+
+func (m *Module) ConstSizeOf(t interfaces.LLVMType) interfaces.LLVMValue {
+	return &ConstSizeOf{Elem: asType(t), Typ: &IntType{Bits: m.Layout.PointerSize * 8}}
+}
+
+// Verify checks that every emitted block ends in exactly one terminator and
+// that branches only target emitted blocks of the same function
+func (m *Module) Verify() error {
+	for _, fn := range m.Functions {
+		for _, block := range fn.Blocks {
+			for i, inst := range block.Instructions {
+				if inst.IsTerminator() && i != len(block.Instructions)-1 {
+					return fmt.Errorf("block %s in function %s has instructions after its terminator", block.Name, fn.Name)
+				}
+				for _, target := range inst.Blocks {
+					if target.Parent != fn || !target.placed {
+						return fmt.Errorf("block %s in function %s branches to missing block %s", block.Name, fn.Name, target.Name)
+					}
+				}
+			}
+			if !block.IsTerminated() {
+				return fmt.Errorf("basic block %s in function %s is not terminated", block.Name, fn.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// Print writes the module as textual IR
+func (m *Module) Print(output io.Writer) {
+	fmt.Fprintf(output, "; ModuleID = '%s'\n", m.Name)
+	if m.DataLayout != "" {
+		fmt.Fprintf(output, "target datalayout = \"%s\"\n", m.DataLayout)
+	}
+	if m.TargetTriple != "" {
+		fmt.Fprintf(output, "target triple = \"%s\"\n", m.TargetTriple)
+	}
+
+	if len(m.Structs) > 0 {
+		fmt.Fprintln(output)
+	}
+	for _, st := range m.Structs {
+		if st.Opaque {
+			fmt.Fprintf(output, "%%%s = type opaque\n", st.Name)
+		} else {
+			fmt.Fprintf(output, "%%%s = type %s\n", st.Name, st.body())
+		}
+	}
+
+	if len(m.Globals) > 0 {
+		fmt.Fprintln(output)
+	}
+	for _, global := range m.Globals {
+		fmt.Fprintln(output, global.definition(m.Layout))
+	}
+
+	declared := false
+	for _, fn := range m.Functions {
+		if len(fn.Blocks) == 0 {
+			if !declared {
+				fmt.Fprintln(output)
+				declared = true
+			}
+			fmt.Fprintf(output, "declare %s @%s(%s)\n", fn.Sig.Result, fn.Name, fn.Sig.paramList())
+		}
+	}
+
+	for _, fn := range m.Functions {
+		if len(fn.Blocks) > 0 {
+			fmt.Fprintln(output)
+			fn.print(output)
+		}
+	}
+
+	m.printMetadata(output)
+}
+
+// Dispose releases the module contents
+func (m *Module) Dispose() {
+	m.Structs = nil
+	m.Globals = nil
+	m.Functions = nil
+	m.Metadata = nil
+	m.NamedMetadata = nil
+	m.structs = make(map[string]*StructType)
+	m.functions = make(map[string]*Function)
+}
+
+// CreateBasicBlock creates a block in the function. Blocks are laid out in
+// the order a builder is first positioned at them, so a block that is never
+// used does not appear in the output.
+func (fn *Function) CreateBasicBlock(name string) interfaces.LLVMBasicBlock {
+	return &BasicBlock{Name: name, Parent: fn}
+}
+
+// GetParameter gets a parameter by index
+func (fn *Function) GetParameter(index int) interfaces.LLVMValue {
+	if index < 0 || index >= len(fn.Params) {
+		return nil
+	}
+	return fn.Params[index]
+}
+
+// GetParameterCount gets the number of parameters
+func (fn *Function) GetParameterCount() int {
+	return len(fn.Params)
+}
+
+func (fn *Function) SetName(name string) {
+	delete(fn.Module.functions, fn.Name)
+	fn.Name = name
+	fn.Module.functions[name] = fn
+}
+func (fn *Function) GetName() string              { return fn.Name }
+func (fn *Function) GetType() interfaces.LLVMType { return Ptr }
+func (fn *Function) Type() Type                   { return Ptr }
+func (fn *Function) Ident() string                { return "@" + fn.Name }
+
+func (fn *Function) print(output io.Writer) {
+	params := make([]string, len(fn.Params))
+	for i, param := range fn.Params {
+		params[i] = operand(param)
+	}
+	debug := ""
+	if fn.Subprogram != nil {
+		debug = " !dbg " + fn.Subprogram.Ref()
+	}
+	fmt.Fprintf(output, "define %s @%s(%s)%s {\n", fn.Sig.Result, fn.Name, strings.Join(params, ", "), debug)
+	for _, block := range fn.Blocks {
+		fmt.Fprintf(output, "%s:\n", block.Name)
+		for _, inst := range block.Instructions {
+			fmt.Fprintf(output, "  %s\n", inst)
+		}
+	}
+	fmt.Fprintln(output, "}")
+}
+
+// GetName gets the block name
+func (b *BasicBlock) GetName() string {
+	return b.Name
+}
+
+// IsTerminated reports whether the block ends in a terminator
+func (b *BasicBlock) IsTerminated() bool {
+	n := len(b.Instructions)
+	return n > 0 && b.Instructions[n-1].IsTerminator()
+}
+
+// Terminator returns the last instruction of a terminated block
+func (b *BasicBlock) Terminator() *Instruction {
+	if !b.IsTerminated() {
+		return nil
+	}
+	return b.Instructions[len(b.Instructions)-1]
+}
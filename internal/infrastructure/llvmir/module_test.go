@@ -0,0 +1,162 @@
+package llvmir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// printModule returns the textual IR of a module
+func printModule(m *Module) string {
+	var sb strings.Builder
+	m.Print(&sb)
+	return sb.String()
+}
+
+func TestModulePrint(t *testing.T) {
+	m := NewModule("test", "x86_64-apple-macosx10.15.0")
+	point := m.NamedStructType("struct.Point")
+	m.SetStructBody(point, []interfaces.LLVMType{I64, I64})
+	m.NamedStructType("struct.Opaque")
+	m.AddGlobal("counter", I64, nil)
+	origin := m.ConstAggregate(point, []interfaces.LLVMValue{m.ConstZero(I64), m.ConstInt(I64, 1)})
+	m.AddGlobal("line", m.ArrayType(point, 2), m.ConstAggregate(m.ArrayType(point, 2), []interfaces.LLVMValue{origin, m.ConstZero(point)}))
+	m.AddStringConstant(".str.0", "hi\n")
+	m.AddFunction("printf", m.FunctionType(I32, []interfaces.LLVMType{m.PointerType(I8)}, true))
+
+	fn := m.AddFunction("main", m.FunctionType(I32, nil, false))
+	b := m.CreateBuilder()
+	b.PositionAtEnd(fn.CreateBasicBlock("entry"))
+	b.CreateRet(m.ConstInt(I32, 0))
+
+	output := printModule(m)
+	expected := []string{
+		"; ModuleID = 'test'",
+		`target triple = "x86_64-apple-macosx10.15.0"`,
+		"%struct.Point = type { i64, i64 }",
+		"%struct.Opaque = type opaque",
+		"@counter = global i64 0, align 8",
+		"@line = global [2 x %struct.Point] [%struct.Point { i64 zeroinitializer, i64 1 }, %struct.Point zeroinitializer], align 8",
+		`@.str.0 = private unnamed_addr constant [4 x i8] c"hi\0A\00", align 1`,
+		"declare i32 @printf(ptr, ...)",
+		"define i32 @main() {\nentry:\n  ret i32 0\n}",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if strings.Index(output, "@counter") > strings.Index(output, "define") {
+		t.Errorf("Expected globals before function definitions, got: %s", output)
+	}
+}
+
+func TestModuleVerify(t *testing.T) {
+	m := NewModule("test", "")
+	fn := m.AddFunction("f", m.FunctionType(Void, nil, false))
+	b := m.CreateBuilder()
+	entry := fn.CreateBasicBlock("entry")
+	b.PositionAtEnd(entry)
+
+	if err := m.Verify(); err == nil {
+		t.Error("Verify should reject an unterminated block")
+	}
+
+	// A branch target that was never placed is reported
+	next := fn.CreateBasicBlock("next")
+	b.CreateBr(next)
+	if err := m.Verify(); err == nil {
+		t.Error("Verify should reject a branch to a block outside the function")
+	}
+
+	b.PositionAtEnd(next)
+	b.CreateRetVoid()
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed for a well-formed function: %v", err)
+	}
+	if entry.(*BasicBlock).Terminator() == nil || !next.IsTerminated() {
+		t.Error("Expected both blocks to be terminated")
+	}
+}
+
+func TestFunctionParameters(t *testing.T) {
+	m := NewModule("test", "")
+	fn := m.AddFunction("add", m.FunctionType(I64, []interfaces.LLVMType{I64, I64}, false))
+	if fn.GetParameterCount() != 2 {
+		t.Fatalf("Expected 2 parameters, got %d", fn.GetParameterCount())
+	}
+	fn.GetParameter(0).SetName("a")
+	fn.GetParameter(1).SetName("b")
+
+	b := m.CreateBuilder()
+	b.PositionAtEnd(fn.CreateBasicBlock("entry"))
+	b.CreateRet(b.CreateAdd(fn.GetParameter(0), fn.GetParameter(1), "sum"))
+
+	output := printModule(m)
+	if !strings.Contains(output, "define i64 @add(i64 %a, i64 %b) {") {
+		t.Errorf("Expected named parameters in the signature, got: %s", output)
+	}
+	if !strings.Contains(output, "%sum = add i64 %a, %b") {
+		t.Errorf("Expected addition of the parameters, got: %s", output)
+	}
+
+	if _, ok := m.GetFunction("add"); !ok {
+		t.Error("GetFunction should find a declared function")
+	}
+	fn.SetName("plus")
+	if _, ok := m.GetFunction("plus"); !ok {
+		t.Error("GetFunction should find a renamed function")
+	}
+}
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		typ      Type
+		expected int
+	}{
+		{I1, 1},
+		{I32, 4},
+		{I64, 8},
+		{Float, 4},
+		{Double, 8},
+		{Ptr, 8},
+		{&ArrayType{Len: 4, Elem: I16}, 2},
+		{&StructType{Fields: []Type{I8, Double}}, 8},
+	}
+
+	layout := ParseLayout(DataLayout("x86_64-pc-linux-gnu"))
+	for _, test := range tests {
+		if got := layout.Align(test.typ); got != test.expected {
+			t.Errorf("Align(%s) = %d, expected %d", test.typ, got, test.expected)
+		}
+	}
+}
+
+func TestBackend(t *testing.T) {
+	backend := NewBackend()
+	if _, err := backend.CreateModule("test"); err == nil {
+		t.Error("CreateModule should fail before Initialize")
+	}
+	if err := backend.Initialize("x86_64-apple-macosx10.15.0"); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if err := backend.Initialize("x86_64-apple-macosx10.15.0"); err == nil {
+		t.Error("Initialize should fail when called twice")
+	}
+
+	module, err := backend.CreateModule("test")
+	if err != nil {
+		t.Fatalf("CreateModule failed: %v", err)
+	}
+	if module.(*Module).TargetTriple != "x86_64-apple-macosx10.15.0" {
+		t.Error("Module should use the backend's target triple")
+	}
+	if err := backend.Optimize(module, 0); err != nil {
+		t.Errorf("Optimize failed: %v", err)
+	}
+	if err := backend.EmitObject(module, nil); err == nil {
+		t.Error("EmitObject should report that llc is required")
+	}
+	backend.Dispose()
+}
@@ -0,0 +1,208 @@
+package llvmir
+
+import "strings"
+
+// Pass is a named transformation of a function. Run reports whether the
+// function changed.
+type Pass struct {
+	Name string
+	Run  func(fn *Function) bool
+}
+
+// PassManager runs a pipeline of passes over every function definition of a
+// module until none of them changes the function any more
+type PassManager struct {
+	passes        []Pass
+	maxIterations int
+}
+
+// Inlining size limits, in instructions of the callee
+const (
+	inlineThresholdO2 = 25
+	inlineThresholdO3 = 75
+)
+
+// NewPassManager creates the pipeline of an optimization level. Every level
+// promotes local variables to SSA values; -O1 also folds constants, which
+// propagates them through the SSA values, and removes dead code and
+// unreachable blocks; -O2 also inlines small functions; -O3 inlines larger
+// ones.
+func NewPassManager(level int) *PassManager {
+	pm := &PassManager{maxIterations: 8}
+	pm.Add(Pass{Name: "mem2reg", Run: promoteMemoryToRegisters})
+	if level <= 0 {
+		return pm
+	}
+
+	switch {
+	case level == 2:
+		pm.Add(Pass{Name: "inline", Run: newInliner(inlineThresholdO2).run})
+	case level >= 3:
+		pm.Add(Pass{Name: "inline", Run: newInliner(inlineThresholdO3).run})
+		pm.maxIterations = 16
+	}
+	pm.Add(Pass{Name: "fold", Run: foldConstants})
+	pm.Add(Pass{Name: "simplifycfg", Run: simplifyCFG})
+	pm.Add(Pass{Name: "dce", Run: eliminateDeadCode})
+	return pm
+}
+
+// Add appends a pass to the pipeline
+func (pm *PassManager) Add(pass Pass) {
+	pm.passes = append(pm.passes, pass)
+}
+
+// Passes returns the names of the passes in pipeline order
+func (pm *PassManager) Passes() []string {
+	names := make([]string, len(pm.passes))
+	for i, pass := range pm.passes {
+		names[i] = pass.Name
+	}
+	return names
+}
+
+// Run optimizes every function definition of the module, reporting whether
+// anything changed
+func (pm *PassManager) Run(m *Module) bool {
+	changed := false
+	for _, fn := range m.Functions {
+		if len(fn.Blocks) == 0 {
+			continue
+		}
+		for i := 0; i < pm.maxIterations; i++ {
+			iterationChanged := false
+			for _, pass := range pm.passes {
+				if pass.Run(fn) {
+					iterationChanged = true
+				}
+			}
+			if !iterationChanged {
+				break
+			}
+			changed = true
+		}
+	}
+	return changed
+}
+
+// isDebugIntrinsic reports whether an instruction is a call to an llvm.dbg
+// intrinsic. These only describe variables and are kept by every pass.
+func isDebugIntrinsic(inst *Instruction) bool {
+	return inst.Op == OpCall && strings.HasPrefix(inst.Callee.Name, "llvm.dbg.")
+}
+
+// hasSideEffects reports whether an instruction must be kept even if its
+// result is unused
+func hasSideEffects(inst *Instruction) bool {
+	switch inst.Op {
+	case OpStore, OpCall:
+		return true
+	}
+	return inst.IsTerminator()
+}
+
+// countUses returns how many operands refer to each instruction of a
+// function. References from debug intrinsics are not counted, so describing
+// a variable does not keep its value alive.
+func countUses(fn *Function) map[*Instruction]int {
+	uses := make(map[*Instruction]int)
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			if isDebugIntrinsic(inst) {
+				continue
+			}
+			for _, op := range inst.Operands {
+				if used, ok := op.(*Instruction); ok {
+					uses[used]++
+				}
+			}
+		}
+	}
+	return uses
+}
+
+// replaceUses makes every operand of the function that refers to old refer
+// to replacement instead
+func replaceUses(fn *Function, old *Instruction, replacement Value) {
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			for i, op := range inst.Operands {
+				if op == Value(old) {
+					inst.Operands[i] = replacement
+				} else if md, ok := op.(*MetadataValue); ok && md.Value == Value(old) {
+					inst.Operands[i] = &MetadataValue{Value: replacement}
+				}
+			}
+		}
+	}
+}
+
+// removeInstructions drops the marked instructions from a function. Debug
+// intrinsics describing a removed value are updated: a declared stack slot
+// loses its declaration, and a variable value becomes undef.
+func removeInstructions(fn *Function, dead map[*Instruction]bool) {
+	for _, block := range fn.Blocks {
+		kept := block.Instructions[:0]
+		for _, inst := range block.Instructions {
+			if dead[inst] {
+				continue
+			}
+			if isDebugIntrinsic(inst) {
+				md, _ := inst.Operands[0].(*MetadataValue)
+				if removed, ok := md.Value.(*Instruction); ok && dead[removed] {
+					if inst.Callee.Name == "llvm.dbg.declare" {
+						continue
+					}
+					inst.Operands[0] = &MetadataValue{Value: &Undef{Typ: removed.Typ}}
+				}
+			}
+			kept = append(kept, inst)
+		}
+		block.Instructions = kept
+	}
+}
+
+// successors returns the blocks a block branches to
+func successors(block *BasicBlock) []*BasicBlock {
+	term := block.Terminator()
+	if term == nil {
+		return nil
+	}
+	return term.Blocks
+}
+
+// phis returns the phi instructions at the start of a block
+func phis(block *BasicBlock) []*Instruction {
+	var result []*Instruction
+	for _, inst := range block.Instructions {
+		if inst.Op != OpPhi {
+			break
+		}
+		result = append(result, inst)
+	}
+	return result
+}
+
+// removeIncoming drops the phi entries of block for edges from pred
+func removeIncoming(block, pred *BasicBlock) {
+	for _, phi := range phis(block) {
+		for i := 0; i < len(phi.Blocks); i++ {
+			if phi.Blocks[i] == pred {
+				phi.Blocks = append(phi.Blocks[:i:i], phi.Blocks[i+1:]...)
+				phi.Operands = append(phi.Operands[:i:i], phi.Operands[i+1:]...)
+				i--
+			}
+		}
+	}
+}
+
+// replaceIncoming renames the predecessor of phi entries in block
+func replaceIncoming(block, old, replacement *BasicBlock) {
+	for _, phi := range phis(block) {
+		for i, pred := range phi.Blocks {
+			if pred == old {
+				phi.Blocks[i] = replacement
+			}
+		}
+	}
+}
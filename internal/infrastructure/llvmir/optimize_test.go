@@ -0,0 +1,276 @@
+package llvmir
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+func TestPassManagerLevels(t *testing.T) {
+	tests := []struct {
+		level    int
+		expected []string
+	}{
+		{0, []string{"mem2reg"}},
+		{1, []string{"mem2reg", "fold", "simplifycfg", "dce"}},
+		{2, []string{"mem2reg", "inline", "fold", "simplifycfg", "dce"}},
+		{3, []string{"mem2reg", "inline", "fold", "simplifycfg", "dce"}},
+	}
+	for _, test := range tests {
+		if got := NewPassManager(test.level).Passes(); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("NewPassManager(%d).Passes() = %v, expected %v", test.level, got, test.expected)
+		}
+	}
+}
+
+func TestOptimizeConstants(t *testing.T) {
+	m, fn, b := newTestBuilder()
+	printInt := m.AddFunction("sl_print_int", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+
+	slot := b.CreateAlloca(I64, "x")
+	b.CreateStore(m.ConstInt(I64, 6), slot)
+	x := b.CreateLoad(slot, "temp_0")
+	product := b.CreateMul(x, m.ConstInt(I64, 7), "temp_1")
+	b.CreateAdd(product, m.ConstInt(I64, 1), "unused")
+	cond := b.CreateICmp(interfaces.IntSGT, product, m.ConstInt(I64, 40), "temp_2")
+	then := fn.CreateBasicBlock("then")
+	other := fn.CreateBasicBlock("else")
+	b.CreateCondBr(cond, then, other)
+	b.PositionAtEnd(then)
+	b.CreateCall(printInt, []interfaces.LLVMValue{product}, "")
+	b.CreateRetVoid()
+	b.PositionAtEnd(other)
+	b.CreateCall(printInt, []interfaces.LLVMValue{m.ConstInt(I64, 0)}, "")
+	b.CreateRetVoid()
+
+	if !NewPassManager(1).Run(m) {
+		t.Error("Expected the module to change")
+	}
+
+	output := printModule(m)
+	if !strings.Contains(output, "call void @sl_print_int(i64 42)") {
+		t.Errorf("Expected the product to be folded, got: %s", output)
+	}
+	for _, unexpected := range []string{"alloca", "store", "load", "mul", "%unused", "icmp", "else:", "then:"} {
+		if strings.Contains(output, unexpected) {
+			t.Errorf("Expected %q to be removed, got: %s", unexpected, output)
+		}
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+}
+
+func TestFoldDivisionByZero(t *testing.T) {
+	m, _, b := newTestBuilder()
+	b.CreateSDiv(m.ConstInt(I64, 1), m.ConstInt(I64, 0), "temp_0")
+	b.CreateRetVoid()
+
+	fn := m.Functions[0]
+	foldConstants(fn)
+	if !strings.Contains(printModule(m), "sdiv i64 1, 0") {
+		t.Error("Division by zero should be left to the target")
+	}
+}
+
+func TestFoldRemainder(t *testing.T) {
+	m, _, b := newTestBuilder()
+	printInt := m.AddFunction("sl_print_int", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+	printDouble := m.AddFunction("sl_print_double", m.FunctionType(Void, []interfaces.LLVMType{Double}, false))
+
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateBinOp(interfaces.BinarySRem, m.ConstInt(I64, -7), m.ConstInt(I64, 3), "temp_0")}, "")
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateBinOp(interfaces.BinaryURem, m.ConstInt(I64, -7), m.ConstInt(I64, 3), "temp_1")}, "")
+	b.CreateCall(printDouble, []interfaces.LLVMValue{b.CreateBinOp(interfaces.BinaryFRem, m.ConstFloat(Double, 7.5), m.ConstFloat(Double, 2), "temp_2")}, "")
+	b.CreateBinOp(interfaces.BinarySRem, m.ConstInt(I32, -2147483648), m.ConstInt(I32, -1), "temp_3")
+	b.CreateBinOp(interfaces.BinaryURem, m.ConstInt(I64, 1), m.ConstInt(I64, 0), "temp_4")
+	b.CreateRetVoid()
+
+	foldConstants(m.Functions[0])
+	output := printModule(m)
+	expected := []string{
+		"call void @sl_print_int(i64 -1)",
+		"call void @sl_print_int(i64 0)",
+		"call void @sl_print_double(double 0x3FF8000000000000)",
+		"srem i32 -2147483648, -1",
+		"urem i64 1, 0",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+func TestFoldBitwise(t *testing.T) {
+	m, _, b := newTestBuilder()
+	printInt := m.AddFunction("sl_print_int", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+	next := m.AddFunction("next", m.FunctionType(I64, nil, false))
+	// Extension folds too, so the i8 results reach the calls as i64 constants
+	printI8 := func(value interfaces.LLVMValue) {
+		b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateCast(interfaces.CastSExt, value, I64, "")}, "")
+	}
+
+	printI8(b.CreateBinOp(interfaces.BinaryAnd, m.ConstInt(I8, 12), m.ConstInt(I8, 10), "temp_0"))
+	printI8(b.CreateBinOp(interfaces.BinaryOr, m.ConstInt(I8, 12), m.ConstInt(I8, 10), "temp_1"))
+	printI8(b.CreateBinOp(interfaces.BinaryShl, m.ConstInt(I8, 1), m.ConstInt(I8, 7), "temp_2"))
+	printI8(b.CreateBinOp(interfaces.BinaryLShr, m.ConstInt(I8, -128), m.ConstInt(I8, 3), "temp_3"))
+	printI8(b.CreateBinOp(interfaces.BinaryAShr, m.ConstInt(I8, -128), m.ConstInt(I8, 3), "temp_4"))
+	b.CreateBinOp(interfaces.BinaryShl, m.ConstInt(I8, 1), m.ConstInt(I8, 8), "temp_5")
+	x := b.CreateCall(next, nil, "temp_6")
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateBinOp(interfaces.BinaryOr, x, m.ConstInt(I64, 0), "temp_7")}, "")
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateBinOp(interfaces.BinaryAnd, x, m.ConstInt(I64, 0), "temp_8")}, "")
+	b.CreateRetVoid()
+
+	foldConstants(m.Functions[0])
+	output := printModule(m)
+	expected := []string{
+		"call void @sl_print_int(i64 8)",
+		"call void @sl_print_int(i64 14)",
+		"call void @sl_print_int(i64 -128)",
+		"call void @sl_print_int(i64 16)",
+		"call void @sl_print_int(i64 -16)",
+		"shl i8 1, 8",
+		"call void @sl_print_int(i64 %temp_6)",
+		"call void @sl_print_int(i64 0)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+func TestFoldUnary(t *testing.T) {
+	m, _, b := newTestBuilder()
+	printInt := m.AddFunction("sl_print_int", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+	printDouble := m.AddFunction("sl_print_double", m.FunctionType(Void, []interfaces.LLVMType{Double}, false))
+
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateNeg(m.ConstInt(I64, 5), "temp_0")}, "")
+	b.CreateCall(printDouble, []interfaces.LLVMValue{b.CreateFNeg(m.ConstFloat(Double, 0), "temp_1")}, "")
+	flag := b.CreateNot(b.CreateNot(m.ConstInt(I1, 1), "temp_2"), "temp_3")
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateCast(interfaces.CastZExt, flag, I64, "temp_4")}, "")
+	b.CreateRetVoid()
+
+	foldConstants(m.Functions[0])
+	output := printModule(m)
+	expected := []string{
+		"call void @sl_print_int(i64 -5)",
+		"call void @sl_print_double(double 0x8000000000000000)",
+		"call void @sl_print_int(i64 1)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
+
+func TestFoldTruncOfExtension(t *testing.T) {
+	m := NewModule("test", "")
+	fn := m.AddFunction("positive", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+	b := m.CreateBuilder()
+	b.PositionAtEnd(fn.CreateBasicBlock("entry"))
+	printInt := m.AddFunction("sl_print_int", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+
+	// A bool stored as a byte and loaded back, as mem2reg leaves it
+	flag := b.CreateICmp(interfaces.IntSGT, fn.GetParameter(0), m.ConstInt(I64, 0), "temp_0")
+	stored := b.CreateCast(interfaces.CastZExt, flag, I8, "temp_1")
+	loaded := b.CreateCast(interfaces.CastTrunc, stored, I1, "temp_2")
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateCast(interfaces.CastZExt, loaded, I64, "temp_3")}, "")
+	narrowed := b.CreateCast(interfaces.CastTrunc, b.CreateCast(interfaces.CastSExt, flag, I64, "temp_4"), I8, "temp_5")
+	b.CreateCall(printInt, []interfaces.LLVMValue{b.CreateCast(interfaces.CastSExt, narrowed, I64, "temp_6")}, "")
+	b.CreateRetVoid()
+
+	foldConstants(fn.(*Function))
+	output := printModule(m)
+	if !strings.Contains(output, "%temp_3 = zext i1 %temp_0 to i64") {
+		t.Errorf("Expected the round trip through i8 to be removed, got: %s", output)
+	}
+	if !strings.Contains(output, "%temp_5 = trunc i64 %temp_4 to i8") {
+		t.Errorf("Expected truncation to another width to be kept, got: %s", output)
+	}
+}
+
+// newInlineTest returns a module whose main function calls max(a, b)
+func newInlineTest() *Module {
+	m := NewModule("test", "")
+	maxFn := m.AddFunction("max", m.FunctionType(I64, []interfaces.LLVMType{I64, I64}, false))
+	b := m.CreateBuilder()
+	b.PositionAtEnd(maxFn.CreateBasicBlock("entry"))
+	a, c := maxFn.GetParameter(0), maxFn.GetParameter(1)
+	then := maxFn.CreateBasicBlock("then")
+	end := maxFn.CreateBasicBlock("end")
+	b.CreateCondBr(b.CreateICmp(interfaces.IntSGT, a, c, "temp_0"), then, end)
+	b.PositionAtEnd(then)
+	b.CreateRet(a)
+	b.PositionAtEnd(end)
+	b.CreateRet(c)
+
+	printInt := m.AddFunction("sl_print_int", m.FunctionType(Void, []interfaces.LLVMType{I64}, false))
+	mainFn := m.AddFunction("main", m.FunctionType(I32, nil, false))
+	b.PositionAtEnd(mainFn.CreateBasicBlock("entry"))
+	slot := b.CreateAlloca(I64, "n")
+	b.CreateStore(m.ConstInt(I64, 3), slot)
+	result := b.CreateCall(maxFn, []interfaces.LLVMValue{b.CreateLoad(slot, "temp_1"), m.ConstInt(I64, 5)}, "temp_2")
+	b.CreateCall(printInt, []interfaces.LLVMValue{result}, "")
+	b.CreateRet(m.ConstInt(I32, 0))
+	return m
+}
+
+func TestInline(t *testing.T) {
+	m := newInlineTest()
+	mainFn := m.functions["main"]
+
+	in := newInliner(inlineThresholdO2)
+	if !in.run(mainFn) {
+		t.Fatal("Expected the call to max to be inlined")
+	}
+	if in.run(mainFn) {
+		t.Error("Expected nothing left to inline")
+	}
+
+	output := printModule(m)
+	expected := []string{
+		"br label %entry.i1",
+		"%temp_0.i1 = icmp sgt i64 %temp_1, 5",
+		"br i1 %temp_0.i1, label %then.i1, label %end.i1",
+		"inline.cont.i1:",
+		"%temp_2 = phi i64 [ %temp_1, %then.i1 ], [ 5, %end.i1 ]",
+		"call void @sl_print_int(i64 %temp_2)",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+	if strings.Contains(output, "call i64 @max") {
+		t.Errorf("Expected the call to be replaced, got: %s", output)
+	}
+	if err := m.Verify(); err != nil {
+		t.Errorf("Verify failed: %v", err)
+	}
+
+	// The rest of the pipeline resolves the inlined branch
+	NewPassManager(2).Run(m)
+	if output := printModule(m); !strings.Contains(output, "call void @sl_print_int(i64 5)") {
+		t.Errorf("Expected the inlined call to be folded, got: %s", output)
+	}
+}
+
+func TestInlineThreshold(t *testing.T) {
+	m := newInlineTest()
+	mainFn := m.functions["main"]
+
+	if newInliner(2).run(mainFn) {
+		t.Error("Expected callees above the threshold to be kept")
+	}
+	if newInliner(inlineThresholdO2).run(m.functions["max"]) {
+		t.Error("Expected nothing to inline into a function without calls")
+	}
+	NewPassManager(1).Run(m)
+	if !strings.Contains(printModule(m), "call i64 @max") {
+		t.Error("Expected -O1 not to inline")
+	}
+}
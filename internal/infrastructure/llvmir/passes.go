@@ -0,0 +1,505 @@
+package llvmir
+
+import (
+	"math"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// foldConstants replaces instructions whose operands are constants, and
+// arithmetic identities such as x + 0, with their result
+func foldConstants(fn *Function) bool {
+	dead := make(map[*Instruction]bool)
+	for _, block := range fn.Blocks {
+		for _, inst := range block.Instructions {
+			if result := foldInstruction(inst); result != nil {
+				replaceUses(fn, inst, result)
+				dead[inst] = true
+			}
+		}
+	}
+	removeInstructions(fn, dead)
+	return len(dead) > 0
+}
+
+// foldInstruction returns the value an instruction always produces, or nil
+func foldInstruction(inst *Instruction) Value {
+	switch inst.Op {
+	case OpAdd, OpSub, OpMul, OpSDiv, OpUDiv, OpSRem, OpURem, OpXor, OpAnd, OpOr, OpShl, OpLShr, OpAShr:
+		return foldIntBinary(inst)
+	case OpFAdd, OpFSub, OpFMul, OpFDiv, OpFRem:
+		return foldFloatBinary(inst)
+	case OpFNeg:
+		if value, ok := inst.Operands[0].(*ConstFloat); ok {
+			return constFloat(value.Typ, -value.Value)
+		}
+	case OpICmp:
+		return foldICmp(inst)
+	case OpFCmp:
+		return foldFCmp(inst)
+	case OpTrunc, OpZExt, OpSExt, OpFPTrunc, OpFPExt, OpFPToUI, OpFPToSI, OpUIToFP, OpSIToFP:
+		return foldCast(inst)
+	case OpPhi:
+		return foldPhi(inst)
+	}
+	return nil
+}
+
+// wrapInt sign-extends the low bits of a value, as an integer of that width holds it
+func wrapInt(value int64, bits int) int64 {
+	if bits >= 64 {
+		return value
+	}
+	shift := uint(64 - bits)
+	return value << shift >> shift
+}
+
+// minInt returns the smallest signed integer of the given width
+func minInt(bits int) int64 {
+	return wrapInt(1<<uint(bits-1), bits)
+}
+
+// unsignedInt returns the low bits of a value as an unsigned integer
+func unsignedInt(value int64, bits int) uint64 {
+	if bits >= 64 {
+		return uint64(value)
+	}
+	return uint64(value) & (1<<uint(bits) - 1)
+}
+
+func constBool(value bool) *ConstInt {
+	if value {
+		return &ConstInt{Typ: I1, Value: 1}
+	}
+	return &ConstInt{Typ: I1, Value: 0}
+}
+
+func foldIntBinary(inst *Instruction) Value {
+	lhs, lhsConst := inst.Operands[0].(*ConstInt)
+	rhs, rhsConst := inst.Operands[1].(*ConstInt)
+	typ, ok := inst.Typ.(*IntType)
+	if !ok {
+		return nil
+	}
+
+	// Identities that hold whatever the other operand is
+	switch {
+	case rhsConst && rhs.Value == 0 && (inst.Op == OpAdd || inst.Op == OpSub || inst.Op == OpXor || inst.Op == OpOr || isShift(inst.Op)):
+		return inst.Operands[0]
+	case lhsConst && lhs.Value == 0 && (inst.Op == OpAdd || inst.Op == OpXor || inst.Op == OpOr):
+		return inst.Operands[1]
+	case (lhsConst && lhs.Value == 0 || rhsConst && rhs.Value == 0) && inst.Op == OpAnd:
+		return &ConstInt{Typ: typ, Value: 0}
+	case rhsConst && wrapInt(rhs.Value, typ.Bits) == 1 && (inst.Op == OpMul || inst.Op == OpSDiv):
+		return inst.Operands[0]
+	case rhsConst && unsignedInt(rhs.Value, typ.Bits) == 1 && inst.Op == OpUDiv:
+		return inst.Operands[0]
+	case lhsConst && wrapInt(lhs.Value, typ.Bits) == 1 && inst.Op == OpMul:
+		return inst.Operands[1]
+	case (lhsConst && lhs.Value == 0 || rhsConst && rhs.Value == 0) && inst.Op == OpMul:
+		return &ConstInt{Typ: typ, Value: 0}
+	}
+	if !lhsConst || !rhsConst {
+		return nil
+	}
+
+	a, b := wrapInt(lhs.Value, typ.Bits), wrapInt(rhs.Value, typ.Bits)
+	var result int64
+	switch inst.Op {
+	case OpAdd:
+		result = a + b
+	case OpSub:
+		result = a - b
+	case OpMul:
+		result = a * b
+	case OpSDiv:
+		// Division by zero and overflow are left to trap at run time
+		if b == 0 || (b == -1 && a == minInt(typ.Bits)) {
+			return nil
+		}
+		result = a / b
+	case OpUDiv:
+		ua, ub := unsignedInt(a, typ.Bits), unsignedInt(b, typ.Bits)
+		if ub == 0 {
+			return nil
+		}
+		result = int64(ua / ub)
+	case OpSRem:
+		// Like sdiv, srem of the minimum value by -1 overflows
+		if b == 0 || (b == -1 && a == minInt(typ.Bits)) {
+			return nil
+		}
+		result = a % b
+	case OpURem:
+		ua, ub := unsignedInt(a, typ.Bits), unsignedInt(b, typ.Bits)
+		if ub == 0 {
+			return nil
+		}
+		result = int64(ua % ub)
+	case OpXor:
+		result = a ^ b
+	case OpAnd:
+		result = a & b
+	case OpOr:
+		result = a | b
+	case OpShl, OpLShr, OpAShr:
+		// Shifting by the width or more is poison, left for the target
+		count := unsignedInt(b, typ.Bits)
+		if count >= uint64(typ.Bits) {
+			return nil
+		}
+		switch inst.Op {
+		case OpShl:
+			result = a << count
+		case OpLShr:
+			result = int64(unsignedInt(a, typ.Bits) >> count)
+		default:
+			result = a >> count
+		}
+	}
+	return &ConstInt{Typ: typ, Value: wrapInt(result, typ.Bits)}
+}
+
+func isShift(op Opcode) bool {
+	return op == OpShl || op == OpLShr || op == OpAShr
+}
+
+func foldFloatBinary(inst *Instruction) Value {
+	lhs, ok := inst.Operands[0].(*ConstFloat)
+	if !ok {
+		return nil
+	}
+	rhs, ok := inst.Operands[1].(*ConstFloat)
+	if !ok {
+		return nil
+	}
+
+	var result float64
+	switch inst.Op {
+	case OpFAdd:
+		result = lhs.Value + rhs.Value
+	case OpFSub:
+		result = lhs.Value - rhs.Value
+	case OpFMul:
+		result = lhs.Value * rhs.Value
+	case OpFDiv:
+		result = lhs.Value / rhs.Value
+	case OpFRem:
+		result = math.Mod(lhs.Value, rhs.Value)
+	}
+	return constFloat(lhs.Typ, result)
+}
+
+// constFloat returns a float constant rounded to the precision of its type
+func constFloat(typ *FloatType, value float64) *ConstFloat {
+	if typ.Bits == 32 {
+		value = float64(float32(value))
+	}
+	return &ConstFloat{Typ: typ, Value: value}
+}
+
+func foldICmp(inst *Instruction) Value {
+	lhs, ok := inst.Operands[0].(*ConstInt)
+	if !ok {
+		return nil
+	}
+	rhs, ok := inst.Operands[1].(*ConstInt)
+	if !ok {
+		return nil
+	}
+
+	bits := lhs.Typ.Bits
+	a, b := wrapInt(lhs.Value, bits), wrapInt(rhs.Value, bits)
+	ua, ub := unsignedInt(lhs.Value, bits), unsignedInt(rhs.Value, bits)
+	switch inst.Pred {
+	case "eq":
+		return constBool(a == b)
+	case "ne":
+		return constBool(a != b)
+	case "slt":
+		return constBool(a < b)
+	case "sle":
+		return constBool(a <= b)
+	case "sgt":
+		return constBool(a > b)
+	case "sge":
+		return constBool(a >= b)
+	case "ult":
+		return constBool(ua < ub)
+	case "ule":
+		return constBool(ua <= ub)
+	case "ugt":
+		return constBool(ua > ub)
+	case "uge":
+		return constBool(ua >= ub)
+	}
+	return nil
+}
+
+func foldFCmp(inst *Instruction) Value {
+	lhs, ok := inst.Operands[0].(*ConstFloat)
+	if !ok {
+		return nil
+	}
+	rhs, ok := inst.Operands[1].(*ConstFloat)
+	if !ok {
+		return nil
+	}
+
+	a, b := lhs.Value, rhs.Value
+	ordered := !math.IsNaN(a) && !math.IsNaN(b)
+	switch inst.Pred {
+	case "oeq":
+		return constBool(ordered && a == b)
+	case "one":
+		return constBool(ordered && a != b)
+	case "olt":
+		return constBool(ordered && a < b)
+	case "ole":
+		return constBool(ordered && a <= b)
+	case "ogt":
+		return constBool(ordered && a > b)
+	case "oge":
+		return constBool(ordered && a >= b)
+	case "une":
+		return constBool(!ordered || a != b)
+	}
+	return nil
+}
+
+func foldCast(inst *Instruction) Value {
+	switch value := inst.Operands[0].(type) {
+	case *ConstInt:
+		switch to := inst.Typ.(type) {
+		case *IntType:
+			op := interfaces.CastSExt
+			if inst.Op == OpZExt {
+				op = interfaces.CastZExt
+			}
+			return &ConstInt{Typ: to, Value: resizeConstant(value, to, op)}
+		case *FloatType:
+			if inst.Op == OpUIToFP {
+				return constFloat(to, float64(unsignedInt(value.Value, value.Typ.Bits)))
+			}
+			return constFloat(to, float64(wrapInt(value.Value, value.Typ.Bits)))
+		}
+	case *ConstFloat:
+		switch to := inst.Typ.(type) {
+		case *FloatType:
+			return constFloat(to, value.Value)
+		case *IntType:
+			// Out of range conversions are poison in LLVM and are left alone
+			truncated := math.Trunc(value.Value)
+			limit := math.Ldexp(1, to.Bits-1)
+			if inst.Op == OpFPToUI {
+				if truncated < 0 || truncated >= 2*limit || to.Bits >= 64 {
+					return nil
+				}
+				return &ConstInt{Typ: to, Value: wrapInt(int64(truncated), to.Bits)}
+			}
+			if math.IsNaN(truncated) || truncated < -limit || truncated >= limit {
+				return nil
+			}
+			return &ConstInt{Typ: to, Value: int64(truncated)}
+		}
+	case *Instruction:
+		// Truncating an extended value back to its own width gives the value
+		if inst.Op == OpTrunc && (value.Op == OpZExt || value.Op == OpSExt) && Equal(value.Operands[0].Type(), inst.Typ) {
+			return value.Operands[0]
+		}
+	}
+	return nil
+}
+
+// foldPhi replaces a phi whose incoming values are all the same. The value
+// must be a constant or argument, or come from the only predecessor, so that
+// it is available where the phi is.
+func foldPhi(inst *Instruction) Value {
+	var common Value
+	for _, value := range inst.Operands {
+		if value == Value(inst) {
+			continue
+		}
+		if common != nil && !sameValue(common, value) {
+			return nil
+		}
+		common = value
+	}
+	if common == nil {
+		return nil
+	}
+	if _, isInst := common.(*Instruction); isInst && len(inst.Operands) > 1 {
+		return nil
+	}
+	return common
+}
+
+// sameValue reports whether two operands are the same value
+func sameValue(a, b Value) bool {
+	if a == b {
+		return true
+	}
+	switch x := a.(type) {
+	case *ConstInt:
+		y, ok := b.(*ConstInt)
+		return ok && x.Typ.Bits == y.Typ.Bits && wrapInt(x.Value, x.Typ.Bits) == wrapInt(y.Value, y.Typ.Bits)
+	case *ConstFloat:
+		y, ok := b.(*ConstFloat)
+		return ok && x.Typ.Bits == y.Typ.Bits && math.Float64bits(x.Value) == math.Float64bits(y.Value)
+	}
+	return false
+}
+
+// simplifyCFG turns branches on constants into unconditional branches,
+// removes blocks that can no longer be reached and merges blocks into their
+// only predecessor
+func simplifyCFG(fn *Function) bool {
+	changed := false
+
+	for _, block := range fn.Blocks {
+		term := block.Terminator()
+		if term == nil || term.Op != OpBr || len(term.Operands) == 0 {
+			continue
+		}
+		cond, ok := term.Operands[0].(*ConstInt)
+		if !ok {
+			continue
+		}
+		taken, dropped := term.Blocks[0], term.Blocks[1]
+		if cond.Value == 0 {
+			taken, dropped = dropped, taken
+		}
+		if dropped != taken {
+			removeIncoming(dropped, block)
+		}
+		term.Operands = nil
+		term.Blocks = []*BasicBlock{taken}
+		changed = true
+	}
+
+	if removeUnreachableBlocks(fn) {
+		changed = true
+	}
+	for mergeBlock(fn) {
+		changed = true
+	}
+	return changed
+}
+
+// removeUnreachableBlocks drops the blocks no path from the entry block reaches
+func removeUnreachableBlocks(fn *Function) bool {
+	reachable := map[*BasicBlock]bool{fn.Blocks[0]: true}
+	work := []*BasicBlock{fn.Blocks[0]}
+	for len(work) > 0 {
+		block := work[len(work)-1]
+		work = work[:len(work)-1]
+		for _, succ := range successors(block) {
+			if !reachable[succ] {
+				reachable[succ] = true
+				work = append(work, succ)
+			}
+		}
+	}
+	if len(reachable) == len(fn.Blocks) {
+		return false
+	}
+
+	kept := fn.Blocks[:0]
+	for _, block := range fn.Blocks {
+		if reachable[block] {
+			kept = append(kept, block)
+			continue
+		}
+		for _, succ := range successors(block) {
+			removeIncoming(succ, block)
+		}
+	}
+	fn.Blocks = kept
+	return true
+}
+
+// mergeBlock appends a block to its only predecessor when that predecessor
+// unconditionally branches to it, reporting whether a block was merged
+func mergeBlock(fn *Function) bool {
+	preds := make(map[*BasicBlock]int)
+	for _, block := range fn.Blocks {
+		for _, succ := range successors(block) {
+			preds[succ]++
+		}
+	}
+
+	for _, block := range fn.Blocks {
+		term := block.Terminator()
+		if term == nil || term.Op != OpBr || len(term.Blocks) != 1 {
+			continue
+		}
+		next := term.Blocks[0]
+		if next == block || next == fn.Blocks[0] || preds[next] != 1 {
+			continue
+		}
+
+		// Phis of a block with a single predecessor have a single value
+		dead := make(map[*Instruction]bool)
+		for _, phi := range phis(next) {
+			replaceUses(fn, phi, phi.Operands[0])
+			dead[phi] = true
+		}
+		block.Instructions = block.Instructions[:len(block.Instructions)-1]
+		for _, inst := range next.Instructions {
+			if !dead[inst] {
+				inst.Parent = block
+				block.Instructions = append(block.Instructions, inst)
+			}
+		}
+		for _, succ := range successors(block) {
+			replaceIncoming(succ, next, block)
+		}
+
+		for i, b := range fn.Blocks {
+			if b == next {
+				fn.Blocks = append(fn.Blocks[:i], fn.Blocks[i+1:]...)
+				break
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// eliminateDeadCode removes instructions without side effects whose results
+// are unused, and local variables that are only ever stored to
+func eliminateDeadCode(fn *Function) bool {
+	changed := false
+	for {
+		uses := countUses(fn)
+		dead := make(map[*Instruction]bool)
+
+		stores := make(map[*Instruction][]*Instruction)
+		for _, block := range fn.Blocks {
+			for _, inst := range block.Instructions {
+				if !hasSideEffects(inst) && uses[inst] == 0 {
+					dead[inst] = true
+				}
+				if inst.Op == OpStore {
+					if slot, ok := inst.Operands[1].(*Instruction); ok && slot.Op == OpAlloca && inst.Operands[0] != Value(slot) {
+						stores[slot] = append(stores[slot], inst)
+					}
+				}
+			}
+		}
+		for slot, slotStores := range stores {
+			if uses[slot] == len(slotStores) {
+				dead[slot] = true
+				for _, store := range slotStores {
+					dead[store] = true
+				}
+			}
+		}
+
+		if len(dead) == 0 {
+			return changed
+		}
+		removeInstructions(fn, dead)
+		changed = true
+	}
+}
@@ -0,0 +1,208 @@
+package llvmir
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// HostTriple returns the target triple of the machine the compiler runs on
+func HostTriple() string {
+	return hostTriple(runtime.GOARCH, runtime.GOOS)
+}
+
+func hostTriple(goarch, goos string) string {
+	arch := map[string]string{
+		"amd64":   "x86_64",
+		"arm64":   "aarch64",
+		"386":     "i386",
+		"riscv64": "riscv64",
+		"wasm":    "wasm32",
+	}[goarch]
+	if arch == "" {
+		arch = goarch
+	}
+
+	switch goos {
+	case "darwin":
+		if arch == "aarch64" {
+			return "arm64-apple-macosx11.0.0"
+		}
+		return arch + "-apple-macosx10.15.0"
+	case "windows":
+		return arch + "-pc-windows-msvc"
+	case "linux":
+		if arch == "x86_64" || arch == "i386" {
+			return arch + "-pc-linux-gnu"
+		}
+		return arch + "-unknown-linux-gnu"
+	case "js", "wasip1":
+		return "wasm32-unknown-unknown"
+	default:
+		return fmt.Sprintf("%s-unknown-%s", arch, goos)
+	}
+}
+
+// DataLayout returns the data layout LLVM uses for a target triple, or an
+// empty string when the architecture is not known
+func DataLayout(triple string) string {
+	arch, _, _ := strings.Cut(triple, "-")
+
+	// Symbol mangling follows the object file format of the OS
+	mangling := "e"
+	switch {
+	case strings.Contains(triple, "apple") || strings.Contains(triple, "darwin") || strings.Contains(triple, "macos"):
+		mangling = "o"
+	case strings.Contains(triple, "windows") && arch == "x86_64":
+		mangling = "w"
+	case strings.Contains(triple, "windows"):
+		mangling = "x"
+	}
+
+	switch arch {
+	case "x86_64":
+		return "e-m:" + mangling + "-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"
+	case "i386", "i686":
+		return "e-m:" + mangling + "-p:32:32-p270:32:32-p271:32:32-p272:64:64-f64:32:64-f80:32-n8:16:32-S128"
+	case "aarch64", "arm64":
+		if mangling == "o" {
+			return "e-m:o-i64:64-i128:128-n32:64-S128"
+		}
+		return "e-m:" + mangling + "-i8:8:32-i16:16:32-i64:64-i128:128-n32:64-S128"
+	case "riscv64":
+		return "e-m:e-p:64:64-i64:64-i128:128-n32:64-S128"
+	case "wasm32":
+		return "e-m:e-p:32:32-i64:64-n32:64-S128"
+	case "wasm64":
+		return "e-m:e-p:64:64-i64:64-n32:64-S128"
+	default:
+		return ""
+	}
+}
+
+// Layout describes how a target lays out values in memory: the size and
+// alignment of pointers and the ABI alignment of 64-bit scalars, which are
+// the parts that differ between the targets the compiler supports. Sizes
+// and alignments are in bytes.
+type Layout struct {
+	PointerSize  int
+	PointerAlign int
+	Int64Align   int
+	Float64Align int
+}
+
+// ParseLayout reads a layout from a data layout string. Specifications the
+// string leaves out take LLVM's defaults. An empty string, used for targets
+// that are not known, gives a 64-bit target with naturally aligned scalars.
+func ParseLayout(dataLayout string) Layout {
+	if dataLayout == "" {
+		return Layout{PointerSize: 8, PointerAlign: 8, Int64Align: 8, Float64Align: 8}
+	}
+
+	layout := Layout{PointerSize: 8, PointerAlign: 8, Int64Align: 4, Float64Align: 8}
+	for _, spec := range strings.Split(dataLayout, "-") {
+		fields := strings.Split(spec, ":")
+		if len(fields) < 2 {
+			continue
+		}
+		bits := make([]int, len(fields)-1)
+		for i, field := range fields[1:] {
+			bits[i], _ = strconv.Atoi(field)
+		}
+		switch fields[0] {
+		case "p", "p0":
+			layout.PointerSize = bits[0] / 8
+			if len(bits) > 1 {
+				layout.PointerAlign = bits[1] / 8
+			}
+		case "i64":
+			layout.Int64Align = bits[0] / 8
+		case "f64":
+			layout.Float64Align = bits[0] / 8
+		}
+	}
+	return layout
+}
+
+// Align returns the ABI alignment of a type
+func (l Layout) Align(t Type) int {
+	switch typ := t.(type) {
+	case *IntType:
+		switch {
+		case typ.Bits <= 8:
+			return 1
+		case typ.Bits == 64:
+			return l.Int64Align
+		}
+		return typ.Bits / 8
+	case *FloatType:
+		if typ.Bits == 64 {
+			return l.Float64Align
+		}
+		return typ.Bits / 8
+	case *PointerType:
+		return l.PointerAlign
+	case *ArrayType:
+		return l.Align(typ.Elem)
+	case *StructType:
+		// A struct is aligned to its most strictly aligned field
+		align := 1
+		for _, field := range typ.Fields {
+			if fieldAlign := l.Align(field); fieldAlign > align {
+				align = fieldAlign
+			}
+		}
+		return align
+	default:
+		return 1
+	}
+}
+
+// Size returns the number of bytes a value of a type occupies in memory,
+// including the padding that keeps consecutive values aligned
+func (l Layout) Size(t Type) int {
+	switch typ := t.(type) {
+	case *IntType:
+		size := 1
+		for size*8 < typ.Bits {
+			size *= 2
+		}
+		return size
+	case *FloatType:
+		return typ.Bits / 8
+	case *PointerType:
+		return l.PointerSize
+	case *ArrayType:
+		return typ.Len * l.Size(typ.Elem)
+	case *StructType:
+		offsets := l.FieldOffsets(typ)
+		size := 0
+		if len(offsets) > 0 {
+			size = offsets[len(offsets)-1] + l.Size(typ.Fields[len(typ.Fields)-1])
+		}
+		return alignTo(size, l.Align(typ))
+	default:
+		return 0
+	}
+}
+
+// FieldOffsets returns the byte offset of each field of a struct
+func (l Layout) FieldOffsets(t *StructType) []int {
+	offsets := make([]int, len(t.Fields))
+	offset := 0
+	for i, field := range t.Fields {
+		offset = alignTo(offset, l.Align(field))
+		offsets[i] = offset
+		offset += l.Size(field)
+	}
+	return offsets
+}
+
+// alignTo rounds offset up to a multiple of align
+func alignTo(offset, align int) int {
+	if align <= 1 {
+		return offset
+	}
+	return (offset + align - 1) / align * align
+}
@@ -0,0 +1,111 @@
+package llvmir
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+func TestHostTriple(t *testing.T) {
+	tests := []struct {
+		goarch, goos string
+		expected     string
+	}{
+		{"amd64", "linux", "x86_64-pc-linux-gnu"},
+		{"arm64", "linux", "aarch64-unknown-linux-gnu"},
+		{"amd64", "darwin", "x86_64-apple-macosx10.15.0"},
+		{"arm64", "darwin", "arm64-apple-macosx11.0.0"},
+		{"amd64", "windows", "x86_64-pc-windows-msvc"},
+		{"wasm", "wasip1", "wasm32-unknown-unknown"},
+	}
+
+	for _, test := range tests {
+		if got := hostTriple(test.goarch, test.goos); got != test.expected {
+			t.Errorf("hostTriple(%s, %s) = %s, expected %s", test.goarch, test.goos, got, test.expected)
+		}
+	}
+	if HostTriple() == "" {
+		t.Error("HostTriple should not be empty")
+	}
+}
+
+func TestDataLayout(t *testing.T) {
+	tests := []struct {
+		triple   string
+		expected string
+	}{
+		{"x86_64-pc-linux-gnu", "e-m:e-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"},
+		{"x86_64-apple-macosx10.15.0", "e-m:o-p270:32:32-p271:32:32-p272:64:64-i64:64-f80:128-n8:16:32:64-S128"},
+		{"aarch64-unknown-linux-gnu", "e-m:e-i8:8:32-i16:16:32-i64:64-i128:128-n32:64-S128"},
+		{"arm64-apple-macosx11.0.0", "e-m:o-i64:64-i128:128-n32:64-S128"},
+		{"wasm32-unknown-unknown", "e-m:e-p:32:32-i64:64-n32:64-S128"},
+		{"sparc-sun-solaris", ""},
+	}
+
+	for _, test := range tests {
+		if got := DataLayout(test.triple); got != test.expected {
+			t.Errorf("DataLayout(%s) = %q, expected %q", test.triple, got, test.expected)
+		}
+	}
+
+	// Unknown targets leave the layout to LLVM's default for the triple
+	output := printModule(NewModule("test", "sparc-sun-solaris"))
+	if strings.Contains(output, "target datalayout") || !strings.Contains(output, `target triple = "sparc-sun-solaris"`) {
+		t.Errorf("Expected only the target triple for an unknown target, got: %s", output)
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	// The fields of struct { i32, ptr, i64, double }
+	record := &StructType{Fields: []Type{I32, Ptr, I64, Double}}
+	tests := []struct {
+		triple  string
+		layout  Layout
+		offsets []int
+		size    int
+	}{
+		{"x86_64-pc-linux-gnu", Layout{PointerSize: 8, PointerAlign: 8, Int64Align: 8, Float64Align: 8}, []int{0, 8, 16, 24}, 32},
+		{"i686-pc-linux-gnu", Layout{PointerSize: 4, PointerAlign: 4, Int64Align: 4, Float64Align: 4}, []int{0, 4, 8, 16}, 24},
+		{"wasm32-unknown-unknown", Layout{PointerSize: 4, PointerAlign: 4, Int64Align: 8, Float64Align: 8}, []int{0, 4, 8, 16}, 24},
+		{"sparc-sun-solaris", Layout{PointerSize: 8, PointerAlign: 8, Int64Align: 8, Float64Align: 8}, []int{0, 8, 16, 24}, 32},
+	}
+
+	for _, test := range tests {
+		layout := ParseLayout(DataLayout(test.triple))
+		if layout != test.layout {
+			t.Errorf("ParseLayout(%s) = %+v, expected %+v", test.triple, layout, test.layout)
+		}
+		if offsets := layout.FieldOffsets(record); fmt.Sprint(offsets) != fmt.Sprint(test.offsets) {
+			t.Errorf("%s: field offsets %v, expected %v", test.triple, offsets, test.offsets)
+		}
+		if size := layout.Size(record); size != test.size {
+			t.Errorf("%s: struct size %d, expected %d", test.triple, size, test.size)
+		}
+	}
+}
+
+func TestTargetAlignment(t *testing.T) {
+	m := NewModule("test", "wasm32-unknown-unknown")
+	fn := m.AddFunction("test", m.FunctionType(Void, nil, false))
+	b := m.CreateBuilder()
+	b.PositionAtEnd(fn.CreateBasicBlock("entry"))
+	record := &StructType{Fields: []Type{I32, Ptr, I64}}
+	slot := b.CreateAlloca(record, "r")
+	field := b.CreateGEP(slot, []interfaces.LLVMValue{m.ConstInt(I32, 0), m.ConstInt(I32, 1)}, "temp_0")
+	b.CreateStore(b.CreateTypedLoad(Ptr, field, "temp_1"), field)
+	b.CreateRetVoid()
+
+	output := printModule(m)
+	expected := []string{
+		"%r = alloca { i32, ptr, i64 }, align 8",
+		"%temp_1 = load ptr, ptr %temp_0, align 4",
+		"store ptr %temp_1, ptr %temp_0, align 4",
+	}
+	for _, e := range expected {
+		if !strings.Contains(output, e) {
+			t.Errorf("Expected %q in output, got: %s", e, output)
+		}
+	}
+}
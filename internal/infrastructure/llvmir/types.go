@@ -0,0 +1,234 @@
+// Package llvmir is a pure-Go, in-memory representation of LLVM IR. It
+// implements the LLVM interfaces of the compiler and prints modules as
+// textual IR that llc and clang accept, so no LLVM libraries are needed to
+// generate code.
+package llvmir
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// Type is an LLVM type
+type Type interface {
+	interfaces.LLVMType
+
+	// String returns the type as written in textual IR
+	String() string
+}
+
+// IntType is an integer type of a fixed bit width
+type IntType struct {
+	Bits int
+}
+
+// FloatType is a 32-bit (float) or 64-bit (double) floating-point type
+type FloatType struct {
+	Bits int
+}
+
+// VoidType is the result type of functions that return nothing
+type VoidType struct{}
+
+// PointerType is an opaque pointer, printed as ptr. Elem records what it
+// points to for typing loads and GEPs; it takes no part in the printed IR.
+type PointerType struct {
+	Elem Type
+}
+
+// ArrayType is a fixed-length array
+type ArrayType struct {
+	Len  int
+	Elem Type
+}
+
+// StructType is a literal struct, or a named struct when Name is set.
+// A named struct without a body is opaque.
+type StructType struct {
+	Name   string
+	Fields []Type
+	Opaque bool
+}
+
+// MetadataType is the type of metadata arguments to intrinsics
+type MetadataType struct{}
+
+// FunctionType is the signature of a function
+type FunctionType struct {
+	Result   Type
+	Params   []Type
+	Variadic bool
+}
+
+// Commonly used types
+var (
+	I1     = &IntType{Bits: 1}
+	I8     = &IntType{Bits: 8}
+	I16    = &IntType{Bits: 16}
+	I32    = &IntType{Bits: 32}
+	I64    = &IntType{Bits: 64}
+	Float  = &FloatType{Bits: 32}
+	Double = &FloatType{Bits: 64}
+	Void   = &VoidType{}
+	Ptr    = &PointerType{}
+	MD     = &MetadataType{}
+)
+
+func (t *IntType) String() string  { return fmt.Sprintf("i%d", t.Bits) }
+func (t *IntType) IsInteger() bool { return true }
+func (t *IntType) IsFloat() bool   { return false }
+func (t *IntType) IsPointer() bool { return false }
+func (t *IntType) IsStruct() bool  { return false }
+
+func (t *FloatType) String() string {
+	if t.Bits == 32 {
+		return "float"
+	}
+	return "double"
+}
+func (t *FloatType) IsInteger() bool { return false }
+func (t *FloatType) IsFloat() bool   { return true }
+func (t *FloatType) IsPointer() bool { return false }
+func (t *FloatType) IsStruct() bool  { return false }
+
+func (t *VoidType) String() string  { return "void" }
+func (t *VoidType) IsInteger() bool { return false }
+func (t *VoidType) IsFloat() bool   { return false }
+func (t *VoidType) IsPointer() bool { return false }
+func (t *VoidType) IsStruct() bool  { return false }
+
+func (t *MetadataType) String() string  { return "metadata" }
+func (t *MetadataType) IsInteger() bool { return false }
+func (t *MetadataType) IsFloat() bool   { return false }
+func (t *MetadataType) IsPointer() bool { return false }
+func (t *MetadataType) IsStruct() bool  { return false }
+
+func (t *PointerType) String() string  { return "ptr" }
+func (t *PointerType) IsInteger() bool { return false }
+func (t *PointerType) IsFloat() bool   { return false }
+func (t *PointerType) IsPointer() bool { return true }
+func (t *PointerType) IsStruct() bool  { return false }
+
+func (t *ArrayType) String() string  { return fmt.Sprintf("[%d x %s]", t.Len, t.Elem) }
+func (t *ArrayType) IsInteger() bool { return false }
+func (t *ArrayType) IsFloat() bool   { return false }
+func (t *ArrayType) IsPointer() bool { return false }
+func (t *ArrayType) IsStruct() bool  { return false }
+
+func (t *StructType) String() string {
+	if t.Name != "" {
+		return "%" + t.Name
+	}
+	return t.body()
+}
+
+// body returns the field list of a struct as written in a type definition
+func (t *StructType) body() string {
+	if len(t.Fields) == 0 {
+		return "{}"
+	}
+	fields := make([]string, len(t.Fields))
+	for i, field := range t.Fields {
+		fields[i] = field.String()
+	}
+	return "{ " + strings.Join(fields, ", ") + " }"
+}
+func (t *StructType) IsInteger() bool { return false }
+func (t *StructType) IsFloat() bool   { return false }
+func (t *StructType) IsPointer() bool { return false }
+func (t *StructType) IsStruct() bool  { return true }
+
+func (t *FunctionType) String() string {
+	return fmt.Sprintf("%s (%s)", t.Result, t.paramList())
+}
+
+// paramList returns the parameter types of a signature, including the
+// trailing ... of variadic functions
+func (t *FunctionType) paramList() string {
+	params := make([]string, 0, len(t.Params)+1)
+	for _, param := range t.Params {
+		params = append(params, param.String())
+	}
+	if t.Variadic {
+		params = append(params, "...")
+	}
+	return strings.Join(params, ", ")
+}
+func (t *FunctionType) IsInteger() bool { return false }
+func (t *FunctionType) IsFloat() bool   { return false }
+func (t *FunctionType) IsPointer() bool { return false }
+func (t *FunctionType) IsStruct() bool  { return false }
+
+// Equal reports whether two types are the same. Pointers are all equal, as
+// under opaque pointers the pointee type does not take part in type identity.
+func Equal(a, b Type) bool {
+	switch x := a.(type) {
+	case *IntType:
+		y, ok := b.(*IntType)
+		return ok && x.Bits == y.Bits
+	case *FloatType:
+		y, ok := b.(*FloatType)
+		return ok && x.Bits == y.Bits
+	case *VoidType:
+		_, ok := b.(*VoidType)
+		return ok
+	case *MetadataType:
+		_, ok := b.(*MetadataType)
+		return ok
+	case *PointerType:
+		_, ok := b.(*PointerType)
+		return ok
+	case *ArrayType:
+		y, ok := b.(*ArrayType)
+		return ok && x.Len == y.Len && Equal(x.Elem, y.Elem)
+	case *StructType:
+		y, ok := b.(*StructType)
+		if !ok {
+			return false
+		}
+		if x.Name != "" || y.Name != "" {
+			return x.Name == y.Name
+		}
+		if len(x.Fields) != len(y.Fields) {
+			return false
+		}
+		for i := range x.Fields {
+			if !Equal(x.Fields[i], y.Fields[i]) {
+				return false
+			}
+		}
+		return true
+	case *FunctionType:
+		y, ok := b.(*FunctionType)
+		if !ok || x.Variadic != y.Variadic || len(x.Params) != len(y.Params) || !Equal(x.Result, y.Result) {
+			return false
+		}
+		for i := range x.Params {
+			if !Equal(x.Params[i], y.Params[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// asType converts an interface type created by this package
+func asType(t interfaces.LLVMType) Type {
+	typ, ok := t.(Type)
+	if !ok {
+		panic(fmt.Sprintf("llvmir: foreign type %T", t))
+	}
+	return typ
+}
+
+// asTypes converts a list of interface types created by this package
+func asTypes(ts []interfaces.LLVMType) []Type {
+	types := make([]Type, len(ts))
+	for i, t := range ts {
+		types[i] = asType(t)
+	}
+	return types
+}
@@ -0,0 +1,254 @@
+package llvmir
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// Value is an LLVM value that can be used as an instruction operand
+type Value interface {
+	interfaces.LLVMValue
+
+	// Type returns the type of the value
+	Type() Type
+
+	// Ident returns the value as written in an operand position
+	Ident() string
+}
+
+// ConstInt is an integer constant
+type ConstInt struct {
+	Typ   *IntType
+	Value int64
+}
+
+// ConstFloat is a floating-point constant
+type ConstFloat struct {
+	Typ   *FloatType
+	Value float64
+}
+
+// ConstNull is the null pointer
+type ConstNull struct {
+	Typ *PointerType
+}
+
+// ConstZero is the all-zero value of a type, written zeroinitializer
+type ConstZero struct {
+	Typ Type
+}
+
+// ConstAggregate is a constant array or struct of constant elements
+type ConstAggregate struct {
+	Typ   Type
+	Elems []Value
+}
+
+// Undef is a value of a type that was never defined, such as a variable
+// read before it is assigned
+type Undef struct {
+	Typ Type
+}
+
+// ConstString is a NUL-terminated byte array initializer
+type ConstString struct {
+	Value string
+}
+
+// ConstGEP is a constant inbounds getelementptr expression
+type ConstGEP struct {
+	Elem    Type
+	Base    Value
+	Indices []Value
+}
+
+// ConstSizeOf is the allocation size of a type, computed by LLVM as the
+// offset of the second element of a null array. Typ is the pointer-sized
+// integer type of the target.
+type ConstSizeOf struct {
+	Elem Type
+	Typ  *IntType
+}
+
+// Param is a function parameter
+type Param struct {
+	Name string
+	Typ  Type
+}
+
+// Global is a module-level variable or constant
+type Global struct {
+	Name        string
+	ValueType   Type
+	Init        Value
+	Constant    bool
+	Private     bool
+	UnnamedAddr bool
+}
+
+func (c *ConstInt) GetType() interfaces.LLVMType { return c.Typ }
+func (c *ConstInt) SetName(string)               {}
+func (c *ConstInt) GetName() string              { return c.Ident() }
+func (c *ConstInt) Type() Type                   { return c.Typ }
+func (c *ConstInt) Ident() string {
+	if c.Typ.Bits == 1 {
+		if c.Value != 0 {
+			return "true"
+		}
+		return "false"
+	}
+	return fmt.Sprintf("%d", c.Value)
+}
+
+func (c *ConstFloat) GetType() interfaces.LLVMType { return c.Typ }
+func (c *ConstFloat) SetName(string)               {}
+func (c *ConstFloat) GetName() string              { return c.Ident() }
+func (c *ConstFloat) Type() Type                   { return c.Typ }
+
+// Ident prints the constant in hexadecimal. The form is exact, which LLVM
+// requires for float constants that are not representable in decimal.
+func (c *ConstFloat) Ident() string {
+	value := c.Value
+	if c.Typ.Bits == 32 {
+		value = float64(float32(value))
+	}
+	return fmt.Sprintf("0x%016X", math.Float64bits(value))
+}
+
+func (c *ConstNull) GetType() interfaces.LLVMType { return c.Typ }
+func (c *ConstNull) SetName(string)               {}
+func (c *ConstNull) GetName() string              { return "null" }
+func (c *ConstNull) Type() Type                   { return c.Typ }
+func (c *ConstNull) Ident() string                { return "null" }
+
+func (c *ConstZero) GetType() interfaces.LLVMType { return c.Typ }
+func (c *ConstZero) SetName(string)               {}
+func (c *ConstZero) GetName() string              { return "zeroinitializer" }
+func (c *ConstZero) Type() Type                   { return c.Typ }
+func (c *ConstZero) Ident() string                { return "zeroinitializer" }
+
+func (c *ConstAggregate) GetType() interfaces.LLVMType { return c.Typ }
+func (c *ConstAggregate) SetName(string)               {}
+func (c *ConstAggregate) GetName() string              { return c.Ident() }
+func (c *ConstAggregate) Type() Type                   { return c.Typ }
+func (c *ConstAggregate) Ident() string {
+	elems := make([]string, len(c.Elems))
+	for i, elem := range c.Elems {
+		elems[i] = operand(elem)
+	}
+	if _, ok := c.Typ.(*ArrayType); ok {
+		return "[" + strings.Join(elems, ", ") + "]"
+	}
+	return "{ " + strings.Join(elems, ", ") + " }"
+}
+
+func (u *Undef) GetType() interfaces.LLVMType { return u.Typ }
+func (u *Undef) SetName(string)               {}
+func (u *Undef) GetName() string              { return "undef" }
+func (u *Undef) Type() Type                   { return u.Typ }
+func (u *Undef) Ident() string                { return "undef" }
+
+func (c *ConstString) GetType() interfaces.LLVMType { return c.Type() }
+func (c *ConstString) SetName(string)               {}
+func (c *ConstString) GetName() string              { return c.Ident() }
+func (c *ConstString) Type() Type                   { return &ArrayType{Len: len(c.Value) + 1, Elem: I8} }
+func (c *ConstString) Ident() string                { return fmt.Sprintf("c\"%s\\00\"", escapeString(c.Value)) }
+
+func (c *ConstGEP) GetType() interfaces.LLVMType { return Ptr }
+func (c *ConstGEP) SetName(string)               {}
+func (c *ConstGEP) GetName() string              { return c.Ident() }
+func (c *ConstGEP) Type() Type                   { return Ptr }
+func (c *ConstGEP) Ident() string {
+	operands := []string{c.Elem.String(), operand(c.Base)}
+	for _, index := range c.Indices {
+		operands = append(operands, operand(index))
+	}
+	return fmt.Sprintf("getelementptr inbounds (%s)", strings.Join(operands, ", "))
+}
+
+func (c *ConstSizeOf) GetType() interfaces.LLVMType { return c.Typ }
+func (c *ConstSizeOf) SetName(string)               {}
+func (c *ConstSizeOf) GetName() string              { return c.Ident() }
+func (c *ConstSizeOf) Type() Type                   { return c.Typ }
+func (c *ConstSizeOf) Ident() string {
+	return fmt.Sprintf("ptrtoint (ptr getelementptr (%s, ptr null, i32 1) to %s)", c.Elem, c.Typ)
+}
+
+func (p *Param) GetType() interfaces.LLVMType { return p.Typ }
+func (p *Param) SetName(name string)          { p.Name = name }
+func (p *Param) GetName() string              { return p.Name }
+func (p *Param) Type() Type                   { return p.Typ }
+func (p *Param) Ident() string                { return "%" + p.Name }
+
+func (g *Global) GetType() interfaces.LLVMType { return Ptr }
+func (g *Global) SetName(name string)          { g.Name = name }
+func (g *Global) GetName() string              { return g.Name }
+func (g *Global) Type() Type                   { return Ptr }
+func (g *Global) Ident() string                { return "@" + g.Name }
+
+// definition returns the global as defined in textual IR for a target layout
+func (g *Global) definition(layout Layout) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "@%s = ", g.Name)
+	if g.Private {
+		sb.WriteString("private ")
+	}
+	if g.UnnamedAddr {
+		sb.WriteString("unnamed_addr ")
+	}
+	if g.Constant {
+		sb.WriteString("constant ")
+	} else {
+		sb.WriteString("global ")
+	}
+	init := "zeroinitializer"
+	if g.Init != nil {
+		init = g.Init.Ident()
+	} else if _, ok := g.ValueType.(*PointerType); ok {
+		init = "null"
+	} else if _, ok := g.ValueType.(*IntType); ok {
+		init = "0"
+	}
+	fmt.Fprintf(&sb, "%s %s, align %d", g.ValueType, init, layout.Align(g.ValueType))
+	return sb.String()
+}
+
+// operand returns a value with its type, as written in an operand list
+func operand(v Value) string {
+	return v.Type().String() + " " + v.Ident()
+}
+
+// asValue converts an interface value created by this package
+func asValue(v interfaces.LLVMValue) Value {
+	value, ok := v.(Value)
+	if !ok {
+		panic(fmt.Sprintf("llvmir: foreign value %T", v))
+	}
+	return value
+}
+
+// asValues converts a list of interface values created by this package
+func asValues(vs []interfaces.LLVMValue) []Value {
+	values := make([]Value, len(vs))
+	for i, v := range vs {
+		values[i] = asValue(v)
+	}
+	return values
+}
+
+// escapeString escapes a string for use in an LLVM IR c"..." constant
+func escapeString(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c >= 0x7f || c == '"' || c == '\\' {
+			sb.WriteString(fmt.Sprintf("\\%02X", c))
+		} else {
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
@@ -35,6 +35,9 @@ const (
 	TokenReturn
 	TokenTrue
 	TokenFalse
+	TokenBreak
+	TokenContinue
+	TokenConst
 
 	// Operators
 	TokenPlus
@@ -51,7 +54,21 @@ const (
 	TokenAnd
 	TokenOr
 	TokenNot
+	TokenAmpersand
+	TokenPipe
+	TokenCaret
+	TokenTilde
+	TokenShiftLeft
+	TokenShiftRight
 	TokenAssign
+	TokenDefine
+	TokenPlusAssign
+	TokenMinusAssign
+	TokenStarAssign
+	TokenSlashAssign
+	TokenPercentAssign
+	TokenIncrement
+	TokenDecrement
 
 	// Delimiters
 	TokenLeftParen
@@ -108,6 +125,12 @@ const (
 			return "For"
 		case TokenReturn:
 			return "Return"
+		case TokenBreak:
+			return "Break"
+		case TokenContinue:
+			return "Continue"
+		case TokenConst:
+			return "Const"
 		case TokenPlus:
 			return "Plus"
 		case TokenMinus:
@@ -136,8 +159,36 @@ const (
 			return "Or"
 		case TokenNot:
 			return "Not"
+		case TokenAmpersand:
+			return "Ampersand"
+		case TokenPipe:
+			return "Pipe"
+		case TokenCaret:
+			return "Caret"
+		case TokenTilde:
+			return "Tilde"
+		case TokenShiftLeft:
+			return "ShiftLeft"
+		case TokenShiftRight:
+			return "ShiftRight"
 		case TokenAssign:
 			return "Assign"
+		case TokenDefine:
+			return "Define"
+		case TokenPlusAssign:
+			return "PlusAssign"
+		case TokenMinusAssign:
+			return "MinusAssign"
+		case TokenStarAssign:
+			return "StarAssign"
+		case TokenSlashAssign:
+			return "SlashAssign"
+		case TokenPercentAssign:
+			return "PercentAssign"
+		case TokenIncrement:
+			return "Increment"
+		case TokenDecrement:
+			return "Decrement"
 		case TokenLeftParen:
 			return "LeftParen"
 		case TokenRightParen:
@@ -224,6 +275,8 @@ type CodeGenOptions struct {
 	OptimizationLevel int
 	DebugInfo         bool
 	TargetTriple      string
+	BoundsChecks      bool
+	DivisionChecks    bool
 }
 
 // Symbol represents a symbol in the symbol table
@@ -243,6 +296,7 @@ const (
 	ParameterSymbol
 	StructSymbol
 	FieldSymbol
+	ConstantSymbol
 )
 
 // Scope represents a lexical scope
@@ -354,6 +408,81 @@ type LLVMModule interface {
 	// GetFunction gets a function by name
 	GetFunction(name string) (LLVMFunction, bool)
 
+	// AddFunction adds a function of the given function type. A function
+	// without basic blocks is emitted as a declaration.
+	AddFunction(name string, funcType LLVMType) LLVMFunction
+
+	// AddGlobal adds a global variable, zero-initialized when init is nil
+	AddGlobal(name string, t LLVMType, init LLVMValue) LLVMValue
+
+	// AddStringConstant adds a private constant holding a NUL-terminated string
+	AddStringConstant(name, value string) LLVMValue
+
+	// CreateBuilder creates an instruction builder for the module
+	CreateBuilder() LLVMBuilder
+
+	// CreateDIBuilder creates a debug information builder for the module
+	CreateDIBuilder() LLVMDIBuilder
+
+	// IntType returns the integer type of the given bit width
+	IntType(bits int) LLVMType
+
+	// FloatType returns the 32-bit floating-point type
+	FloatType() LLVMType
+
+	// DoubleType returns the 64-bit floating-point type
+	DoubleType() LLVMType
+
+	// VoidType returns the void type
+	VoidType() LLVMType
+
+	// PointerType returns a pointer to elem, which may be nil. Pointers print
+	// as the opaque ptr type; elem only types loads through them.
+	PointerType(elem LLVMType) LLVMType
+
+	// ArrayType returns a fixed-length array type
+	ArrayType(elem LLVMType, length int) LLVMType
+
+	// StructType returns a literal struct type
+	StructType(fields []LLVMType) LLVMType
+
+	// NamedStructType returns the named struct type, creating an opaque one if needed
+	NamedStructType(name string) LLVMType
+
+	// SetStructBody sets the fields of a named struct type
+	SetStructBody(structType LLVMType, fields []LLVMType)
+
+	// FunctionType returns a function type
+	FunctionType(result LLVMType, params []LLVMType, variadic bool) LLVMType
+
+	// ConstInt returns an integer constant
+	ConstInt(t LLVMType, value int64) LLVMValue
+
+	// ConstFloat returns a floating-point constant
+	ConstFloat(t LLVMType, value float64) LLVMValue
+
+	// ConstNull returns the null value of a pointer type
+	ConstNull(t LLVMType) LLVMValue
+
+	// ConstZero returns the all-zero value of a type
+	ConstZero(t LLVMType) LLVMValue
+
+	// ConstAggregate returns a constant array or struct of type t
+	ConstAggregate(t LLVMType, elems []LLVMValue) LLVMValue
+
+	// ConstGEP returns a constant inbounds getelementptr expression
+	ConstGEP(t LLVMType, ptr LLVMValue, indices []LLVMValue) LLVMValue
+
+	// ConstSizeOf returns the allocation size of a type as a constant of the
+	// target's pointer-sized integer type, C's size_t
+	ConstSizeOf(t LLVMType) LLVMValue
+
+	// SizeOf returns the allocation size of a type in bytes on the module's target
+	SizeOf(t LLVMType) int
+
+	// AlignOf returns the ABI alignment of a type in bytes on the module's target
+	AlignOf(t LLVMType) int
+
 	// Verify verifies the module
 	Verify() error
 
@@ -429,6 +558,9 @@ type LLVMBuilder interface {
 	// CreateLoad creates a load instruction
 	CreateLoad(ptr LLVMValue, name string) LLVMValue
 
+	// CreateTypedLoad creates a load of a value of type t
+	CreateTypedLoad(t LLVMType, ptr LLVMValue, name string) LLVMValue
+
 	// CreateAdd creates an add instruction
 	CreateAdd(lhs, rhs LLVMValue, name string) LLVMValue
 
@@ -441,6 +573,21 @@ type LLVMBuilder interface {
 	// CreateSDiv creates a signed division instruction
 	CreateSDiv(lhs, rhs LLVMValue, name string) LLVMValue
 
+	// CreateBinOp creates an arithmetic instruction
+	CreateBinOp(op BinaryOpcode, lhs, rhs LLVMValue, name string) LLVMValue
+
+	// CreateNeg creates an integer negation
+	CreateNeg(value LLVMValue, name string) LLVMValue
+
+	// CreateFNeg creates a floating-point negation
+	CreateFNeg(value LLVMValue, name string) LLVMValue
+
+	// CreateNot creates a bitwise complement, which is logical not for i1
+	CreateNot(value LLVMValue, name string) LLVMValue
+
+	// CreateCast creates a conversion of value to type t
+	CreateCast(op CastOpcode, value LLVMValue, t LLVMType, name string) LLVMValue
+
 	// CreateICmp creates an integer comparison
 	CreateICmp(pred IntPredicate, lhs, rhs LLVMValue, name string) LLVMValue
 
@@ -459,12 +606,31 @@ type LLVMBuilder interface {
 	// CreateRetVoid creates a void return instruction
 	CreateRetVoid() LLVMValue
 
+	// CreateUnreachable creates an unreachable instruction
+	CreateUnreachable() LLVMValue
+
+	// CreatePhi creates a phi node merging one value per predecessor block
+	CreatePhi(t LLVMType, values []LLVMValue, blocks []LLVMBasicBlock, name string) LLVMValue
+
 	// CreateCall creates a function call
 	CreateCall(fn LLVMFunction, args []LLVMValue, name string) LLVMValue
 
 	// CreateGEP creates a getelementptr instruction
 	CreateGEP(ptr LLVMValue, indices []LLVMValue, name string) LLVMValue
 
+	// CreateInBoundsGEP creates an inbounds getelementptr into a value of type t
+	CreateInBoundsGEP(t LLVMType, ptr LLVMValue, indices []LLVMValue, name string) LLVMValue
+
+	// CreateExtractValue creates an extractvalue of an aggregate member
+	CreateExtractValue(aggregate LLVMValue, index int, name string) LLVMValue
+
+	// GetInsertBlock gets the block the builder appends to
+	GetInsertBlock() LLVMBasicBlock
+
+	// SetCurrentDebugLocation attaches a DILocation to the instructions
+	// created next; nil stops attaching locations
+	SetCurrentDebugLocation(location LLVMMetadata)
+
 	// Dispose disposes of the builder
 	Dispose()
 }
@@ -479,6 +645,10 @@ const (
 	IntSLE                     // signed less or equal
 	IntSGT                     // signed greater than
 	IntSGE                     // signed greater or equal
+	IntULT                     // unsigned less than
+	IntULE                     // unsigned less or equal
+	IntUGT                     // unsigned greater than
+	IntUGE                     // unsigned greater or equal
 )
 
 // FloatPredicate represents float comparison predicates
@@ -491,4 +661,114 @@ const (
 	FloatOLE                       // ordered and less than or equal
 	FloatOGT                       // ordered and greater than
 	FloatOGE                       // ordered and greater than or equal
+	FloatUNE                       // unordered or not equal
 )
+
+// BinaryOpcode represents arithmetic instructions
+type BinaryOpcode int
+
+const (
+	BinaryAdd  BinaryOpcode = iota // integer addition
+	BinarySub                      // integer subtraction
+	BinaryMul                      // integer multiplication
+	BinarySDiv                     // signed integer division
+	BinaryUDiv                     // unsigned integer division
+	BinaryFAdd                     // floating-point addition
+	BinaryFSub                     // floating-point subtraction
+	BinaryFMul                     // floating-point multiplication
+	BinaryFDiv                     // floating-point division
+	BinaryXor                      // bitwise exclusive or
+	BinarySRem                     // signed integer remainder
+	BinaryURem                     // unsigned integer remainder
+	BinaryFRem                     // floating-point remainder
+	BinaryAnd                      // bitwise and
+	BinaryOr                       // bitwise or
+	BinaryShl                      // shift left
+	BinaryLShr                     // logical shift right
+	BinaryAShr                     // arithmetic shift right
+)
+
+// CastOpcode represents conversion instructions
+type CastOpcode int
+
+const (
+	CastTrunc   CastOpcode = iota // integer truncation
+	CastZExt                      // integer zero extension
+	CastSExt                      // integer sign extension
+	CastFPTrunc                   // floating-point truncation
+	CastFPExt                     // floating-point extension
+	CastFPToUI                    // floating-point to unsigned integer
+	CastFPToSI                    // floating-point to signed integer
+	CastUIToFP                    // unsigned integer to floating-point
+	CastSIToFP                    // signed integer to floating-point
+)
+
+// LLVMMetadata represents a debug information metadata node
+type LLVMMetadata interface{}
+
+// DWARFEncoding is the DW_ATE encoding of a basic debug type
+type DWARFEncoding int
+
+const (
+	DWARFBoolean    DWARFEncoding = 0x02 // DW_ATE_boolean
+	DWARFFloat      DWARFEncoding = 0x04 // DW_ATE_float
+	DWARFSigned     DWARFEncoding = 0x05 // DW_ATE_signed
+	DWARFSignedChar DWARFEncoding = 0x06 // DW_ATE_signed_char
+	DWARFUnsigned   DWARFEncoding = 0x08 // DW_ATE_unsigned
+)
+
+// LLVMDIBuilder builds the DWARF debug information of a module
+type LLVMDIBuilder interface {
+	// CreateCompileUnit creates the compile unit for a source file
+	CreateCompileUnit(filename, directory, producer string, optimized bool) LLVMMetadata
+
+	// CreateFile creates a source file descriptor
+	CreateFile(filename, directory string) LLVMMetadata
+
+	// CreateBasicType creates a scalar type
+	CreateBasicType(name string, sizeBits int, encoding DWARFEncoding) LLVMMetadata
+
+	// CreatePointerType creates a pointer to pointee
+	CreatePointerType(pointee LLVMMetadata, sizeBits int) LLVMMetadata
+
+	// CreateArrayType creates a fixed-length array type
+	CreateArrayType(element LLVMMetadata, count, sizeBits, alignBits int) LLVMMetadata
+
+	// CreateStructType creates a struct type whose members are set with SetStructMembers
+	CreateStructType(scope LLVMMetadata, name string, file LLVMMetadata, line, sizeBits, alignBits int) LLVMMetadata
+
+	// CreateMemberType creates a struct member
+	CreateMemberType(scope LLVMMetadata, name string, file LLVMMetadata, line, sizeBits, alignBits, offsetBits int, memberType LLVMMetadata) LLVMMetadata
+
+	// SetStructMembers sets the members of a struct type
+	SetStructMembers(structType LLVMMetadata, members []LLVMMetadata)
+
+	// CreateSubroutineType creates a function type; types holds the result
+	// type first, nil for void, followed by the parameter types
+	CreateSubroutineType(types []LLVMMetadata) LLVMMetadata
+
+	// CreateFunction creates the subprogram describing a function definition
+	CreateFunction(scope LLVMMetadata, name string, file LLVMMetadata, line int, funcType LLVMMetadata, optimized bool) LLVMMetadata
+
+	// SetSubprogram attaches a subprogram to a function
+	SetSubprogram(fn LLVMFunction, subprogram LLVMMetadata)
+
+	// CreateLexicalBlock creates a nested scope
+	CreateLexicalBlock(scope, file LLVMMetadata, line, column int) LLVMMetadata
+
+	// CreateAutoVariable creates a local variable
+	CreateAutoVariable(scope LLVMMetadata, name string, file LLVMMetadata, line int, varType LLVMMetadata) LLVMMetadata
+
+	// CreateParameterVariable creates a function parameter; argNo starts at 1
+	CreateParameterVariable(scope LLVMMetadata, name string, argNo int, file LLVMMetadata, line int, varType LLVMMetadata) LLVMMetadata
+
+	// CreateDebugLocation creates a source location within scope
+	CreateDebugLocation(line, column int, scope LLVMMetadata) LLVMMetadata
+
+	// InsertDeclareAtEnd appends an llvm.dbg.declare describing the variable
+	// stored at storage to the end of block
+	InsertDeclareAtEnd(storage LLVMValue, variable, location LLVMMetadata, block LLVMBasicBlock)
+
+	// Finalize completes the debug information of the module
+	Finalize()
+}
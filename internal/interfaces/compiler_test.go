@@ -29,6 +29,9 @@ func TestTokenString(t *testing.T) {
 		{TokenWhile, "While"},
 		{TokenFor, "For"},
 		{TokenReturn, "Return"},
+		{TokenBreak, "Break"},
+		{TokenContinue, "Continue"},
+		{TokenConst, "Const"},
 		{TokenPlus, "Plus"},
 		{TokenMinus, "Minus"},
 		{TokenStar, "Star"},
@@ -43,7 +46,21 @@ func TestTokenString(t *testing.T) {
 		{TokenAnd, "And"},
 		{TokenOr, "Or"},
 		{TokenNot, "Not"},
+		{TokenAmpersand, "Ampersand"},
+		{TokenPipe, "Pipe"},
+		{TokenCaret, "Caret"},
+		{TokenTilde, "Tilde"},
+		{TokenShiftLeft, "ShiftLeft"},
+		{TokenShiftRight, "ShiftRight"},
 		{TokenAssign, "Assign"},
+		{TokenDefine, "Define"},
+		{TokenPlusAssign, "PlusAssign"},
+		{TokenMinusAssign, "MinusAssign"},
+		{TokenStarAssign, "StarAssign"},
+		{TokenSlashAssign, "SlashAssign"},
+		{TokenPercentAssign, "PercentAssign"},
+		{TokenIncrement, "Increment"},
+		{TokenDecrement, "Decrement"},
 		{TokenLeftParen, "LeftParen"},
 		{TokenRightParen, "RightParen"},
 		{TokenLeftBrace, "LeftBrace"},
@@ -179,9 +196,12 @@ func TestTokenTypeConstants(t *testing.T) {
 	allTokens := []TokenType{
 		TokenEOF, TokenError, TokenIdentifier, TokenInt, TokenFloat, TokenString, TokenBool,
 		TokenTrue, TokenFalse, TokenFunc, TokenStruct, TokenVar, TokenIf, TokenElse,
-		TokenWhile, TokenFor, TokenReturn, TokenPlus, TokenMinus, TokenStar, TokenSlash, TokenPercent,
+		TokenWhile, TokenFor, TokenReturn, TokenBreak, TokenContinue, TokenConst, TokenPlus, TokenMinus, TokenStar, TokenSlash, TokenPercent,
 		TokenEqual, TokenNotEqual, TokenLess, TokenLessEqual, TokenGreater, TokenGreaterEqual,
-		TokenAnd, TokenOr, TokenNot, TokenAssign, TokenLeftParen, TokenRightParen,
+		TokenAnd, TokenOr, TokenNot,
+		TokenAmpersand, TokenPipe, TokenCaret, TokenTilde, TokenShiftLeft, TokenShiftRight, TokenAssign, TokenDefine,
+		TokenPlusAssign, TokenMinusAssign, TokenStarAssign, TokenSlashAssign, TokenPercentAssign,
+		TokenIncrement, TokenDecrement, TokenLeftParen, TokenRightParen,
 		TokenLeftBrace, TokenRightBrace, TokenLeftBracket, TokenRightBracket,
 		TokenSemicolon, TokenComma, TokenDot, TokenArrow, TokenColon,
 	}
@@ -37,6 +37,9 @@ var keywords = map[string]interfaces.TokenType{
 	"while":    interfaces.TokenWhile,
 	"for":      interfaces.TokenFor,
 	"return":   interfaces.TokenReturn,
+	"break":    interfaces.TokenBreak,
+	"continue": interfaces.TokenContinue,
+	"const":    interfaces.TokenConst,
 	"true":     interfaces.TokenTrue,
 	"false":    interfaces.TokenFalse,
 	// Type names like "int", "double", "string", "bool" should be identifiers
@@ -99,6 +102,16 @@ func (l *StaticLangLexer) NextToken() interfaces.Token {
 	// Single-character tokens
 	switch l.current {
 	case '+':
+		if l.next == '+' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenIncrement, Value: "++", Location: position}
+		}
+		if l.next == '=' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenPlusAssign, Value: "+=", Location: position}
+		}
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenPlus, Value: "+", Location: position}
 	case '-':
@@ -107,9 +120,24 @@ func (l *StaticLangLexer) NextToken() interfaces.Token {
 			l.advance()
 			return interfaces.Token{Type: interfaces.TokenArrow, Value: "->", Location: position}
 		}
+		if l.next == '-' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenDecrement, Value: "--", Location: position}
+		}
+		if l.next == '=' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenMinusAssign, Value: "-=", Location: position}
+		}
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenMinus, Value: "-", Location: position}
 	case '*':
+		if l.next == '=' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenStarAssign, Value: "*=", Location: position}
+		}
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenStar, Value: "*", Location: position}
 	case '/':
@@ -118,9 +146,19 @@ func (l *StaticLangLexer) NextToken() interfaces.Token {
 			l.skipComment()
 			return l.NextToken() // Get next token after comment
 		}
+		if l.next == '=' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenSlashAssign, Value: "/=", Location: position}
+		}
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenSlash, Value: "/", Location: position}
 	case '%':
+		if l.next == '=' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenPercentAssign, Value: "%=", Location: position}
+		}
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenPercent, Value: "%", Location: position}
 	case '(':
@@ -150,7 +188,18 @@ func (l *StaticLangLexer) NextToken() interfaces.Token {
 	case '.':
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenDot, Value: ".", Location: position}
+	case '^':
+		l.advance()
+		return interfaces.Token{Type: interfaces.TokenCaret, Value: "^", Location: position}
+	case '~':
+		l.advance()
+		return interfaces.Token{Type: interfaces.TokenTilde, Value: "~", Location: position}
 	case ':':
+		if l.next == '=' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenDefine, Value: ":=", Location: position}
+		}
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenColon, Value: ":", Location: position}
 	}
@@ -174,6 +223,11 @@ func (l *StaticLangLexer) NextToken() interfaces.Token {
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenNot, Value: "!", Location: position}
 	case '<':
+		if l.next == '<' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenShiftLeft, Value: "<<", Location: position}
+		}
 		if l.next == '=' {
 			l.advance()
 			l.advance()
@@ -182,6 +236,11 @@ func (l *StaticLangLexer) NextToken() interfaces.Token {
 		l.advance()
 		return interfaces.Token{Type: interfaces.TokenLess, Value: "<", Location: position}
 	case '>':
+		if l.next == '>' {
+			l.advance()
+			l.advance()
+			return interfaces.Token{Type: interfaces.TokenShiftRight, Value: ">>", Location: position}
+		}
 		if l.next == '=' {
 			l.advance()
 			l.advance()
@@ -195,12 +254,16 @@ func (l *StaticLangLexer) NextToken() interfaces.Token {
 			l.advance()
 			return interfaces.Token{Type: interfaces.TokenAnd, Value: "&&", Location: position}
 		}
+		l.advance()
+		return interfaces.Token{Type: interfaces.TokenAmpersand, Value: "&", Location: position}
 	case '|':
 		if l.next == '|' {
 			l.advance()
 			l.advance()
 			return interfaces.Token{Type: interfaces.TokenOr, Value: "||", Location: position}
 		}
+		l.advance()
+		return interfaces.Token{Type: interfaces.TokenPipe, Value: "|", Location: position}
 	}
 
 	// String literals
@@ -459,6 +522,12 @@ func TokenTypeString(t interfaces.TokenType) string {
 		return "TRUE"
 	case interfaces.TokenFalse:
 		return "FALSE"
+	case interfaces.TokenBreak:
+		return "BREAK"
+	case interfaces.TokenContinue:
+		return "CONTINUE"
+	case interfaces.TokenConst:
+		return "CONST"
 	case interfaces.TokenPlus:
 		return "PLUS"
 	case interfaces.TokenMinus:
@@ -487,8 +556,36 @@ func TokenTypeString(t interfaces.TokenType) string {
 		return "OR"
 	case interfaces.TokenNot:
 		return "NOT"
+	case interfaces.TokenAmpersand:
+		return "AMPERSAND"
+	case interfaces.TokenPipe:
+		return "PIPE"
+	case interfaces.TokenCaret:
+		return "CARET"
+	case interfaces.TokenTilde:
+		return "TILDE"
+	case interfaces.TokenShiftLeft:
+		return "SHIFT_LEFT"
+	case interfaces.TokenShiftRight:
+		return "SHIFT_RIGHT"
 	case interfaces.TokenAssign:
 		return "ASSIGN"
+	case interfaces.TokenDefine:
+		return "DEFINE"
+	case interfaces.TokenPlusAssign:
+		return "PLUS_ASSIGN"
+	case interfaces.TokenMinusAssign:
+		return "MINUS_ASSIGN"
+	case interfaces.TokenStarAssign:
+		return "STAR_ASSIGN"
+	case interfaces.TokenSlashAssign:
+		return "SLASH_ASSIGN"
+	case interfaces.TokenPercentAssign:
+		return "PERCENT_ASSIGN"
+	case interfaces.TokenIncrement:
+		return "INCREMENT"
+	case interfaces.TokenDecrement:
+		return "DECREMENT"
 	case interfaces.TokenLeftParen:
 		return "LEFT_PAREN"
 	case interfaces.TokenRightParen:
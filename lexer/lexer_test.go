@@ -16,22 +16,27 @@ func TestLexer_BasicTokenization(t *testing.T) {
 	}{
 		{
 			name:  "keywords",
-			input: "func var if else while for return struct true false",
+			input: "func var if else while for return struct true false break continue const",
 			expected: []interfaces.TokenType{
 				interfaces.TokenFunc, interfaces.TokenVar, interfaces.TokenIf, interfaces.TokenElse,
 				interfaces.TokenWhile, interfaces.TokenFor, interfaces.TokenReturn, interfaces.TokenStruct,
-				interfaces.TokenTrue, interfaces.TokenFalse, interfaces.TokenEOF,
+				interfaces.TokenTrue, interfaces.TokenFalse, interfaces.TokenBreak, interfaces.TokenContinue,
+				interfaces.TokenConst, interfaces.TokenEOF,
 			},
 		},
 		{
 			name:  "operators",
-			input: "+ - * / % == != < <= > >= && || ! =",
+			input: "+ - * / % == != < <= > >= && || ! & | ^ ~ << >> = := += -= *= /= %= ++ --",
 			expected: []interfaces.TokenType{
 				interfaces.TokenPlus, interfaces.TokenMinus, interfaces.TokenStar, interfaces.TokenSlash,
 				interfaces.TokenPercent, interfaces.TokenEqual, interfaces.TokenNotEqual, interfaces.TokenLess,
 				interfaces.TokenLessEqual, interfaces.TokenGreater, interfaces.TokenGreaterEqual,
-				interfaces.TokenAnd, interfaces.TokenOr, interfaces.TokenNot, interfaces.TokenAssign,
-				interfaces.TokenEOF,
+				interfaces.TokenAnd, interfaces.TokenOr, interfaces.TokenNot, interfaces.TokenAmpersand,
+				interfaces.TokenPipe, interfaces.TokenCaret, interfaces.TokenTilde, interfaces.TokenShiftLeft,
+				interfaces.TokenShiftRight, interfaces.TokenAssign,
+				interfaces.TokenDefine, interfaces.TokenPlusAssign, interfaces.TokenMinusAssign,
+				interfaces.TokenStarAssign, interfaces.TokenSlashAssign, interfaces.TokenPercentAssign,
+				interfaces.TokenIncrement, interfaces.TokenDecrement, interfaces.TokenEOF,
 			},
 		},
 		{
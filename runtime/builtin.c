@@ -3,6 +3,7 @@
  * Provides memory management and I/O functions for StaticLang programs
  */
 
+#include <stdint.h>
 #include <stdio.h>
 #include <stdlib.h>
 #include <string.h>
@@ -27,10 +28,18 @@ void sl_free(void* ptr) {
 
 /*
  * Print function for integers
- * Prints an integer value followed by a newline
+ * Prints a 64-bit signed integer value followed by a newline
  */
-void sl_print_int(int value) {
-    printf("%d\n", value);
+void sl_print_int(int64_t value) {
+    printf("%lld\n", (long long)value);
+}
+
+/*
+ * Print function for unsigned integers
+ * Prints a 64-bit unsigned integer value followed by a newline
+ */
+void sl_print_uint(uint64_t value) {
+    printf("%llu\n", (unsigned long long)value);
 }
 
 /*
@@ -51,6 +60,14 @@ void sl_print_string(const char* value) {
     }
 }
 
+/*
+ * Print function for bools
+ * Prints true or false followed by a newline
+ */
+void sl_print_bool(int value) {
+    printf("%s\n", value ? "true" : "false");
+}
+
 /*
  * String allocation and initialization
  * Allocates memory for a string and copies the content
@@ -97,6 +114,53 @@ int sl_compare_string(const char* str1, const char* str2) {
     return strcmp(str1, str2);
 }
 
+/*
+ * String length
+ * Returns the number of bytes in a string, 0 for NULL
+ */
+int64_t sl_string_length(const char* str) {
+    if (str == NULL) return 0;
+    return (int64_t)strlen(str);
+}
+
+/*
+ * Integer to string conversion
+ * Formats a 64-bit signed integer into newly allocated memory
+ */
+char* sl_int_to_string(int64_t value) {
+    char buffer[32];
+    snprintf(buffer, sizeof(buffer), "%lld", (long long)value);
+    return sl_alloc_string(buffer);
+}
+
+/*
+ * Unsigned integer to string conversion
+ * Formats a 64-bit unsigned integer into newly allocated memory
+ */
+char* sl_uint_to_string(uint64_t value) {
+    char buffer[32];
+    snprintf(buffer, sizeof(buffer), "%llu", (unsigned long long)value);
+    return sl_alloc_string(buffer);
+}
+
+/*
+ * Float to string conversion
+ * Formats a double in the shortest of %f and %e notation
+ */
+char* sl_float_to_string(double value) {
+    char buffer[64];
+    snprintf(buffer, sizeof(buffer), "%g", value);
+    return sl_alloc_string(buffer);
+}
+
+/*
+ * Bool to string conversion
+ * Returns a newly allocated "true" or "false"
+ */
+char* sl_bool_to_string(int value) {
+    return sl_alloc_string(value ? "true" : "false");
+}
+
 /*
  * Array allocation
  * Allocates memory for an array of the specified type and size
@@ -105,6 +169,28 @@ void* sl_alloc_array(size_t element_size, size_t count) {
     return calloc(count, element_size);
 }
 
+/*
+ * Array bounds check failure
+ * Reports an out-of-range index with its source location and exits
+ */
+void sl_bounds_check_failed(const char* file, int line, int column, int64_t index, int64_t length) {
+    fflush(stdout);
+    fprintf(stderr, "%s:%d:%d: runtime error: index %lld out of range for array of length %lld\n",
+            file != NULL ? file : "<unknown>", line, column, (long long)index, (long long)length);
+    exit(2);
+}
+
+/*
+ * Division check failure
+ * Reports an integer division or remainder by zero with its source location and exits
+ */
+void sl_division_by_zero(const char* file, int line, int column) {
+    fflush(stdout);
+    fprintf(stderr, "%s:%d:%d: runtime error: integer division by zero\n",
+            file != NULL ? file : "<unknown>", line, column);
+    exit(2);
+}
+
 /*
  * Memory debugging functions (only active in debug builds)
  */
@@ -7,6 +7,7 @@
 #define STATICLANG_BUILTIN_H
 
 #include <stddef.h>
+#include <stdint.h>
 
 #ifdef __cplusplus
 extern "C" {
@@ -17,18 +18,31 @@ void* sl_malloc(size_t size);
 void sl_free(void* ptr);
 
 /* Print functions for different types */
-void sl_print_int(int value);
+void sl_print_int(int64_t value);
+void sl_print_uint(uint64_t value);
 void sl_print_double(double value);
 void sl_print_string(const char* value);
+void sl_print_bool(int value);
 
 /* String manipulation functions */
 char* sl_alloc_string(const char* str);
 char* sl_concat_string(const char* str1, const char* str2);
 int sl_compare_string(const char* str1, const char* str2);
+int64_t sl_string_length(const char* str);
+
+/* Conversions to string */
+char* sl_int_to_string(int64_t value);
+char* sl_uint_to_string(uint64_t value);
+char* sl_float_to_string(double value);
+char* sl_bool_to_string(int value);
 
 /* Array allocation */
 void* sl_alloc_array(size_t element_size, size_t count);
 
+/* Runtime checks */
+void sl_bounds_check_failed(const char* file, int line, int column, int64_t index, int64_t length);
+void sl_division_by_zero(const char* file, int line, int column);
+
 /* Debug memory functions (only in debug builds) */
 #ifdef DEBUG_MEMORY
 void* sl_debug_malloc(size_t size, const char* file, int line);
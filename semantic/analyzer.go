@@ -3,6 +3,7 @@ package semantic
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/sokoide/llvm5/internal/domain"
 	"github.com/sokoide/llvm5/internal/interfaces"
@@ -15,12 +16,15 @@ type Analyzer struct {
 	errorReporter        domain.ErrorReporter
 	currentFunction      *domain.FunctionDecl
 	builtinsInitialized bool
+	loopLabels           []string // Labels of the enclosing loops, innermost last ("" if unlabeled)
+	constants            map[*interfaces.Symbol]interface{} // Values of the constants declared so far
 }
 
 // NewAnalyzer creates a new semantic analyzer
 func NewAnalyzer() *Analyzer {
 	return &Analyzer{
 		typeRegistry: domain.NewDefaultTypeRegistry(),
+		constants:    make(map[*interfaces.Symbol]interface{}),
 	}
 }
 
@@ -34,15 +38,31 @@ func (a *Analyzer) Analyze(ast *domain.Program) error {
 		a.builtinsInitialized = true
 	}
 
-	// First pass: collect all function and struct declarations
+	// First pass: collect all function, struct and global variable declarations,
+	// and evaluate global constants in source order
 	for _, decl := range ast.Declarations {
+		if constant, ok := decl.(*domain.VarDeclStmt); ok && constant.Constant {
+			if err := a.defineConstant(constant); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := a.declareTopLevelSymbol(decl); err != nil {
 			return err
 		}
 	}
 
-	// Second pass: analyze function bodies
+	// Second pass: analyze function bodies and global initializers
 	for _, decl := range ast.Declarations {
+		if global, ok := decl.(*domain.VarDeclStmt); ok {
+			if global.Constant {
+				continue
+			}
+			if err := a.analyzeGlobalVariable(global); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := decl.Accept(a); err != nil {
 			return err
 		}
@@ -66,15 +86,21 @@ func (a *Analyzer) SetErrorReporter(reporter domain.ErrorReporter) {
 	a.errorReporter = reporter
 }
 
-// declareTopLevelSymbol declares function and struct symbols in the global scope
+// declareTopLevelSymbol declares function, struct and global variable symbols in the global scope
 func (a *Analyzer) declareTopLevelSymbol(decl domain.Declaration) error {
 	switch d := decl.(type) {
 	case *domain.FunctionDecl:
 		// Create function type
 		paramTypes := make([]domain.Type, len(d.Parameters))
 		for i, param := range d.Parameters {
+			if err := a.resolveType(param.Type); err != nil {
+				return err
+			}
 			paramTypes[i] = param.Type
 		}
+		if err := a.resolveType(d.ReturnType); err != nil {
+			return err
+		}
 
 		funcType := &domain.FunctionType{
 			ParameterTypes: paramTypes,
@@ -91,6 +117,12 @@ func (a *Analyzer) declareTopLevelSymbol(decl domain.Declaration) error {
 		return err
 
 	case *domain.StructDecl:
+		for _, field := range d.Fields {
+			if err := a.resolveType(field.Type); err != nil {
+				return err
+			}
+		}
+
 		// Create struct type
 		structType, err := a.typeRegistry.CreateStructType(d.Name, d.Fields)
 		if err != nil {
@@ -106,6 +138,20 @@ func (a *Analyzer) declareTopLevelSymbol(decl domain.Declaration) error {
 		)
 		return err
 
+	case *domain.VarDeclStmt:
+		if err := a.resolveType(d.Type_); err != nil {
+			return err
+		}
+
+		// Declare global variable symbol, visible from every function
+		_, err := a.symbolTable.DeclareSymbol(
+			d.Name,
+			d.Type_,
+			interfaces.VariableSymbol,
+			d.GetLocation(),
+		)
+		return err
+
 	default:
 		return fmt.Errorf("unknown declaration type: %T", decl)
 	}
@@ -145,6 +191,10 @@ func (a *Analyzer) initializeBuiltinFunctions() error {
 			ParameterTypes: []domain.Type{}, // Variadic - will be handled specially
 			ReturnType:     domain.NewVoidType(),
 		},
+		"len": {
+			ParameterTypes: []domain.Type{}, // Accepts strings and arrays - handled specially
+			ReturnType:     domain.NewIntType(),
+		},
 	}
 
 	// Add builtin functions to symbol table
@@ -222,45 +272,269 @@ func (a *Analyzer) VisitBlockStmt(stmt *domain.BlockStmt) error {
 
 // VisitVarDeclStmt analyzes variable declarations
 func (a *Analyzer) VisitVarDeclStmt(stmt *domain.VarDeclStmt) error {
+	if stmt.Constant {
+		return a.defineConstant(stmt)
+	}
+	if err := a.resolveType(stmt.Type_); err != nil {
+		return err
+	}
+
 	// Check if initializer exists and type check it
 	if stmt.Initializer != nil {
 		if err := stmt.Initializer.Accept(a); err != nil {
 			return err
 		}
 
+		if stmt.Type_ == nil {
+			// Variables declared without a type take the type of their initializer
+			stmt.Type_ = a.inferVariableType(stmt)
+		} else {
+			// Type check assignment
+			a.adaptConstant(stmt.Initializer, stmt.Type_)
+			initType := stmt.Initializer.GetType()
+			if !stmt.Type_.IsAssignableFrom(initType) {
+				a.reportError(
+					domain.TypeCheckError,
+					fmt.Sprintf("cannot assign %s to variable of type %s", initType.String(), stmt.Type_.String()),
+					stmt.GetLocation(),
+					"in variable declaration",
+					[]string{"ensure the initializer expression matches the declared type"},
+				)
+			}
+		}
+	}
+
+	// Declare variable symbol
+	_, err := a.symbolTable.DeclareSymbol(
+		stmt.Name,
+		stmt.Type_,
+		interfaces.VariableSymbol,
+		stmt.GetLocation(),
+	)
+	if err != nil {
+		a.reportError(
+			domain.SemanticError,
+			fmt.Sprintf("variable '%s' already declared", stmt.Name),
+			stmt.GetLocation(),
+			"in variable declaration",
+			[]string{"variable names must be unique within a scope"},
+		)
+	}
+
+	return nil
+}
+
+// inferVariableType returns the type of the initializer of a variable declared
+// without a type, reporting initializers that do not produce a value
+func (a *Analyzer) inferVariableType(stmt *domain.VarDeclStmt) domain.Type {
+	initType := stmt.Initializer.GetType()
+	var reason string
+	switch t := initType.(type) {
+	case nil:
+		reason = "the initializer has no type"
+	case *domain.TypeError:
+		reason = "the initializer is invalid"
+	case *domain.FunctionType:
+		reason = "functions are not values"
+	case *domain.BasicType:
+		if t.Kind == domain.VoidType {
+			reason = "the initializer does not return a value"
+		}
+	}
+	if reason == "" {
+		return initType
+	}
+
+	a.reportError(
+		domain.TypeCheckError,
+		fmt.Sprintf("cannot infer type of '%s': %s", stmt.Name, reason),
+		stmt.GetLocation(),
+		"in variable declaration",
+		[]string{"declare the variable with an explicit type"},
+	)
+	return &domain.TypeError{Message: "cannot infer variable type"}
+}
+
+// analyzeGlobalVariable type checks the initializer of a global variable and
+// folds it to a literal, since globals are initialized before the program runs
+func (a *Analyzer) analyzeGlobalVariable(stmt *domain.VarDeclStmt) error {
+	if stmt.Initializer == nil {
+		return nil
+	}
+	if err := stmt.Initializer.Accept(a); err != nil {
+		return err
+	}
+	if _, isError := stmt.Initializer.GetType().(*domain.TypeError); isError {
+		return nil
+	}
+
+	literal, err := a.constantLiteral(stmt.Initializer)
+	if err != nil {
+		a.reportError(
+			domain.SemanticError,
+			fmt.Sprintf("invalid initializer for global '%s': %v", stmt.Name, err),
+			stmt.Initializer.GetLocation(),
+			"in global variable declaration",
+			[]string{"global variables can only be initialized with literals and operators applied to them"},
+		)
+		return nil
+	}
+	stmt.Initializer = literal
+
+	// Type check assignment
+	a.adaptConstant(stmt.Initializer, stmt.Type_)
+	initType := stmt.Initializer.GetType()
+	if !stmt.Type_.IsAssignableFrom(initType) {
+		a.reportError(
+			domain.TypeCheckError,
+			fmt.Sprintf("cannot assign %s to variable of type %s", initType.String(), stmt.Type_.String()),
+			stmt.GetLocation(),
+			"in global variable declaration",
+			[]string{"ensure the initializer expression matches the declared type"},
+		)
+	}
+
+	return nil
+}
+
+// defineConstant evaluates the initializer of a constant declaration and
+// declares the constant with its value, so that later constant expressions
+// and array sizes can refer to it
+func (a *Analyzer) defineConstant(stmt *domain.VarDeclStmt) error {
+	if err := stmt.Initializer.Accept(a); err != nil {
+		return err
+	}
+
+	var value interface{}
+	basic, isBasic := stmt.Type_.(*domain.BasicType)
+	_, isError := stmt.Initializer.GetType().(*domain.TypeError)
+	switch {
+	case !isBasic || basic.Kind == domain.VoidType:
+		a.reportError(
+			domain.TypeCheckError,
+			fmt.Sprintf("constant '%s' cannot have type %s", stmt.Name, stmt.Type_.String()),
+			stmt.GetLocation(),
+			"in constant declaration",
+			[]string{"constants must have a numeric, bool or string type"},
+		)
+	case !isError:
+		literal, err := a.constantLiteral(stmt.Initializer)
+		if err != nil {
+			a.reportError(
+				domain.SemanticError,
+				fmt.Sprintf("invalid initializer for constant '%s': %v", stmt.Name, err),
+				stmt.Initializer.GetLocation(),
+				"in constant declaration",
+				[]string{"constants can only be initialized with literals, other constants and operators applied to them"},
+			)
+			break
+		}
+		stmt.Initializer = literal
+
 		// Type check assignment
+		a.adaptConstant(stmt.Initializer, stmt.Type_)
 		initType := stmt.Initializer.GetType()
 		if !stmt.Type_.IsAssignableFrom(initType) {
 			a.reportError(
 				domain.TypeCheckError,
-				fmt.Sprintf("cannot assign %s to variable of type %s", initType.String(), stmt.Type_.String()),
+				fmt.Sprintf("cannot assign %s to constant of type %s", initType.String(), stmt.Type_.String()),
 				stmt.GetLocation(),
-				"in variable declaration",
+				"in constant declaration",
 				[]string{"ensure the initializer expression matches the declared type"},
 			)
+			break
+		}
+		value = literal.Value
+		if float, ok := value.(float64); ok {
+			value = roundConstant(float, stmt.Type_)
 		}
 	}
 
-	// Declare variable symbol
-	_, err := a.symbolTable.DeclareSymbol(
+	// Declare constant symbol
+	symbol, err := a.symbolTable.DeclareSymbol(
 		stmt.Name,
 		stmt.Type_,
-		interfaces.VariableSymbol,
+		interfaces.ConstantSymbol,
 		stmt.GetLocation(),
 	)
 	if err != nil {
 		a.reportError(
 			domain.SemanticError,
-			fmt.Sprintf("variable '%s' already declared", stmt.Name),
+			fmt.Sprintf("constant '%s' already declared", stmt.Name),
 			stmt.GetLocation(),
-			"in variable declaration",
-			[]string{"variable names must be unique within a scope"},
+			"in constant declaration",
+			[]string{"constant names must be unique within a scope"},
 		)
+		return nil
+	}
+	if value != nil {
+		a.constants[symbol] = value
+	}
+
+	return nil
+}
+
+// resolveType evaluates the constant size expressions of an array type,
+// including those of nested element types, and replaces them with their values
+func (a *Analyzer) resolveType(t domain.Type) error {
+	array, ok := t.(*domain.ArrayType)
+	if !ok {
+		return nil
+	}
+	if err := a.resolveType(array.ElementType); err != nil {
+		return err
+	}
+	if array.SizeExpr == nil {
+		return nil
 	}
 
+	sizeExpr := array.SizeExpr
+	array.SizeExpr = nil
+	if err := sizeExpr.Accept(a); err != nil {
+		return err
+	}
+	if _, isError := sizeExpr.GetType().(*domain.TypeError); isError {
+		return nil
+	}
+
+	var message string
+	if !domain.IsIntegerType(sizeExpr.GetType()) {
+		message = fmt.Sprintf("array size must be an integer, got %s", sizeExpr.GetType().String())
+	} else if value, err := a.evaluateConstant(sizeExpr); err != nil {
+		message = fmt.Sprintf("invalid array size: %v", err)
+	} else if size := value.(int64); size < 0 || size > math.MaxInt32 {
+		message = fmt.Sprintf("invalid array size %d", size)
+	} else {
+		array.Size = int(size)
+		return nil
+	}
+	a.reportError(
+		domain.TypeCheckError,
+		message,
+		sizeExpr.GetLocation(),
+		"in array type",
+		[]string{"array sizes must be non-negative integer constants"},
+	)
 	return nil
 }
 
+// checkAssignable reports assignments to constants
+func (a *Analyzer) checkAssignable(target domain.Expression) {
+	ident, ok := target.(*domain.IdentifierExpr)
+	if !ok {
+		return
+	}
+	if symbol, found := a.symbolTable.LookupSymbol(ident.Name); found && symbol.Kind == interfaces.ConstantSymbol {
+		a.reportError(
+			domain.SemanticError,
+			fmt.Sprintf("cannot assign to constant '%s'", ident.Name),
+			target.GetLocation(),
+			"in assignment",
+			[]string{"declare it with var to make it modifiable"},
+		)
+	}
+}
+
 // VisitAssignStmt analyzes assignment statements
 func (a *Analyzer) VisitAssignStmt(stmt *domain.AssignStmt) error {
 	// Analyze target and value expressions
@@ -270,9 +544,11 @@ func (a *Analyzer) VisitAssignStmt(stmt *domain.AssignStmt) error {
 	if err := stmt.Value.Accept(a); err != nil {
 		return err
 	}
+	a.checkAssignable(stmt.Target)
 
 	// Type check assignment
 	targetType := stmt.Target.GetType()
+	a.adaptConstant(stmt.Value, targetType)
 	valueType := stmt.Value.GetType()
 
 	if !targetType.IsAssignableFrom(valueType) {
@@ -288,6 +564,51 @@ func (a *Analyzer) VisitAssignStmt(stmt *domain.AssignStmt) error {
 	return nil
 }
 
+// VisitCompoundAssignStmt analyzes compound assignments, increments and
+// decrements, which follow the rules of their binary operator
+func (a *Analyzer) VisitCompoundAssignStmt(stmt *domain.CompoundAssignStmt) error {
+	if err := stmt.Target.Accept(a); err != nil {
+		return err
+	}
+	targetType := stmt.Target.GetType()
+
+	operator := stmt.Operator.String() + "="
+	valueType := targetType
+	if stmt.Value != nil {
+		if err := stmt.Value.Accept(a); err != nil {
+			return err
+		}
+		a.adaptConstant(stmt.Value, targetType)
+		valueType = stmt.Value.GetType()
+	} else if stmt.Operator == domain.Add {
+		operator = "++"
+	} else {
+		operator = "--"
+	}
+	a.checkAssignable(stmt.Target)
+
+	// Increments apply to numbers only, while += also concatenates strings
+	valid := domain.CanApplyBinaryOperator(stmt.Operator, targetType, valueType)
+	if stmt.Value == nil {
+		valid = valid && domain.IsNumericType(targetType)
+	}
+	if !valid {
+		message := fmt.Sprintf("cannot apply operator %s to %s and %s", operator, targetType.String(), valueType.String())
+		if stmt.Value == nil {
+			message = fmt.Sprintf("cannot apply operator %s to %s", operator, targetType.String())
+		}
+		a.reportError(
+			domain.TypeCheckError,
+			message,
+			stmt.GetLocation(),
+			"in compound assignment",
+			[]string{"ensure the target and value have compatible types for the operator"},
+		)
+	}
+
+	return nil
+}
+
 // VisitIfStmt analyzes if statements
 func (a *Analyzer) VisitIfStmt(stmt *domain.IfStmt) error {
 	// Analyze condition
@@ -344,6 +665,8 @@ func (a *Analyzer) VisitWhileStmt(stmt *domain.WhileStmt) error {
 	}
 
 	// Analyze body
+	a.enterLoop(stmt.Label, stmt.GetLocation())
+	defer a.exitLoop()
 	return stmt.Body.Accept(a)
 }
 
@@ -388,9 +711,77 @@ func (a *Analyzer) VisitForStmt(stmt *domain.ForStmt) error {
 	}
 
 	// Analyze body
+	a.enterLoop(stmt.Label, stmt.GetLocation())
+	defer a.exitLoop()
 	return stmt.Body.Accept(a)
 }
 
+// enterLoop records a loop so that break and continue statements in its body can target it
+func (a *Analyzer) enterLoop(label string, location domain.SourceRange) {
+	if label != "" {
+		for _, enclosing := range a.loopLabels {
+			if enclosing == label {
+				a.reportError(
+					domain.SemanticError,
+					fmt.Sprintf("loop label %s is already used by an enclosing loop", label),
+					location,
+					"in labeled loop",
+					[]string{"use a different label for the inner loop"},
+				)
+				break
+			}
+		}
+	}
+	a.loopLabels = append(a.loopLabels, label)
+}
+
+// exitLoop removes the innermost loop recorded by enterLoop
+func (a *Analyzer) exitLoop() {
+	a.loopLabels = a.loopLabels[:len(a.loopLabels)-1]
+}
+
+// checkLoopJump validates the target of a break or continue statement
+func (a *Analyzer) checkLoopJump(keyword, label string, location domain.SourceRange) {
+	if len(a.loopLabels) == 0 {
+		a.reportError(
+			domain.SemanticError,
+			fmt.Sprintf("%s statement outside loop", keyword),
+			location,
+			"",
+			[]string{fmt.Sprintf("%s statements can only be used inside while or for loops", keyword)},
+		)
+		return
+	}
+
+	if label == "" {
+		return
+	}
+	for _, enclosing := range a.loopLabels {
+		if enclosing == label {
+			return
+		}
+	}
+	a.reportError(
+		domain.SemanticError,
+		fmt.Sprintf("undefined loop label: %s", label),
+		location,
+		fmt.Sprintf("in %s statement", keyword),
+		[]string{"the label must name an enclosing while or for loop"},
+	)
+}
+
+// VisitBreakStmt analyzes break statements
+func (a *Analyzer) VisitBreakStmt(stmt *domain.BreakStmt) error {
+	a.checkLoopJump("break", stmt.Label, stmt.GetLocation())
+	return nil
+}
+
+// VisitContinueStmt analyzes continue statements
+func (a *Analyzer) VisitContinueStmt(stmt *domain.ContinueStmt) error {
+	a.checkLoopJump("continue", stmt.Label, stmt.GetLocation())
+	return nil
+}
+
 // VisitReturnStmt analyzes return statements
 func (a *Analyzer) VisitReturnStmt(stmt *domain.ReturnStmt) error {
 	if a.currentFunction == nil {
@@ -424,6 +815,7 @@ func (a *Analyzer) VisitReturnStmt(stmt *domain.ReturnStmt) error {
 			return err
 		}
 
+		a.adaptConstant(stmt.Value, expectedReturnType)
 		valueType := stmt.Value.GetType()
 		if !expectedReturnType.IsAssignableFrom(valueType) {
 			a.reportError(
@@ -454,6 +846,13 @@ func (a *Analyzer) VisitBinaryExpr(expr *domain.BinaryExpr) error {
 		return err
 	}
 
+	// Integer constants take the sized integer type of the other operand.
+	// A shift count is independent of the type of the value shifted.
+	if expr.Operator != domain.Shl && expr.Operator != domain.Shr {
+		a.adaptConstant(expr.Right, expr.Left.GetType())
+		a.adaptConstant(expr.Left, expr.Right.GetType())
+	}
+
 	leftType := expr.Left.GetType()
 	rightType := expr.Right.GetType()
 
@@ -475,6 +874,8 @@ func (a *Analyzer) VisitBinaryExpr(expr *domain.BinaryExpr) error {
 	switch expr.Operator {
 	case domain.Add, domain.Sub, domain.Mul, domain.Div, domain.Mod:
 		resultType = leftType // Arithmetic operations preserve type
+	case domain.BitAnd, domain.BitOr, domain.BitXor, domain.Shl, domain.Shr:
+		resultType = leftType // Bitwise operations preserve the left operand's type
 	case domain.Eq, domain.Ne, domain.Lt, domain.Le, domain.Gt, domain.Ge:
 		resultType = a.typeRegistry.GetBuiltinType(domain.BoolType) // Comparison operations return bool
 	case domain.And, domain.Or:
@@ -540,6 +941,9 @@ func (a *Analyzer) VisitCallExpr(expr *domain.CallExpr) error {
 			// Special handling for print function - it's variadic
 			return a.handlePrintFunction(expr)
 		}
+		if identExpr.Name == "len" {
+			return a.handleLenFunction(expr)
+		}
 	}
 
 	// Check argument count for regular functions
@@ -561,6 +965,7 @@ func (a *Analyzer) VisitCallExpr(expr *domain.CallExpr) error {
 
 		if i < len(funcType.ParameterTypes) {
 			expectedType := funcType.ParameterTypes[i]
+			a.adaptConstant(arg, expectedType)
 			actualType := arg.GetType()
 
 			if !expectedType.IsAssignableFrom(actualType) {
@@ -607,6 +1012,44 @@ func (a *Analyzer) handlePrintFunction(expr *domain.CallExpr) error {
 	return nil
 }
 
+// handleLenFunction validates the len builtin, which takes one string or array
+func (a *Analyzer) handleLenFunction(expr *domain.CallExpr) error {
+	for _, arg := range expr.Args {
+		if err := arg.Accept(a); err != nil {
+			return err
+		}
+	}
+
+	if len(expr.Args) != 1 {
+		a.reportError(
+			domain.TypeCheckError,
+			fmt.Sprintf("len expects 1 argument, got %d", len(expr.Args)),
+			expr.GetLocation(),
+			"in len function call",
+			[]string{"pass a single string or array to len"},
+		)
+		expr.SetType(&domain.TypeError{Message: "invalid len call"})
+		return nil
+	}
+
+	argType := expr.Args[0].GetType()
+	_, isArray := argType.(*domain.ArrayType)
+	if !isArray && argType.String() != "string" {
+		a.reportError(
+			domain.TypeCheckError,
+			fmt.Sprintf("cannot take len of %s", argType.String()),
+			expr.GetLocation(),
+			"in len function call",
+			[]string{"len accepts strings and arrays"},
+		)
+		expr.SetType(&domain.TypeError{Message: "invalid len call"})
+		return nil
+	}
+
+	expr.SetType(a.typeRegistry.GetBuiltinType(domain.IntType))
+	return nil
+}
+
 // VisitIdentifierExpr analyzes identifier expressions
 func (a *Analyzer) VisitIdentifierExpr(expr *domain.IdentifierExpr) error {
 	// Look up symbol
@@ -649,6 +1092,60 @@ func (a *Analyzer) VisitLiteralExpr(expr *domain.LiteralExpr) error {
 	return nil
 }
 
+// adaptConstant gives a numeric literal (optionally negated) the sized type
+// expected by its context, so that e.g. `var b u8 = 255;` or `var f f32 = 1.5;`
+// type checks without a cast. Constants that do not fit the target are reported.
+func (a *Analyzer) adaptConstant(expr domain.Expression, target domain.Type) {
+	if expr.GetType() == nil || expr.GetType().Equals(target) {
+		return
+	}
+	sameClass := (domain.IsIntegerType(target) && domain.IsIntegerType(expr.GetType())) ||
+		(domain.IsFloatType(target) && domain.IsFloatType(expr.GetType()))
+	if !sameClass {
+		return
+	}
+
+	literal, negated := expr, false
+	if unary, ok := expr.(*domain.UnaryExpr); ok && unary.Operator == domain.Neg {
+		literal, negated = unary.Operand, true
+	}
+	lit, ok := literal.(*domain.LiteralExpr)
+	if !ok {
+		return
+	}
+
+	var fits bool
+	var text string
+	switch value := lit.Value.(type) {
+	case int64:
+		if negated {
+			value = -value
+		}
+		fits, text = domain.IntegerFits(value, target), fmt.Sprintf("%d", value)
+	case float64:
+		if negated {
+			value = -value
+		}
+		fits, text = domain.FloatFits(value, target), fmt.Sprintf("%g", value)
+	default:
+		return
+	}
+
+	if !fits {
+		a.reportError(
+			domain.TypeCheckError,
+			fmt.Sprintf("constant %s overflows %s", text, target.String()),
+			expr.GetLocation(),
+			"in numeric constant",
+			[]string{fmt.Sprintf("use a value in the range of %s", target.String())},
+		)
+		return
+	}
+
+	lit.SetType(target)
+	expr.SetType(target)
+}
+
 // VisitIndexExpr analyzes array index expressions
 func (a *Analyzer) VisitIndexExpr(expr *domain.IndexExpr) error {
 	// Analyze object and index
@@ -677,11 +1174,10 @@ func (a *Analyzer) VisitIndexExpr(expr *domain.IndexExpr) error {
 	}
 
 	// Check if index is integer
-	intType := a.typeRegistry.GetBuiltinType(domain.IntType)
-	if !indexType.Equals(intType) {
+	if !domain.IsIntegerType(indexType) {
 		a.reportError(
 			domain.TypeCheckError,
-			fmt.Sprintf("array index must be int, got %s", indexType.String()),
+			fmt.Sprintf("array index must be an integer, got %s", indexType.String()),
 			expr.Index.GetLocation(),
 			"in index expression",
 			[]string{"use an integer expression as the index"},
@@ -732,3 +1228,26 @@ func (a *Analyzer) VisitMemberExpr(expr *domain.MemberExpr) error {
 	expr.SetType(memberType)
 	return nil
 }
+
+// VisitCastExpr analyzes explicit type conversions
+func (a *Analyzer) VisitCastExpr(expr *domain.CastExpr) error {
+	if err := expr.Value.Accept(a); err != nil {
+		return err
+	}
+
+	valueType := expr.Value.GetType()
+	if _, isError := valueType.(*domain.TypeError); !isError && !domain.CanCast(valueType, expr.Target) {
+		a.reportError(
+			domain.TypeCheckError,
+			fmt.Sprintf("cannot convert %s to %s", valueType.String(), expr.Target.String()),
+			expr.GetLocation(),
+			"in type conversion",
+			[]string{"numeric and bool values convert to each other and to string"},
+		)
+		expr.SetType(&domain.TypeError{Message: "invalid conversion"})
+		return nil
+	}
+
+	expr.SetType(expr.Target)
+	return nil
+}
@@ -637,6 +637,45 @@ func TestAnalyzer_VisitAssignStmt(t *testing.T) {
 	}
 }
 
+// TestAnalyzer_VisitCompoundAssignStmt tests compound assignments, increments and decrements
+func TestAnalyzer_VisitCompoundAssignStmt(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	errorReporter := &MockErrorReporter{}
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetErrorReporter(errorReporter)
+
+	symbolTable.DeclareSymbol("count", &domain.BasicType{Kind: domain.UInt8Type}, interfaces.VariableSymbol, domain.SourceRange{})
+	symbolTable.DeclareSymbol("name", &domain.BasicType{Kind: domain.StringType}, interfaces.VariableSymbol, domain.SourceRange{})
+	symbolTable.DeclareSymbol("limit", &domain.BasicType{Kind: domain.IntType}, interfaces.ConstantSymbol, domain.SourceRange{})
+
+	tests := []struct {
+		stmt     *domain.CompoundAssignStmt
+		expected string // Expected error, empty if valid
+	}{
+		{&domain.CompoundAssignStmt{Target: &domain.IdentifierExpr{Name: "count"}, Operator: domain.Add, Value: &domain.LiteralExpr{Value: int64(200)}}, ""},
+		{&domain.CompoundAssignStmt{Target: &domain.IdentifierExpr{Name: "count"}, Operator: domain.Sub}, ""},
+		{&domain.CompoundAssignStmt{Target: &domain.IdentifierExpr{Name: "name"}, Operator: domain.Add, Value: &domain.LiteralExpr{Value: "!"}}, ""},
+		{&domain.CompoundAssignStmt{Target: &domain.IdentifierExpr{Name: "count"}, Operator: domain.Mod, Value: &domain.LiteralExpr{Value: 1.5}},
+			"cannot apply operator %= to u8 and float"},
+		{&domain.CompoundAssignStmt{Target: &domain.IdentifierExpr{Name: "name"}, Operator: domain.Add}, "cannot apply operator ++ to string"},
+		{&domain.CompoundAssignStmt{Target: &domain.IdentifierExpr{Name: "limit"}, Operator: domain.Sub}, "cannot assign to constant 'limit'"},
+	}
+	for _, test := range tests {
+		errorReporter.Clear()
+		if err := analyzer.VisitCompoundAssignStmt(test.stmt); err != nil {
+			t.Fatalf("VisitCompoundAssignStmt failed: %v", err)
+		}
+		errors := errorReporter.GetErrors()
+		if test.expected == "" && len(errors) > 0 {
+			t.Errorf("Expected no errors, got %v", errors)
+		}
+		if test.expected != "" && (len(errors) == 0 || errors[0].Message != test.expected) {
+			t.Errorf("Expected error %q, got %v", test.expected, errors)
+		}
+	}
+}
+
 // TestAnalyzer_VisitIndexExpr tests array index expression analysis
 func TestAnalyzer_VisitIndexExpr(t *testing.T) {
 	analyzer := NewAnalyzer()
@@ -1341,6 +1380,519 @@ func TestAnalyzer_LoopConditionTypeValidation(t *testing.T) {
 	}
 }
 
+// TestAnalyzer_BreakContinueValidation tests break and continue placement and labels
+func TestAnalyzer_BreakContinueValidation(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	typeRegistry := domain.NewTypeRegistry()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(typeRegistry)
+	analyzer.SetErrorReporter(errorReporter)
+
+	// break and continue outside a loop are rejected
+	if err := analyzer.VisitBreakStmt(&domain.BreakStmt{}); err != nil {
+		t.Errorf("VisitBreakStmt should report, not return, errors: %v", err)
+	}
+	if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, "break statement outside loop") {
+		t.Errorf("Expected break outside loop error, got %v", errorReporter.GetErrors())
+	}
+
+	errorReporter.Clear()
+	analyzer.VisitContinueStmt(&domain.ContinueStmt{})
+	if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, "continue statement outside loop") {
+		t.Errorf("Expected continue outside loop error, got %v", errorReporter.GetErrors())
+	}
+
+	// Labeled jumps inside nested loops are accepted
+	errorReporter.Clear()
+	outer := &domain.WhileStmt{
+		Label:     "outer",
+		Condition: &domain.LiteralExpr{Value: true},
+		Body: &domain.WhileStmt{
+			Condition: &domain.LiteralExpr{Value: true},
+			Body: &domain.BlockStmt{Statements: []domain.Statement{
+				&domain.ContinueStmt{Label: "outer"},
+				&domain.BreakStmt{},
+			}},
+		},
+	}
+	if err := analyzer.VisitWhileStmt(outer); err != nil {
+		t.Fatalf("VisitWhileStmt failed: %v", err)
+	}
+	if errorReporter.HasErrors() {
+		t.Errorf("Labeled jumps in nested loops should be valid, got %v", errorReporter.GetErrors())
+	}
+
+	// Labels must name an enclosing loop
+	loop := &domain.WhileStmt{
+		Condition: &domain.LiteralExpr{Value: true},
+		Body:      &domain.BreakStmt{Label: "missing"},
+	}
+	analyzer.VisitWhileStmt(loop)
+	if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, "undefined loop label: missing") {
+		t.Errorf("Expected undefined label error, got %v", errorReporter.GetErrors())
+	}
+}
+
+// TestAnalyzer_SizedIntegerConstants tests integer literal adaptation to sized types
+func TestAnalyzer_SizedIntegerConstants(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	typeRegistry := domain.NewTypeRegistry()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(typeRegistry)
+	analyzer.SetErrorReporter(errorReporter)
+
+	u8Type := &domain.BasicType{Kind: domain.UInt8Type}
+
+	// A literal in range takes the declared type
+	literal := &domain.LiteralExpr{Value: int64(200)}
+	if err := analyzer.VisitVarDeclStmt(&domain.VarDeclStmt{Name: "small", Type_: u8Type, Initializer: literal}); err != nil {
+		t.Fatalf("VisitVarDeclStmt failed: %v", err)
+	}
+	if errorReporter.HasErrors() {
+		t.Errorf("Expected no errors, got %v", errorReporter.GetErrors())
+	}
+	if !literal.GetType().Equals(u8Type) {
+		t.Errorf("Expected literal type u8, got %s", literal.GetType())
+	}
+
+	// A literal out of range is rejected
+	analyzer.VisitVarDeclStmt(&domain.VarDeclStmt{
+		Name:        "big",
+		Type_:       u8Type,
+		Initializer: &domain.LiteralExpr{Value: int64(300)},
+	})
+	if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, "constant 300 overflows u8") {
+		t.Errorf("Expected overflow error, got %v", errorReporter.GetErrors())
+	}
+
+	// Distinct integer types do not mix implicitly
+	errorReporter.Clear()
+	symbolTable.DeclareSymbol("wide", &domain.BasicType{Kind: domain.IntType}, interfaces.VariableSymbol, domain.SourceRange{})
+	analyzer.VisitVarDeclStmt(&domain.VarDeclStmt{
+		Name:        "narrow",
+		Type_:       u8Type,
+		Initializer: &domain.IdentifierExpr{Name: "wide"},
+	})
+	if !errorReporter.HasErrors() {
+		t.Error("Expected type mismatch assigning int to u8")
+	}
+}
+
+// TestAnalyzer_GlobalVariables tests that globals are declared for every
+// function and that their initializers are folded to literals
+func TestAnalyzer_GlobalVariables(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(domain.NewTypeRegistry())
+	analyzer.SetErrorReporter(errorReporter)
+
+	intType := &domain.BasicType{Kind: domain.IntType}
+	u8Type := &domain.BasicType{Kind: domain.UInt8Type}
+	sum := &domain.VarDeclStmt{Name: "sum", Type_: intType, Initializer: &domain.BinaryExpr{
+		Left:     &domain.LiteralExpr{Value: int64(40)},
+		Operator: domain.Add,
+		Right: &domain.BinaryExpr{
+			Left:     &domain.LiteralExpr{Value: int64(7)},
+			Operator: domain.Mod,
+			Right:    &domain.LiteralExpr{Value: int64(5)},
+		},
+	}}
+	small := &domain.VarDeclStmt{Name: "small", Type_: u8Type, Initializer: &domain.BinaryExpr{
+		Left:     &domain.LiteralExpr{Value: int64(200)},
+		Operator: domain.Add,
+		Right:    &domain.LiteralExpr{Value: int64(55)},
+	}}
+	// The function uses sum before it appears in the source
+	useSum := &domain.FunctionDecl{
+		Name:       "main",
+		ReturnType: intType,
+		Body: &domain.BlockStmt{Statements: []domain.Statement{
+			&domain.ReturnStmt{Value: &domain.IdentifierExpr{Name: "sum"}},
+		}},
+	}
+	program := &domain.Program{Declarations: []domain.Declaration{useSum, sum, small}}
+
+	if err := analyzer.Analyze(program); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if errorReporter.HasErrors() {
+		t.Fatalf("Expected no errors, got %v", errorReporter.GetErrors())
+	}
+	if lit, ok := sum.Initializer.(*domain.LiteralExpr); !ok || lit.Value != int64(42) {
+		t.Errorf("Expected sum to be folded to 42, got %#v", sum.Initializer)
+	}
+	if lit, ok := small.Initializer.(*domain.LiteralExpr); !ok || lit.Value != int64(255) || !lit.GetType().Equals(u8Type) {
+		t.Errorf("Expected small to be folded to u8 255, got %#v", small.Initializer)
+	}
+
+	// Initializers that need the program to run are rejected
+	tests := []struct {
+		initializer domain.Expression
+		expected    string
+	}{
+		{&domain.IdentifierExpr{Name: "sum"}, "invalid initializer for global 'copy': sum is not a constant"},
+		{&domain.BinaryExpr{
+			Left:     &domain.LiteralExpr{Value: int64(1)},
+			Operator: domain.Div,
+			Right:    &domain.LiteralExpr{Value: int64(0)},
+		}, "division by zero in constant expression"},
+	}
+	for _, test := range tests {
+		errorReporter.Clear()
+		analyzer.analyzeGlobalVariable(&domain.VarDeclStmt{Name: "copy", Type_: intType, Initializer: test.initializer})
+		if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, test.expected) {
+			t.Errorf("Expected error %q, got %v", test.expected, errorReporter.GetErrors())
+		}
+	}
+}
+
+// TestAnalyzer_EvaluateConstant tests compile-time evaluation of expressions
+func TestAnalyzer_EvaluateConstant(t *testing.T) {
+	analyzer := NewAnalyzer()
+	analyzer.SetSymbolTable(infrastructure.NewSymbolTable())
+	analyzer.SetTypeRegistry(domain.NewTypeRegistry())
+	analyzer.SetErrorReporter(&MockErrorReporter{})
+
+	literal := func(value interface{}) domain.Expression { return &domain.LiteralExpr{Value: value} }
+	binary := func(left domain.Expression, op domain.BinaryOperator, right domain.Expression) domain.Expression {
+		return &domain.BinaryExpr{Left: left, Operator: op, Right: right}
+	}
+	cast := func(target domain.BasicTypeKind, value domain.Expression) domain.Expression {
+		return &domain.CastExpr{Target: &domain.BasicType{Kind: target}, Value: value}
+	}
+
+	tests := []struct {
+		name     string
+		expr     domain.Expression
+		expected interface{}
+	}{
+		{"arithmetic", binary(literal(int64(7)), domain.Mul, binary(literal(int64(3)), domain.Sub, literal(int64(1)))), int64(14)},
+		{"signed remainder", binary(&domain.UnaryExpr{Operator: domain.Neg, Operand: literal(int64(7))}, domain.Mod, literal(int64(4))), int64(-3)},
+		{"wrapping", binary(cast(domain.UInt8Type, literal(int64(250))), domain.Add, cast(domain.UInt8Type, literal(int64(10)))), int64(4)},
+		{"unsigned compare", binary(cast(domain.UInt64Type, literal(int64(-1))), domain.Gt, cast(domain.UInt64Type, literal(int64(1)))), true},
+		{"float", binary(literal(7.5), domain.Mod, literal(2.0)), 1.5},
+		{"logical", binary(&domain.UnaryExpr{Operator: domain.Not, Operand: literal(false)}, domain.And, binary(literal("a"), domain.Lt, literal("b"))), true},
+		{"concatenation", binary(literal("con"), domain.Add, literal("cat")), "concat"},
+		{"conversion", cast(domain.IntType, literal(-2.9)), int64(-2)},
+		{"bitwise", binary(literal(int64(12)), domain.BitOr, binary(literal(int64(10)), domain.BitXor, binary(literal(int64(6)), domain.BitAnd, literal(int64(3))))), int64(12)},
+		{"complement", &domain.UnaryExpr{Operator: domain.BitNot, Operand: cast(domain.UInt8Type, literal(int64(5)))}, int64(250)},
+		{"shift wrapping", binary(cast(domain.Int8Type, literal(int64(1))), domain.Shl, literal(int64(7))), int64(-128)},
+		{"arithmetic shift", binary(cast(domain.Int8Type, literal(int64(-128))), domain.Shr, literal(int64(3))), int64(-16)},
+		{"logical shift", binary(cast(domain.UInt8Type, literal(int64(-128))), domain.Shr, literal(int64(3))), int64(16)},
+	}
+	for _, test := range tests {
+		if err := test.expr.Accept(analyzer); err != nil {
+			t.Fatalf("%s: analysis failed: %v", test.name, err)
+		}
+		value, err := analyzer.evaluateConstant(test.expr)
+		if err != nil {
+			t.Errorf("%s: evaluateConstant failed: %v", test.name, err)
+			continue
+		}
+		if value != test.expected {
+			t.Errorf("%s: evaluateConstant() = %v, expected %v", test.name, value, test.expected)
+		}
+	}
+
+	wide := binary(literal(int64(1)), domain.Shl, literal(int64(64)))
+	if err := wide.Accept(analyzer); err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	if _, err := analyzer.evaluateConstant(wide); err == nil {
+		t.Error("Expected shifting by the operand width to be rejected")
+	}
+
+	call := &domain.CallExpr{Function: &domain.IdentifierExpr{Name: "len"}, Args: []domain.Expression{literal("abc")}}
+	if _, err := analyzer.evaluateConstant(call); err == nil {
+		t.Error("Expected calls not to be constant")
+	}
+}
+
+// TestAnalyzer_Constants tests constant declarations, their use in other
+// constants and array sizes, and rejection of assignments to them
+func TestAnalyzer_Constants(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(domain.NewTypeRegistry())
+	analyzer.SetErrorReporter(errorReporter)
+
+	intType := &domain.BasicType{Kind: domain.IntType}
+	boolType := &domain.BasicType{Kind: domain.BoolType}
+	size := &domain.VarDeclStmt{Name: "N", Type_: intType, Constant: true, Initializer: &domain.BinaryExpr{
+		Left:     &domain.LiteralExpr{Value: int64(4)},
+		Operator: domain.Mul,
+		Right:    &domain.LiteralExpr{Value: int64(256)},
+	}}
+	large := &domain.VarDeclStmt{Name: "large", Type_: boolType, Constant: true, Initializer: &domain.BinaryExpr{
+		Left:     &domain.BinaryExpr{Left: &domain.IdentifierExpr{Name: "N"}, Operator: domain.Gt, Right: &domain.LiteralExpr{Value: int64(1000)}},
+		Operator: domain.And,
+		Right:    &domain.LiteralExpr{Value: true},
+	}}
+	buffer := &domain.ArrayType{ElementType: intType, SizeExpr: &domain.BinaryExpr{
+		Left:     &domain.IdentifierExpr{Name: "N"},
+		Operator: domain.Div,
+		Right:    &domain.IdentifierExpr{Name: "half"},
+	}}
+	half := &domain.VarDeclStmt{Name: "half", Type_: intType, Constant: true, Initializer: &domain.LiteralExpr{Value: int64(2)}}
+	fn := &domain.FunctionDecl{
+		Name:       "main",
+		ReturnType: intType,
+		Body: &domain.BlockStmt{Statements: []domain.Statement{
+			half,
+			&domain.VarDeclStmt{Name: "buffer", Type_: buffer},
+			&domain.ReturnStmt{Value: &domain.IdentifierExpr{Name: "half"}},
+		}},
+	}
+	program := &domain.Program{Declarations: []domain.Declaration{size, large, fn}}
+
+	if err := analyzer.Analyze(program); err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if errorReporter.HasErrors() {
+		t.Fatalf("Expected no errors, got %v", errorReporter.GetErrors())
+	}
+	if lit, ok := size.Initializer.(*domain.LiteralExpr); !ok || lit.Value != int64(1024) {
+		t.Errorf("Expected N to be folded to 1024, got %#v", size.Initializer)
+	}
+	if lit, ok := large.Initializer.(*domain.LiteralExpr); !ok || lit.Value != true {
+		t.Errorf("Expected large to be folded to true, got %#v", large.Initializer)
+	}
+	if buffer.Size != 512 || buffer.SizeExpr != nil {
+		t.Errorf("Expected the array size to be resolved to 512, got %#v", buffer)
+	}
+
+	tests := []struct {
+		name     string
+		stmt     domain.Statement
+		expected string
+	}{
+		{"assignment", &domain.AssignStmt{Target: &domain.IdentifierExpr{Name: "N"}, Value: &domain.LiteralExpr{Value: int64(1)}},
+			"cannot assign to constant 'N'"},
+		{"variable initializer", &domain.VarDeclStmt{Name: "copy", Type_: intType, Constant: true, Initializer: &domain.IdentifierExpr{Name: "buffer"}},
+			"invalid initializer for constant 'copy'"},
+		{"negative size", &domain.VarDeclStmt{Name: "empty", Type_: &domain.ArrayType{ElementType: intType, SizeExpr: &domain.UnaryExpr{
+			Operator: domain.Neg, Operand: &domain.IdentifierExpr{Name: "N"}}}},
+			"invalid array size -1024"},
+		{"float size", &domain.VarDeclStmt{Name: "odd", Type_: &domain.ArrayType{ElementType: intType, SizeExpr: &domain.LiteralExpr{Value: 1.5}}},
+			"array size must be an integer"},
+		{"array constant", &domain.VarDeclStmt{Name: "table", Type_: buffer, Constant: true, Initializer: &domain.LiteralExpr{Value: int64(0)}},
+			"constant 'table' cannot have type [512]int"},
+	}
+	for _, test := range tests {
+		errorReporter.Clear()
+		symbolTable.EnterScope()
+		symbolTable.DeclareSymbol("buffer", buffer, interfaces.VariableSymbol, domain.SourceRange{})
+		if err := test.stmt.Accept(analyzer); err != nil {
+			t.Fatalf("%s: analysis failed: %v", test.name, err)
+		}
+		symbolTable.ExitScope()
+		if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, test.expected) {
+			t.Errorf("%s: expected error %q, got %v", test.name, test.expected, errorReporter.GetErrors())
+		}
+	}
+}
+
+// TestAnalyzer_InferredVariableTypes tests variables that take the type of their initializer
+func TestAnalyzer_InferredVariableTypes(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(domain.NewTypeRegistry())
+	analyzer.SetErrorReporter(errorReporter)
+	symbolTable.DeclareSymbol("reset", &domain.FunctionType{ReturnType: domain.NewVoidType()}, interfaces.FunctionSymbol, domain.SourceRange{})
+
+	u8Type := &domain.BasicType{Kind: domain.UInt8Type}
+	tests := []struct {
+		initializer domain.Expression
+		expected    domain.Type
+	}{
+		{&domain.LiteralExpr{Value: int64(1)}, &domain.BasicType{Kind: domain.IntType}},
+		{&domain.LiteralExpr{Value: 1.5}, &domain.BasicType{Kind: domain.FloatType}},
+		{&domain.CastExpr{Target: u8Type, Value: &domain.LiteralExpr{Value: int64(7)}}, u8Type},
+		{&domain.BinaryExpr{Left: &domain.LiteralExpr{Value: "a"}, Operator: domain.Lt, Right: &domain.LiteralExpr{Value: "b"}}, &domain.BasicType{Kind: domain.BoolType}},
+	}
+	for _, test := range tests {
+		stmt := &domain.VarDeclStmt{Name: "v", Initializer: test.initializer}
+		symbolTable.EnterScope()
+		if err := stmt.Accept(analyzer); err != nil {
+			t.Fatalf("VisitVarDeclStmt failed: %v", err)
+		}
+		symbol, _ := symbolTable.LookupSymbol("v")
+		symbolTable.ExitScope()
+		if stmt.Type_ == nil || !stmt.Type_.Equals(test.expected) || !symbol.Type.Equals(test.expected) {
+			t.Errorf("Expected v to be inferred as %s, got %v", test.expected, stmt.Type_)
+		}
+	}
+	if errorReporter.HasErrors() {
+		t.Fatalf("Expected no errors, got %v", errorReporter.GetErrors())
+	}
+
+	// Initializers without a value type are reported
+	invalid := []struct {
+		initializer domain.Expression
+		expected    string
+	}{
+		{&domain.CallExpr{Function: &domain.IdentifierExpr{Name: "reset"}}, "cannot infer type of 'v': the initializer does not return a value"},
+		{&domain.IdentifierExpr{Name: "missing"}, "cannot infer type of 'v': the initializer is invalid"},
+		{&domain.IdentifierExpr{Name: "reset"}, "cannot infer type of 'v': functions are not values"},
+	}
+	for _, test := range invalid {
+		errorReporter.Clear()
+		stmt := &domain.VarDeclStmt{Name: "v", Initializer: test.initializer}
+		symbolTable.EnterScope()
+		if err := stmt.Accept(analyzer); err != nil {
+			t.Fatalf("VisitVarDeclStmt failed: %v", err)
+		}
+		symbolTable.ExitScope()
+		errors := errorReporter.GetErrors()
+		if len(errors) == 0 || errors[len(errors)-1].Message != test.expected {
+			t.Errorf("Expected error %q, got %v", test.expected, errors)
+		}
+		if _, ok := stmt.Type_.(*domain.TypeError); !ok {
+			t.Errorf("Expected an error type for v, got %v", stmt.Type_)
+		}
+	}
+}
+
+// TestAnalyzer_Float32Constants tests float literal adaptation to f32
+func TestAnalyzer_Float32Constants(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	typeRegistry := domain.NewTypeRegistry()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(typeRegistry)
+	analyzer.SetErrorReporter(errorReporter)
+
+	f32Type := &domain.BasicType{Kind: domain.Float32Type}
+
+	literal := &domain.LiteralExpr{Value: 1.5}
+	analyzer.VisitVarDeclStmt(&domain.VarDeclStmt{Name: "x", Type_: f32Type, Initializer: literal})
+	if errorReporter.HasErrors() {
+		t.Errorf("Expected no errors, got %v", errorReporter.GetErrors())
+	}
+	if !literal.GetType().Equals(f32Type) {
+		t.Errorf("Expected literal type f32, got %s", literal.GetType())
+	}
+
+	analyzer.VisitVarDeclStmt(&domain.VarDeclStmt{
+		Name:        "huge",
+		Type_:       f32Type,
+		Initializer: &domain.LiteralExpr{Value: 1e40},
+	})
+	if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, "overflows f32") {
+		t.Errorf("Expected overflow error, got %v", errorReporter.GetErrors())
+	}
+
+	// Integer literals do not silently become floats
+	errorReporter.Clear()
+	analyzer.VisitVarDeclStmt(&domain.VarDeclStmt{
+		Name:        "y",
+		Type_:       f32Type,
+		Initializer: &domain.LiteralExpr{Value: int64(1)},
+	})
+	if !errorReporter.HasErrors() {
+		t.Error("Expected type mismatch assigning int literal to f32")
+	}
+}
+
+// TestAnalyzer_VisitCastExpr tests explicit conversion checking
+func TestAnalyzer_VisitCastExpr(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	typeRegistry := domain.NewTypeRegistry()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(typeRegistry)
+	analyzer.SetErrorReporter(errorReporter)
+
+	cast := &domain.CastExpr{Target: domain.NewFloatType(), Value: &domain.LiteralExpr{Value: int64(3)}}
+	if err := analyzer.VisitCastExpr(cast); err != nil {
+		t.Fatalf("VisitCastExpr failed: %v", err)
+	}
+	if errorReporter.HasErrors() || !cast.GetType().Equals(domain.NewFloatType()) {
+		t.Errorf("Expected float result without errors, got %s and %v", cast.GetType(), errorReporter.GetErrors())
+	}
+
+	invalid := &domain.CastExpr{Target: domain.NewIntType(), Value: &domain.LiteralExpr{Value: "\"42\""}}
+	analyzer.VisitCastExpr(invalid)
+	if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, "cannot convert string to int") {
+		t.Errorf("Expected conversion error, got %v", errorReporter.GetErrors())
+	}
+}
+
+// TestAnalyzer_StringOperators tests string concatenation, comparison and len
+func TestAnalyzer_StringOperators(t *testing.T) {
+	analyzer := NewAnalyzer()
+	symbolTable := infrastructure.NewSymbolTable()
+	typeRegistry := domain.NewTypeRegistry()
+	errorReporter := &MockErrorReporter{}
+
+	analyzer.SetSymbolTable(symbolTable)
+	analyzer.SetTypeRegistry(typeRegistry)
+	analyzer.SetErrorReporter(errorReporter)
+	if err := analyzer.initializeBuiltinFunctions(); err != nil {
+		t.Fatalf("initializeBuiltinFunctions failed: %v", err)
+	}
+
+	concat := &domain.BinaryExpr{
+		Left:     &domain.LiteralExpr{Value: "\"a\""},
+		Operator: domain.Add,
+		Right:    &domain.LiteralExpr{Value: "\"b\""},
+	}
+	analyzer.VisitBinaryExpr(concat)
+	if !concat.GetType().Equals(domain.NewStringType()) {
+		t.Errorf("Expected string concatenation to be a string, got %s", concat.GetType())
+	}
+
+	compare := &domain.BinaryExpr{
+		Left:     &domain.LiteralExpr{Value: "\"a\""},
+		Operator: domain.Ge,
+		Right:    &domain.LiteralExpr{Value: "\"b\""},
+	}
+	analyzer.VisitBinaryExpr(compare)
+	if !compare.GetType().Equals(domain.NewBoolType()) {
+		t.Errorf("Expected string comparison to be a bool, got %s", compare.GetType())
+	}
+
+	length := &domain.CallExpr{
+		Function: &domain.IdentifierExpr{Name: "len"},
+		Args:     []domain.Expression{concat},
+	}
+	analyzer.VisitCallExpr(length)
+	if errorReporter.HasErrors() {
+		t.Fatalf("Expected no errors, got %v", errorReporter.GetErrors())
+	}
+	if !length.GetType().Equals(domain.NewIntType()) {
+		t.Errorf("Expected len to return int, got %s", length.GetType())
+	}
+
+	invalid := &domain.CallExpr{
+		Function: &domain.IdentifierExpr{Name: "len"},
+		Args:     []domain.Expression{&domain.LiteralExpr{Value: int64(3)}},
+	}
+	analyzer.VisitCallExpr(invalid)
+	if !errorReporter.HasErrors() || !strings.Contains(errorReporter.GetErrors()[0].Message, "cannot take len of int") {
+		t.Errorf("Expected len error, got %v", errorReporter.GetErrors())
+	}
+}
+
 // TestAnalyzer_UnaryExpressionTypeValidation tests unary expression type checking
 func TestAnalyzer_UnaryExpressionTypeValidation(t *testing.T) {
 	analyzer := NewAnalyzer()
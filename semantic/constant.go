@@ -0,0 +1,318 @@
+package semantic
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sokoide/llvm5/internal/domain"
+	"github.com/sokoide/llvm5/internal/interfaces"
+)
+
+// evaluateConstant computes the value of an analyzed expression at compile
+// time. Integers are int64 values wrapped to the width of their type, as the
+// generated code would compute them; floats are float64, and bools and strings
+// keep their Go types. Expressions that need the program to run, such as
+// variables and calls, are reported as errors.
+func (a *Analyzer) evaluateConstant(expr domain.Expression) (interface{}, error) {
+	switch e := expr.(type) {
+	case *domain.LiteralExpr:
+		if domain.IsIntegerType(e.GetType()) {
+			if value, ok := e.Value.(int64); ok {
+				return wrapConstant(value, e.GetType()), nil
+			}
+		}
+		return e.Value, nil
+	case *domain.UnaryExpr:
+		operand, err := a.evaluateConstant(e.Operand)
+		if err != nil {
+			return nil, err
+		}
+		return evaluateUnaryConstant(e.Operator, operand, e.GetType())
+	case *domain.BinaryExpr:
+		left, err := a.evaluateConstant(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := a.evaluateConstant(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return evaluateBinaryConstant(e.Operator, left, right, e.Left.GetType())
+	case *domain.CastExpr:
+		value, err := a.evaluateConstant(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return convertConstant(value, e.Value.GetType(), e.Target)
+	case *domain.IdentifierExpr:
+		if symbol, found := a.symbolTable.LookupSymbol(e.Name); found && symbol.Kind == interfaces.ConstantSymbol {
+			if value, ok := a.constants[symbol]; ok {
+				return value, nil
+			}
+			return nil, fmt.Errorf("constant %s has no valid value", e.Name)
+		}
+		return nil, fmt.Errorf("%s is not a constant", e.Name)
+	case *domain.CallExpr:
+		return nil, fmt.Errorf("function calls are not constant")
+	}
+	return nil, fmt.Errorf("expression is not constant")
+}
+
+// constantLiteral returns a literal holding the value of a constant expression
+func (a *Analyzer) constantLiteral(expr domain.Expression) (*domain.LiteralExpr, error) {
+	value, err := a.evaluateConstant(expr)
+	if err != nil {
+		return nil, err
+	}
+	literal := &domain.LiteralExpr{BaseNode: domain.BaseNode{Location: expr.GetLocation()}, Value: value}
+	literal.SetType(expr.GetType())
+	return literal, nil
+}
+
+// wrapConstant truncates an integer to the width of its type, sign-extending
+// signed values and zero-extending unsigned ones
+func wrapConstant(value int64, t domain.Type) int64 {
+	bits := uint(t.GetSize() * 8)
+	if bits >= 64 {
+		return value
+	}
+	if domain.IsUnsignedType(t) {
+		return value & (int64(1)<<bits - 1)
+	}
+	shift := 64 - bits
+	return value << shift >> shift
+}
+
+// roundConstant rounds a float to the precision of its type
+func roundConstant(value float64, t domain.Type) float64 {
+	if basic, ok := t.(*domain.BasicType); ok && basic.Kind == domain.Float32Type {
+		return float64(float32(value))
+	}
+	return value
+}
+
+func evaluateUnaryConstant(op domain.UnaryOperator, operand interface{}, t domain.Type) (interface{}, error) {
+	switch value := operand.(type) {
+	case int64:
+		switch op {
+		case domain.Neg:
+			return wrapConstant(-value, t), nil
+		case domain.BitNot:
+			return wrapConstant(^value, t), nil
+		}
+	case float64:
+		if op == domain.Neg {
+			return -value, nil
+		}
+	case bool:
+		if op == domain.Not {
+			return !value, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid constant operation %s%v", op, operand)
+}
+
+func evaluateBinaryConstant(op domain.BinaryOperator, left, right interface{}, t domain.Type) (interface{}, error) {
+	switch l := left.(type) {
+	case int64:
+		r, ok := right.(int64)
+		if !ok {
+			break
+		}
+		if (op == domain.Shl || op == domain.Shr) && (r < 0 || r >= int64(t.GetSize()*8)) {
+			return nil, fmt.Errorf("invalid shift count %d in constant expression", r)
+		}
+		if domain.IsUnsignedType(t) {
+			return evaluateUnsignedConstant(op, uint64(l), uint64(r), t)
+		}
+		switch op {
+		case domain.Add:
+			return wrapConstant(l+r, t), nil
+		case domain.Sub:
+			return wrapConstant(l-r, t), nil
+		case domain.Mul:
+			return wrapConstant(l*r, t), nil
+		case domain.Div, domain.Mod:
+			if r == 0 {
+				return nil, fmt.Errorf("division by zero in constant expression")
+			}
+			if op == domain.Div {
+				return wrapConstant(l/r, t), nil
+			}
+			return wrapConstant(l%r, t), nil
+		case domain.BitAnd:
+			return l & r, nil
+		case domain.BitOr:
+			return l | r, nil
+		case domain.BitXor:
+			return l ^ r, nil
+		case domain.Shl:
+			return wrapConstant(l<<r, t), nil
+		case domain.Shr:
+			return l >> r, nil
+		}
+		return compareConstants(op, compareOrdered(l, r))
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			break
+		}
+		switch op {
+		case domain.Add:
+			return roundConstant(l+r, t), nil
+		case domain.Sub:
+			return roundConstant(l-r, t), nil
+		case domain.Mul:
+			return roundConstant(l*r, t), nil
+		case domain.Div:
+			return roundConstant(l/r, t), nil
+		case domain.Mod:
+			return roundConstant(math.Mod(l, r), t), nil
+		}
+		if math.IsNaN(l) || math.IsNaN(r) {
+			// NaN is unordered, so only != holds
+			return op == domain.Ne, nil
+		}
+		return compareConstants(op, compareOrdered(l, r))
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			break
+		}
+		if op == domain.Add {
+			return l + r, nil
+		}
+		return compareConstants(op, compareOrdered(l, r))
+	case bool:
+		r, ok := right.(bool)
+		if !ok {
+			break
+		}
+		switch op {
+		case domain.And:
+			return l && r, nil
+		case domain.Or:
+			return l || r, nil
+		case domain.Eq:
+			return l == r, nil
+		case domain.Ne:
+			return l != r, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid constant operation %v %s %v", left, op, right)
+}
+
+func evaluateUnsignedConstant(op domain.BinaryOperator, l, r uint64, t domain.Type) (interface{}, error) {
+	switch op {
+	case domain.Add:
+		return wrapConstant(int64(l+r), t), nil
+	case domain.Sub:
+		return wrapConstant(int64(l-r), t), nil
+	case domain.Mul:
+		return wrapConstant(int64(l*r), t), nil
+	case domain.Div, domain.Mod:
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero in constant expression")
+		}
+		if op == domain.Div {
+			return int64(l / r), nil
+		}
+		return int64(l % r), nil
+	case domain.BitAnd:
+		return int64(l & r), nil
+	case domain.BitOr:
+		return int64(l | r), nil
+	case domain.BitXor:
+		return int64(l ^ r), nil
+	case domain.Shl:
+		return wrapConstant(int64(l<<r), t), nil
+	case domain.Shr:
+		return int64(l >> r), nil
+	}
+	return compareConstants(op, compareOrdered(l, r))
+}
+
+// compareOrdered returns -1, 0 or 1 as l is less than, equal to or greater than r
+func compareOrdered[T int64 | uint64 | float64 | string](l, r T) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	}
+	return 0
+}
+
+// compareConstants applies a comparison operator to the result of compareOrdered
+func compareConstants(op domain.BinaryOperator, cmp int) (interface{}, error) {
+	switch op {
+	case domain.Eq:
+		return cmp == 0, nil
+	case domain.Ne:
+		return cmp != 0, nil
+	case domain.Lt:
+		return cmp < 0, nil
+	case domain.Le:
+		return cmp <= 0, nil
+	case domain.Gt:
+		return cmp > 0, nil
+	case domain.Ge:
+		return cmp >= 0, nil
+	}
+	return nil, fmt.Errorf("invalid constant operator %s", op)
+}
+
+// convertConstant applies an explicit conversion to a constant. Conversions
+// to string allocate at run time and are not constant.
+func convertConstant(value interface{}, from, to domain.Type) (interface{}, error) {
+	if from.Equals(to) {
+		return value, nil
+	}
+	toBasic, ok := to.(*domain.BasicType)
+	if !ok {
+		return nil, fmt.Errorf("cannot convert constant to %s", to)
+	}
+
+	switch {
+	case toBasic.Kind == domain.BoolType:
+		switch v := value.(type) {
+		case int64:
+			return v != 0, nil
+		case float64:
+			return v != 0, nil
+		}
+	case toBasic.IsInteger():
+		switch v := value.(type) {
+		case int64:
+			return wrapConstant(v, to), nil
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case float64:
+			// Out of range float to integer conversions have no defined result
+			truncated := math.Trunc(v)
+			if math.IsNaN(truncated) || !domain.IntegerFits(int64(truncated), to) || float64(int64(truncated)) != truncated {
+				return nil, fmt.Errorf("constant %g overflows %s", v, to)
+			}
+			return int64(truncated), nil
+		}
+	case toBasic.IsFloat():
+		switch v := value.(type) {
+		case int64:
+			if domain.IsUnsignedType(from) {
+				return roundConstant(float64(uint64(v)), to), nil
+			}
+			return roundConstant(float64(v), to), nil
+		case float64:
+			return roundConstant(v, to), nil
+		case bool:
+			if v {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		}
+	}
+	return nil, fmt.Errorf("conversion to %s is not constant", to)
+}
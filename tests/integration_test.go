@@ -4,7 +4,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -352,6 +354,168 @@ int main() {
 	_ = err
 }
 
+func TestCompileWithDebugInfo(t *testing.T) {
+	sourceCode := `
+func add(a int, b int) -> int {
+    var sum int = a + b;
+    return sum;
+}
+
+func main() -> int {
+    var total int = 0;
+    for (var i int = 0; i < 3; i = i + 1;) {
+        total = add(total, i);
+    }
+    return total;
+}
+`
+
+	tempFile := createTempFile(t, "debug.sl", sourceCode)
+	defer os.Remove(tempFile)
+
+	config := application.CompilerConfig{
+		UseMockComponents: false,
+		MemoryManagerType: application.PooledMemoryManager,
+		ErrorReporterType: application.ConsoleErrorReporter,
+		CompilationOptions: domain.CompilationOptions{
+			OptimizationLevel: 0,
+			DebugInfo:         true,
+			TargetTriple:      "x86_64-pc-linux-gnu",
+			OutputPath:        "",
+			WarningsAsErrors:  false,
+		},
+		ErrorOutput: os.Stderr,
+		Verbose:     false,
+	}
+
+	factory := application.NewCompilerFactory(config)
+	pipeline := factory.CreateCompilerPipeline()
+
+	input, err := os.Open(tempFile)
+	if err != nil {
+		t.Fatalf("Failed to open input file: %v", err)
+	}
+	defer input.Close()
+
+	outputFile := createTempFile(t, "debug.ll", "")
+	defer os.Remove(outputFile)
+
+	output, err := os.Create(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to create output file: %v", err)
+	}
+	defer output.Close()
+
+	if err := pipeline.Compile(tempFile, input, output); err != nil {
+		t.Fatalf("Compilation failed: %v", err)
+	}
+
+	outputContent, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	outputStr := string(outputContent)
+
+	expected := []string{
+		"!llvm.dbg.cu = !{",
+		`!DIFile(filename: "` + filepath.Base(tempFile) + `", directory: "` + filepath.Dir(tempFile) + `")`,
+		"distinct !DICompileUnit(language: DW_LANG_C99",
+		`distinct !DISubprogram(name: "add"`,
+		`distinct !DISubprogram(name: "main"`,
+		`!DILocalVariable(name: "a", arg: 1`,
+		`!DILocalVariable(name: "sum"`,
+		`!DILocalVariable(name: "i"`,
+		"distinct !DILexicalBlock(",
+		"call void @llvm.dbg.value(metadata i64 %a, metadata !",
+		"call void @llvm.dbg.value(metadata i64 %i.0, metadata !",
+		`!{i32 2, !"Debug Info Version", i32 3}`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(outputStr, e) {
+			t.Errorf("Expected %q in output, got: %s", e, outputStr)
+		}
+	}
+	if !strings.Contains(outputStr, "!DILocation(line: 3, column: 5") {
+		t.Errorf("Expected a location for the declaration of sum, got: %s", outputStr)
+	}
+
+	validateWithLLVM(t, outputFile)
+}
+
+func TestCompileWithOptimization(t *testing.T) {
+	sourceCode := `
+func add(a int, b int) -> int {
+    return a + b;
+}
+
+func main() -> int {
+    var total int = add(2, 3);
+    if (total > 10) {
+        print(total);
+    }
+    return total;
+}
+`
+
+	tempFile := createTempFile(t, "optimize.sl", sourceCode)
+	defer os.Remove(tempFile)
+
+	config := application.CompilerConfig{
+		UseMockComponents: false,
+		MemoryManagerType: application.PooledMemoryManager,
+		ErrorReporterType: application.ConsoleErrorReporter,
+		CompilationOptions: domain.CompilationOptions{
+			OptimizationLevel: 2,
+			DebugInfo:         false,
+			TargetTriple:      "x86_64-pc-linux-gnu",
+			OutputPath:        "",
+			WarningsAsErrors:  false,
+		},
+		ErrorOutput: os.Stderr,
+		Verbose:     false,
+	}
+
+	factory := application.NewCompilerFactory(config)
+	pipeline := factory.CreateCompilerPipeline()
+
+	input, err := os.Open(tempFile)
+	if err != nil {
+		t.Fatalf("Failed to open input file: %v", err)
+	}
+	defer input.Close()
+
+	outputFile := createTempFile(t, "optimize.ll", "")
+	defer os.Remove(outputFile)
+
+	output, err := os.Create(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to create output file: %v", err)
+	}
+	defer output.Close()
+
+	if err := pipeline.Compile(tempFile, input, output); err != nil {
+		t.Fatalf("Compilation failed: %v", err)
+	}
+
+	outputContent, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read output file: %v", err)
+	}
+	outputStr := string(outputContent)
+
+	// add is inlined and folded, and the branch that cannot be taken is removed
+	if !strings.Contains(outputStr, "ret i32 5") {
+		t.Errorf("Expected main to return the folded constant, got: %s", outputStr)
+	}
+	for _, unexpected := range []string{"call i64 @add", "call void @sl_print_int", "alloca"} {
+		if strings.Contains(outputStr, unexpected) {
+			t.Errorf("Expected %q to be optimized away, got: %s", unexpected, outputStr)
+		}
+	}
+
+	validateWithLLVM(t, outputFile)
+}
+
 // TestExamplesDirectory tests all .sl files in the examples directory
 func TestExamplesDirectory(t *testing.T) {
 	// Find all .sl files in examples directory
@@ -485,9 +649,10 @@ func validateWithLLVM(t *testing.T, llFile string) {
 	// Check if llvm-as is available
 	if _, err := exec.LookPath("llvm-as"); err == nil {
 		// Try to assemble the .ll file
-		cmd := exec.Command("llvm-as", "-o", "/dev/null", llFile)
-		if err := cmd.Run(); err != nil {
-			t.Errorf("Generated .ll file failed LLVM assembly validation: %v", err)
+		args := append(opaquePointerArgs(), "-o", "/dev/null", llFile)
+		cmd := exec.Command("llvm-as", args...)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("Generated .ll file failed LLVM assembly validation: %v\n%s", err, output)
 		} else {
 			t.Logf("Generated .ll file passed LLVM assembly validation")
 		}
@@ -496,6 +661,24 @@ func validateWithLLVM(t *testing.T, llFile string) {
 	}
 }
 
+// opaquePointerArgs returns the flags llvm-as needs to read the generated IR.
+// Pointers are always opaque (ptr), which is the default from LLVM 15 on and
+// has to be enabled explicitly before that.
+func opaquePointerArgs() []string {
+	output, err := exec.Command("llvm-as", "--version").Output()
+	if err != nil {
+		return nil
+	}
+	match := regexp.MustCompile(`LLVM version (\d+)`).FindSubmatch(output)
+	if match == nil {
+		return nil
+	}
+	if major, _ := strconv.Atoi(string(match[1])); major < 15 {
+		return []string{"-opaque-pointers"}
+	}
+	return nil
+}
+
 // findExampleFiles finds all .sl files in the examples directory
 func findExampleFiles() ([]string, error) {
 	var slFiles []string